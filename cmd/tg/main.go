@@ -4,9 +4,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -78,6 +86,10 @@ func main() {
 					Name:  "outSwagger",
 					Usage: "path to output swagger file",
 				},
+				&cli.StringFlag{
+					Name:  "openapi",
+					Usage: "version of OpenAPI of swagger file: 3.0 or 3.1",
+				},
 				&cli.StringFlag{
 					Name:  "redoc",
 					Usage: "path to output redoc bundle",
@@ -90,9 +102,13 @@ func main() {
 					Name:  "zipkin",
 					Usage: "use Zipkin tracer",
 				},
-				&cli.BoolFlag{
-					Name:  "mongo",
-					Usage: "enable mongo support",
+				&cli.StringFlag{
+					Name:  "tracer",
+					Usage: "tracer backend: opentracing (Jaeger and Zipkin), jaeger, zipkin, otel or none",
+				},
+				&cli.StringFlag{
+					Name:  "engine",
+					Usage: "HTTP engine of server: fasthttp or nethttp",
 				},
 				&cli.StringFlag{
 					Name:  "implements",
@@ -102,11 +118,47 @@ func main() {
 					Name:  "tests",
 					Usage: "path to generate tests",
 				},
+				&cli.BoolFlag{
+					Name:  "watch",
+					Usage: "regenerate transport on services changes",
+				},
+				&cli.DurationFlag{
+					Name:  "interval",
+					Value: time.Second,
+					Usage: "watch polling and debounce interval",
+				},
+				&cli.BoolFlag{
+					Name:  "strict",
+					Usage: "validate annotations before generation and fail on any error",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "do not write files, fail if generated files are out of date",
+				},
+				&cli.BoolFlag{
+					Name:  "diff",
+					Usage: "do not write files, print unified diff and fail if generated files are out of date",
+				},
 			},
 
 			UsageText:   "tg transport",
 			Description: "generate services transport layer by interfaces",
 		},
+		{
+			Name:   "check",
+			Usage:  "validate annotations of interfaces in 'service' package",
+			Action: cmdCheck,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "services",
+					Value: "./pkg/someService/service",
+					Usage: "path to services package",
+				},
+			},
+
+			UsageText:   "tg check --services ./pkg/someService/service",
+			Description: "check annotations against known tags and methods signatures, print file:line diagnostics",
+		},
 		{
 			Name:   "client",
 			Usage:  "generate services clients by interfaces in 'service' package",
@@ -122,11 +174,39 @@ func main() {
 					Value: "./pkg/clients",
 					Usage: "path to output clients",
 				},
+				&cli.StringFlag{
+					Name:  "tracer",
+					Usage: "tracer: opentracing, otel or none",
+				},
+				&cli.StringFlag{
+					Name:  "engine",
+					Usage: "HTTP engine of clients: fasthttp or nethttp",
+				},
 			},
 
 			UsageText:   "tg client --services ./pkg/someService/service",
 			Description: "generate services transport layer by interfaces",
 		},
+		{
+			Name:   "ts",
+			Usage:  "generate typescript types and clients by interfaces in 'service' package",
+			Action: cmdTypeScript,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "services",
+					Value: "./pkg/someService/service",
+					Usage: "path to services package",
+				},
+				&cli.StringFlag{
+					Name:  "outPath",
+					Value: "./ts",
+					Usage: "path to output typescript files",
+				},
+			},
+
+			UsageText:   "tg ts --services ./pkg/someService/service --outPath ./web/api",
+			Description: "generate typescript types and fetch based clients by interfaces",
+		},
 		{
 			Name:   "swagger",
 			Usage:  "generate swagger documentation by interfaces in 'service' package",
@@ -145,15 +225,50 @@ func main() {
 					Name:  "iface",
 					Usage: "interfaces included to swagger",
 				},
+				&cli.StringSliceFlag{
+					Name:  "audience",
+					Usage: "render document for audience, 'name' or 'name=path to output file'",
+				},
 				&cli.StringFlag{
 					Name:  "redoc",
 					Usage: "path to output redoc bundle",
 				},
+				&cli.BoolFlag{
+					Name:  "json",
+					Usage: "save swagger in JSON format",
+				},
+				&cli.StringFlag{
+					Name:  "openapi",
+					Usage: "version of OpenAPI: 3.0 or 3.1",
+				},
 			},
 
-			UsageText:   "tg swagger --iface firstIface --iface secondIface",
+			UsageText:   "tg swagger --iface firstIface --iface secondIface --audience public --audience internal=./internal.yaml",
 			Description: "generate swagger documentation by interfaces",
 		},
+		{
+			Name:   "openrpc",
+			Usage:  "generate OpenRPC specification by jsonRPC interfaces in 'service' package",
+			Action: cmdOpenRPC,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "services",
+					Value: "./pkg/someService/service",
+					Usage: "path to services package",
+				},
+				&cli.StringFlag{
+					Name:  "outFile",
+					Usage: "path to output file",
+				},
+				&cli.StringSliceFlag{
+					Name:  "iface",
+					Usage: "interfaces included to specification",
+				},
+			},
+
+			UsageText:   "tg openrpc --iface firstIface --outFile ./openrpc.json",
+			Description: "generate OpenRPC specification by jsonRPC interfaces",
+		},
 	}
 
 	err := app.Run(os.Args)
@@ -187,13 +302,29 @@ func cmdClient(c *cli.Context) (err error) {
 	}()
 
 	var tr generator.Transport
-	if tr, err = generator.NewTransport(log, c.String("services")); err != nil {
+	if tr, err = generator.NewTransport(log, c.String("services"), tracerOption(c), generator.WithEngine(c.String("engine"))); err != nil {
 		return
 	}
 
 	return tr.RenderClient(c.String("outPath"))
 }
 
+func cmdTypeScript(c *cli.Context) (err error) {
+
+	defer func() {
+		if err == nil {
+			log.Info("done")
+		}
+	}()
+
+	var tr generator.Transport
+	if tr, err = generator.NewTransport(log, c.String("services")); err != nil {
+		return
+	}
+
+	return tr.RenderTypeScript(c.String("outPath"))
+}
+
 func cmdTransport(c *cli.Context) (err error) {
 
 	defer func() {
@@ -205,11 +336,9 @@ func cmdTransport(c *cli.Context) (err error) {
 	opts := []generator.Option{
 		generator.WithTests(c.String("tests")),
 		generator.WithImplements(c.String("implements")),
-	}
-
-	var tr generator.Transport
-	if tr, err = generator.NewTransport(log, c.String("services"), opts...); err != nil {
-		return
+		tracerOption(c),
+		generator.WithOpenAPI(c.String("openapi")),
+		generator.WithEngine(c.String("engine")),
 	}
 
 	outPath, _ := path.Split(c.String("services"))
@@ -219,19 +348,136 @@ func cmdTransport(c *cli.Context) (err error) {
 		outPath = c.String("out")
 	}
 
-	if err = tr.RenderServer(outPath); err != nil {
+	render := func(tr generator.Transport) (err error) {
+
+		if c.Bool("strict") {
+			if err = checkTransport(tr); err != nil {
+				return
+			}
+		}
+
+		if err = tr.RenderServer(outPath); err != nil {
+			return
+		}
+
+		if c.String("outSwagger") != "" {
+			err = tr.RenderSwagger(c.String("outSwagger"))
+		}
+		if c.String("redoc") != "" {
+			var output []byte
+			log.Infof("write to %s", c.String("redoc"))
+			if output, err = exec.Command("redoc-cli", "bundle", c.String("outSwagger"), "-o", c.String("redoc")).Output(); err != nil {
+				log.WithError(err).Error(string(output))
+			}
+		}
+		return
+	}
+
+	if c.Bool("dry-run") || c.Bool("diff") {
+
+		if c.Bool("watch") {
+			return errors.New("watch mode can not be combined with dry-run or diff")
+		}
+		// tests are not part of transport, do not touch them
+		var testsDir string
+		if testsDir, err = ioutil.TempDir("", "tg-tests"); err != nil {
+			return
+		}
+		defer os.RemoveAll(testsDir)
+		opts = append(opts, generator.WithTests(testsDir))
+		var tr generator.Transport
+		if tr, err = generator.NewTransport(log, c.String("services"), opts...); err != nil {
+			return
+		}
+		return diffTransport(c, tr, outPath)
+	}
+
+	if c.Bool("watch") {
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		log.Infof("watch %s", c.String("services"))
+		return generator.Watch(ctx, log, c.String("services"), c.Duration("interval"), render, opts...)
+	}
+
+	var tr generator.Transport
+	if tr, err = generator.NewTransport(log, c.String("services"), opts...); err != nil {
 		return
 	}
+	return render(tr)
+}
+
+func diffTransport(c *cli.Context, tr generator.Transport, outPath string) (err error) {
 
+	if c.Bool("strict") {
+		if err = checkTransport(tr); err != nil {
+			return
+		}
+	}
+
+	var diffs, swaggerDiffs []generator.FileDiff
+	if diffs, err = tr.DiffServer(outPath); err != nil {
+		return
+	}
 	if c.String("outSwagger") != "" {
-		err = tr.RenderSwagger(c.String("outSwagger"))
+		if swaggerDiffs, err = tr.DiffSwagger(c.String("outSwagger")); err != nil {
+			return
+		}
+		diffs = append(diffs, swaggerDiffs...)
 	}
-	if c.String("redoc") != "" {
-		var output []byte
-		log.Infof("write to %s", c.String("redoc"))
-		if output, err = exec.Command("redoc-cli", "bundle", c.String("outSwagger"), "-o", c.String("redoc")).Output(); err != nil {
-			log.WithError(err).Error(string(output))
+	for _, diff := range diffs {
+		if c.Bool("diff") {
+			fmt.Print(diff.Diff)
+			continue
 		}
+		fmt.Println(diff.Path)
+	}
+	if len(diffs) != 0 {
+		return fmt.Errorf("%d generated files are out of date, run 'tg transport'", len(diffs))
+	}
+	log.Info("generated files are up to date")
+	return
+}
+
+// tracerOption returns tracer selected by flags, empty tracer is taken from package annotation.
+func tracerOption(c *cli.Context) generator.Option {
+
+	tracer := c.String("tracer")
+	switch {
+	case c.Bool("jaeger") && c.Bool("zipkin"):
+		tracer = generator.TracerOpentracing
+	case c.Bool("jaeger"):
+		tracer = generator.TracerJaeger
+	case c.Bool("zipkin"):
+		tracer = generator.TracerZipkin
+	}
+	return generator.WithTracer(tracer)
+}
+
+func cmdCheck(c *cli.Context) (err error) {
+
+	var tr generator.Transport
+	if tr, err = generator.NewTransport(log, c.String("services")); err != nil {
+		return
+	}
+	if err = checkTransport(tr); err == nil {
+		log.Info("no errors found")
+	}
+	return
+}
+
+func checkTransport(tr generator.Transport) (err error) {
+
+	var diagnostics []generator.Diagnostic
+	if diagnostics, err = tr.Check(); err != nil {
+		return
+	}
+	for _, diagnostic := range diagnostics {
+		_, _ = fmt.Fprintln(os.Stderr, diagnostic)
+	}
+	if len(diagnostics) != 0 {
+		return fmt.Errorf("%d annotation errors found", len(diagnostics))
 	}
 	return
 }
@@ -245,23 +491,80 @@ func cmdSwagger(c *cli.Context) (err error) {
 	}()
 
 	var tr generator.Transport
-	if tr, err = generator.NewTransport(log, c.String("services")); err != nil {
+	if tr, err = generator.NewTransport(log, c.String("services"), generator.WithOpenAPI(c.String("openapi")), generator.WithSwaggerJSON(c.Bool("json"))); err != nil {
 		return
 	}
 
 	outPath := path.Join(c.String("services"), "swagger.yaml")
+	if c.Bool("json") {
+		outPath = path.Join(c.String("services"), "swagger.json")
+	}
 
 	if c.String("outFile") != "" {
 		outPath = c.String("outFile")
 	}
-	if err = tr.RenderSwagger(outPath); err == nil {
+	if len(c.StringSlice("audience")) == 0 {
+		return renderSwagger(outPath, c.String("redoc"), func(outFile string) error {
+			return tr.RenderSwagger(outFile, c.StringSlice("iface")...)
+		})
+	}
+	for _, audience := range c.StringSlice("audience") {
+
+		name, audiencePath := audience, withSuffix(outPath, audience)
+		if tokens := strings.SplitN(audience, "=", 2); len(tokens) == 2 {
+			name, audiencePath = tokens[0], tokens[1]
+		}
+		var redocPath string
 		if c.String("redoc") != "" {
+			redocPath = withSuffix(c.String("redoc"), name)
+		}
+		if err = renderSwagger(audiencePath, redocPath, func(outFile string) error {
+			return tr.RenderSwaggerAudience(outFile, name, c.StringSlice("iface")...)
+		}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func cmdOpenRPC(c *cli.Context) (err error) {
+
+	defer func() {
+		if err == nil {
+			log.Info("done")
+		}
+	}()
+
+	var tr generator.Transport
+	if tr, err = generator.NewTransport(log, c.String("services")); err != nil {
+		return
+	}
+
+	outPath := path.Join(c.String("services"), "openrpc.json")
+
+	if c.String("outFile") != "" {
+		outPath = c.String("outFile")
+	}
+	return tr.RenderOpenRPC(outPath, c.StringSlice("iface")...)
+}
+
+func renderSwagger(outPath, redocPath string, render func(outFile string) error) (err error) {
+
+	if err = render(outPath); err == nil {
+		if redocPath != "" {
 			var output []byte
-			log.Infof("write to %s", c.String("redoc"))
-			if output, err = exec.Command("redoc-cli", "bundle", outPath, "-o", c.String("redoc")).Output(); err != nil {
+			log.Infof("write to %s", redocPath)
+			if output, err = exec.Command("redoc-cli", "bundle", outPath, "-o", redocPath).Output(); err != nil {
 				log.WithError(err).Error(string(output))
 			}
 		}
 	}
 	return
 }
+
+// withSuffix returns path of file for audience, like 'swagger-public.yaml' for 'swagger.yaml'.
+func withSuffix(filePath, suffix string) string {
+
+	ext := filepath.Ext(filePath)
+	return strings.TrimSuffix(filePath, ext) + "-" + suffix + ext
+}
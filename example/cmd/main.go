@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -30,9 +32,17 @@ func main() {
 
 	srv := transport.New(log, services...).WithLog(log).WithTrace().TraceJaeger("example")
 
-	srv.ServeHTTP(":9000")
+	transport.ExitOnError(log, srv.ServeHTTP(":9000"), "serve http")
+	transport.ExitOnError(log, srv.ServeHealth(":9091"), "serve health")
 
 	<-shutdown
 
 	log.Info("start shutdown server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("shutdown server")
+	}
 }
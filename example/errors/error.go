@@ -30,6 +30,10 @@ func (e *ErrorType) Code() int {
 	return e.Status
 }
 
+func (e *ErrorType) JsonRPCCode() int {
+	return e.Status
+}
+
 func (e *ErrorType) Error() string {
 	return e.Err
 }
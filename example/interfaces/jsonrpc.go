@@ -10,5 +10,7 @@ type JsonRPC interface {
 	// @tg summary=`json RPC метод`
 	// @tg arg1.type=string
 	// @tg arg1.format=uuid
+	// @tg arg0.min=1 arg0.max=100 arg1.required
+	// @tg 400=github.com/seniorGolang/tg/example/errors:ErrorType
 	Test(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (ret1 int, ret2 string, err error)
 }
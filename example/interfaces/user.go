@@ -2,6 +2,8 @@
 // @tg title=`Example API`
 // @tg description=`A service which provide Example API`
 // @tg servers=`http://example.test`
+// @tg rpc-discover
+// @tg cors-origins=http://example.test cors-headers=Authorization,X-Client-Id cors-max-age=10m
 //go:generate tg transport --services . --out ../transport --outSwagger ../swagger.yaml
 package interfaces
 
@@ -14,7 +16,7 @@ import (
 // @tg http-prefix=api/v2
 // @tg http-server log trace metrics
 // общий код 400 для всех методов, кроме UploadFile
-// @tg 400=github.com/seniorGolang/tg/example/error:ErrorType
+// @tg 400=github.com/seniorGolang/tg/example/errors:ErrorType
 type User interface {
 
 	// @tg summary=`Данные пользователя`
@@ -22,17 +24,21 @@ type User interface {
 	// @tg http-method=GET
 	// @tg http-success=204
 	// @tg http-path=/user/info
+	// @tg timeout=5s
+	// @tg rate-limit=100/s limit-key=header:X-Client-Id
 	// @tg http-cookies=cookie|sessionCookie
 	// @tg http-headers=userAgent|User-Agent
-	// @tg 401=github.com/seniorGolang/tg/example/error:ErrorType
+	// @tg 401=github.com/seniorGolang/tg/example/errors:ErrorType
 	GetUser(ctx context.Context, cookie, userAgent string) (user *types.User, err error)
 
 	// @tg summary=`Загрузка аватара пользователя`
 	// @tg desc=`Загрузка файла`
 	// @tg http-method=POST
-	// @tg uploadTo=fileBytes
 	// @tg http-path=/user/file
 	// @tg http-upload=fileBytes|fileBytes
+	// @tg log-skip=fileBytes log-error-level=warn
+	// @tg auth=bearer|apiKey:X-Api-Key roles=admin
+	// @tg rate-limit=10/s burst=20 max-concurrent=4 limit-key=principal
 	// @tg 400=-
 	UploadFile(ctx context.Context, fileBytes []byte) (err error)
 
@@ -40,11 +46,13 @@ type User interface {
 	// @tg http-method=PATCH
 	// @tg http-path=/user/custom/response
 	// @tg http-response=github.com/seniorGolang/tg/example/implement:CustomResponseHandler
+	// @tg arg1.enum=asc,desc
 	CustomResponse(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (err error)
 
 	// @tg summary=`Метод полностью обрабатываемый кастомным хендлером`
 	// @tg http-method=DELETE
 	// @tg http-path=/user/custom
 	// @tg handler=github.com/seniorGolang/tg/example/implement:CustomHandler
+	// @tg compress=false
 	CustomHandler(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (err error)
 }
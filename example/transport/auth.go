@@ -0,0 +1,164 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// userValuePrincipal is key of principal in user values of request, which are keyed by strings only
+const userValuePrincipal = "tg.principal"
+
+type ctxKeyPrincipal struct{}
+
+var (
+	errUnauthorized = errors.New("unauthorized")
+	errForbidden    = errors.New("forbidden")
+)
+
+// Credentials of request, Token is bearer token or API key, Username and Password are set by basic scheme
+type Credentials struct {
+	Scheme   string
+	Token    string
+	Username string
+	Password string
+	Service  string
+	Method   string
+}
+
+type Principal interface {
+	// ID identifies principal, it is key of limiters annotated by limit-key=principal
+	ID() string
+	HasRole(role string) bool
+}
+
+// Authenticator is called before methods annotated by auth, error means that request is unauthorized
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials Credentials) (principal Principal, err error)
+}
+
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(srv *Server) {
+		srv.authenticator = authenticator
+	}
+}
+
+func PrincipalFromContext(ctx context.Context) (principal Principal, ok bool) {
+	if requestCtx, isRequest := ctx.(*fasthttp.RequestCtx); isRequest {
+		principal, ok = requestCtx.UserValue(userValuePrincipal).(Principal)
+		return
+	}
+	principal, ok = ctx.Value(ctxKeyPrincipal{}).(Principal)
+	return
+}
+
+func withPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, ctxKeyPrincipal{}, principal)
+}
+
+func authenticate(authenticator Authenticator, ctx context.Context, header func(key string) string, service, method string, schemes, roles []string) (principal Principal, err error) {
+
+	if authenticator == nil {
+		return nil, errUnauthorized
+	}
+	credentials, found := extractCredentials(header, schemes)
+	if !found {
+		return nil, errUnauthorized
+	}
+	credentials.Service = service
+	credentials.Method = method
+
+	if principal, err = authenticator.Authenticate(ctx, credentials); err != nil || principal == nil {
+		return nil, errUnauthorized
+	}
+	if len(roles) == 0 {
+		return
+	}
+	for _, role := range roles {
+		if principal.HasRole(role) {
+			return
+		}
+	}
+	return nil, errForbidden
+}
+
+func requestHeader(ctx *fasthttp.RequestCtx) func(string) string {
+	return func(key string) string {
+		return string(ctx.Request.Header.Peek(key))
+	}
+}
+
+func extractCredentials(header func(key string) string, schemes []string) (credentials Credentials, found bool) {
+
+	authorization := header(fasthttp.HeaderAuthorization)
+	for _, scheme := range schemes {
+
+		tokens := strings.SplitN(scheme, ":", 2)
+		switch tokens[0] {
+		case "bearer":
+			if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+				return Credentials{
+					Scheme: "bearer",
+					Token:  authorization[7:],
+				}, true
+			}
+		case "basic":
+			if len(authorization) > 6 && strings.EqualFold(authorization[:6], "Basic ") {
+				decoded, err := base64.StdEncoding.DecodeString(authorization[6:])
+				if err != nil {
+					continue
+				}
+				if pair := strings.SplitN(string(decoded), ":", 2); len(pair) == 2 {
+					return Credentials{
+						Password: pair[1],
+						Scheme:   "basic",
+						Username: pair[0],
+					}, true
+				}
+			}
+		case "apiKey":
+			if key := header(tokens[1]); key != "" {
+				return Credentials{
+					Scheme: "apiKey",
+					Token:  key,
+				}, true
+			}
+		}
+	}
+	return
+}
+
+func authHTTP(authenticator Authenticator, ctx *fasthttp.RequestCtx, service, method string, schemes, roles []string) (principal Principal, ok bool) {
+
+	principal, err := authenticate(authenticator, ctx, requestHeader(ctx), service, method, schemes, roles)
+	if errors.Is(err, errForbidden) {
+		ctx.Error(fasthttp.StatusMessage(fasthttp.StatusForbidden), fasthttp.StatusForbidden)
+		return nil, false
+	}
+	if err != nil {
+		ctx.Error(fasthttp.StatusMessage(fasthttp.StatusUnauthorized), fasthttp.StatusUnauthorized)
+		return nil, false
+	}
+	ctx.SetUserValue(userValuePrincipal, principal)
+	return principal, true
+}
+
+func authJsonRPC(methodContext context.Context, authenticator Authenticator, ctx *fasthttp.RequestCtx, service, method string, schemes, roles []string) (context.Context, error) {
+
+	principal, err := authenticate(authenticator, ctx, requestHeader(ctx), service, method, schemes, roles)
+	if err != nil {
+		return methodContext, err
+	}
+	return withPrincipal(methodContext, principal), nil
+}
+
+func authErrorCode(err error) int {
+	if errors.Is(err, errForbidden) {
+		return forbiddenError
+	}
+	return unauthorizedError
+}
@@ -0,0 +1,125 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/valyala/fasthttp"
+)
+
+const defaultCompressMinSize = 1024
+
+var compressEncodings = []string{"br", "gzip", "deflate"}
+
+// CompressMinSize sets size of response body, smaller responses are not compressed, negative size disables compression
+func CompressMinSize(size int) Option {
+	return func(srv *Server) {
+		srv.compressMinSize = size
+	}
+}
+
+func (srv *Server) compress(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		compressResponse(ctx, srv.compressMinSize)
+	}
+}
+
+func compressResponse(ctx *fasthttp.RequestCtx, minSize int) {
+
+	if minSize < 0 || ctx.Response.IsBodyStream() || len(ctx.Response.Header.Peek(fasthttp.HeaderContentEncoding)) != 0 {
+		return
+	}
+	ctx.Response.Header.Add(fasthttp.HeaderVary, fasthttp.HeaderAcceptEncoding)
+
+	body := ctx.Response.Body()
+	if len(body) < minSize || len(body) == 0 {
+		return
+	}
+	var compressed []byte
+	encoding := acceptedEncoding(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptEncoding)))
+	switch encoding {
+	case "br":
+		compressed = fasthttp.AppendBrotliBytes(nil, body)
+	case "gzip":
+		compressed = fasthttp.AppendGzipBytes(nil, body)
+	case "deflate":
+		compressed = fasthttp.AppendDeflateBytes(nil, body)
+	default:
+		return
+	}
+	if len(compressed) >= len(body) {
+		return
+	}
+	ctx.Response.SetBody(compressed)
+	ctx.Response.Header.Set(fasthttp.HeaderContentEncoding, encoding)
+}
+
+func acceptedEncoding(header string) (encoding string) {
+
+	weights := make(map[string]float64)
+	for _, item := range strings.Split(header, ",") {
+		tokens := strings.Split(item, ";")
+		weight := 1.0
+		for _, param := range tokens[1:] {
+			if param = strings.TrimSpace(param); strings.HasPrefix(param, "q=") {
+				weight, _ = strconv.ParseFloat(param[2:], 64)
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(tokens[0]))] = weight
+	}
+	var best float64
+	for _, name := range compressEncodings {
+		weight, found := weights[name]
+		if !found {
+			weight = weights["*"]
+		}
+		if weight > best {
+			encoding, best = name, weight
+		}
+	}
+	return
+}
+
+func decompressRequest(ctx *fasthttp.RequestCtx, maxSize int) (statusCode int, err error) {
+
+	encoding := strings.ToLower(strings.TrimSpace(string(ctx.Request.Header.Peek(fasthttp.HeaderContentEncoding))))
+	if encoding == "" || encoding == "identity" {
+		return
+	}
+	var reader io.Reader
+	body := bytes.NewReader(ctx.Request.Body())
+	switch encoding {
+	case "br":
+		reader = brotli.NewReader(body)
+	case "gzip":
+		if reader, err = gzip.NewReader(body); err != nil {
+			return fasthttp.StatusBadRequest, err
+		}
+	case "deflate":
+		if reader, err = zlib.NewReader(body); err != nil {
+			return fasthttp.StatusBadRequest, err
+		}
+	default:
+		return fasthttp.StatusUnsupportedMediaType, fmt.Errorf("unsupported content encoding '%s'", encoding)
+	}
+	var decoded []byte
+	if decoded, err = ioutil.ReadAll(io.LimitReader(reader, int64(maxSize)+1)); err != nil {
+		return fasthttp.StatusBadRequest, err
+	}
+	if len(decoded) > maxSize {
+		return fasthttp.StatusRequestEntityTooLarge, errors.New("decoded request body is too large")
+	}
+	ctx.Request.Header.Del(fasthttp.HeaderContentEncoding)
+	ctx.Request.SetBody(decoded)
+	return
+}
@@ -1,4 +1,118 @@
 // GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
 package transport
 
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
 const CtxCancelRequest = "ctxCancelRequest"
+
+type detachedContext struct {
+	context.Context
+	values context.Context
+}
+
+func (ctx detachedContext) Value(key interface{}) interface{} {
+	return ctx.values.Value(key)
+}
+
+// userValueRequestContext is key of request context in user values of request, which are keyed by strings only
+const userValueRequestContext = "tg.requestContext"
+
+// requestContext returns context of request served by ServeHTTP or ServeHTTPS, other requests get base context
+func requestContext(ctx *fasthttp.RequestCtx, base context.Context) context.Context {
+	if requestCtx, ok := ctx.UserValue(userValueRequestContext).(context.Context); ok {
+		return requestCtx
+	}
+	return base
+}
+
+// closeNotifier is connection, which cancels context of request when client closes it
+type closeNotifier interface {
+	notifyClose(cancel context.CancelFunc)
+}
+
+// closeNotifyListener wraps connections of HTTP server, so context of request is canceled when client closes connection
+type closeNotifyListener struct {
+	net.Listener
+}
+
+func (listener closeNotifyListener) Accept() (net.Conn, error) {
+	conn, err := listener.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	notifier := &closeNotifyConn{Conn: conn}
+	if _, ok := conn.(*tls.Conn); ok {
+		return closeNotifyTLSConn{closeNotifyConn: notifier}, nil
+	}
+	return notifier, nil
+}
+
+// closeNotifyConn reads connection in background while request is handled,
+// byte read meanwhile is returned to server by next Read
+type closeNotifyConn struct {
+	net.Conn
+	reading  chan error
+	buffered []byte
+	readErr  error
+}
+
+func (conn *closeNotifyConn) notifyClose(cancel context.CancelFunc) {
+	if conn.reading != nil || len(conn.buffered) != 0 || conn.readErr != nil {
+		return
+	}
+	// deadline of reading request must not end background read of long request,
+	// server sets deadline of connection again, when it waits for next request
+	_ = conn.Conn.SetReadDeadline(time.Time{})
+	reading := make(chan error, 1)
+	conn.reading = reading
+	go func() {
+		data := make([]byte, 1)
+		n, err := conn.Conn.Read(data)
+		conn.buffered = data[:n]
+		// idle deadline of next request may expire, it is not close of connection
+		if err != nil && !os.IsTimeout(err) {
+			cancel()
+		}
+		reading <- err
+	}()
+}
+
+func (conn *closeNotifyConn) Read(data []byte) (n int, err error) {
+	if conn.reading != nil {
+		err = <-conn.reading
+		conn.reading = nil
+		if err != nil && !os.IsTimeout(err) {
+			conn.readErr = err
+		}
+	}
+	if len(conn.buffered) != 0 {
+		n = copy(data, conn.buffered)
+		conn.buffered = conn.buffered[n:]
+		return n, nil
+	}
+	if conn.readErr != nil {
+		return 0, conn.readErr
+	}
+	return conn.Conn.Read(data)
+}
+
+// closeNotifyTLSConn keeps methods of TLS connection, so fasthttp recognizes HTTPS requests
+type closeNotifyTLSConn struct {
+	*closeNotifyConn
+}
+
+func (conn closeNotifyTLSConn) Handshake() error {
+	return conn.Conn.(*tls.Conn).Handshake()
+}
+
+func (conn closeNotifyTLSConn) ConnectionState() tls.ConnectionState {
+	return conn.Conn.(*tls.Conn).ConnectionState()
+}
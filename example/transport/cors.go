@@ -0,0 +1,170 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CORSConfig of cross-origin requests, origin may contain one wildcard like 'https://*.example.com',
+// methods of route are allowed if AllowedMethods is empty, CORS-safelisted headers are allowed always
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+var corsSafelisted = map[string]struct{}{
+	"accept":           {},
+	"accept-language":  {},
+	"content-language": {},
+	"content-type":     {},
+}
+
+// WithCORS replaces CORS configuration of annotations for all interfaces and batch endpoint
+func WithCORS(config CORSConfig) Option {
+	return func(srv *Server) {
+		srv.cors = &config
+	}
+}
+
+func (srv *Server) withCORS(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		srv.cors.setHeaders(ctx)
+	}
+}
+
+func (srv *Server) preflight(methods ...string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		srv.cors.preflight(ctx, methods)
+	}
+}
+
+func (config *CORSConfig) setHeaders(ctx *fasthttp.RequestCtx) {
+
+	origin := string(ctx.Request.Header.Peek("Origin"))
+	if config == nil || origin == "" || !config.allowOrigin(origin) {
+		return
+	}
+	ctx.Response.Header.Add("Vary", "Origin")
+	ctx.Response.Header.Set("Access-Control-Allow-Origin", config.originValue(origin))
+	if config.AllowCredentials {
+		ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(config.ExposedHeaders) != 0 {
+		ctx.Response.Header.Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
+	}
+}
+
+func (config *CORSConfig) preflight(ctx *fasthttp.RequestCtx, methods []string) {
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+	ctx.Response.Header.Set("Allow", strings.Join(methods, ", ")+", OPTIONS")
+
+	origin := string(ctx.Request.Header.Peek("Origin"))
+	method := string(ctx.Request.Header.Peek("Access-Control-Request-Method"))
+	if config == nil || origin == "" || method == "" {
+		return
+	}
+	ctx.Response.Header.Add("Vary", "Origin")
+	ctx.Response.Header.Add("Vary", "Access-Control-Request-Method")
+	ctx.Response.Header.Add("Vary", "Access-Control-Request-Headers")
+
+	allowed := config.allowedMethods(methods)
+	headers := string(ctx.Request.Header.Peek("Access-Control-Request-Headers"))
+	if !config.allowOrigin(origin) || !containsString(allowed, method) || !config.allowHeaders(headers) {
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		return
+	}
+	ctx.Response.Header.Set("Access-Control-Allow-Origin", config.originValue(origin))
+	ctx.Response.Header.Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+	if headers != "" {
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", headers)
+	}
+	if config.AllowCredentials {
+		ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if config.MaxAge > 0 {
+		ctx.Response.Header.Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+	}
+}
+
+func (config *CORSConfig) allowOrigin(origin string) bool {
+
+	for _, allowed := range config.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if i := strings.Index(allowed, "*"); i >= 0 && len(origin) >= len(allowed) && strings.HasPrefix(origin, allowed[:i]) && strings.HasSuffix(origin, allowed[i+1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (config *CORSConfig) originValue(origin string) string {
+
+	if !config.AllowCredentials {
+		for _, allowed := range config.AllowedOrigins {
+			if allowed == "*" {
+				return "*"
+			}
+		}
+	}
+	return origin
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (config *CORSConfig) allowedMethods(methods []string) (allowed []string) {
+
+	if len(config.AllowedMethods) == 0 {
+		return methods
+	}
+	for _, method := range methods {
+		for _, configured := range config.AllowedMethods {
+			if configured == "*" || configured == method {
+				allowed = append(allowed, method)
+				break
+			}
+		}
+	}
+	return
+}
+
+func (config *CORSConfig) allowHeaders(headers string) bool {
+
+	for _, header := range strings.Split(headers, ",") {
+		if header = strings.ToLower(strings.TrimSpace(header)); header == "" {
+			continue
+		}
+		if _, found := corsSafelisted[header]; found {
+			continue
+		}
+		allowed := false
+		for _, configured := range config.AllowedHeaders {
+			if configured == "*" || strings.EqualFold(configured, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
@@ -2,7 +2,9 @@
 package transport
 
 import (
+	"errors"
 	"os"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -11,12 +13,36 @@ type withErrorCode interface {
 	Code() int
 }
 
+type withErrorCodeJsonRPC interface {
+	JsonRPCCode() int
+}
+
 type strError string
 
 func (e strError) Error() string {
 	return string(e)
 }
 
+type shutdownErrors []error
+
+func (e shutdownErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any error of shutdown matches target, like context.DeadlineExceeded
+func (e shutdownErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func ExitOnError(log logrus.FieldLogger, err error, msg string) {
 	if err != nil {
 		log.WithError(err).Error(msg)
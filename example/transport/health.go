@@ -0,0 +1,152 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const defaultHealthTimeout = time.Second * 5
+
+const (
+	healthOK       = "ok"
+	healthFail     = "fail"
+	healthShutdown = "shutdown"
+)
+
+type HealthCheck func(ctx context.Context) error
+
+// HealthChecker could be implemented by service to be checked by readiness probe automatically
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type healthCheck struct {
+	name    string
+	check   HealthCheck
+	timeout time.Duration
+}
+
+type healthStatus struct {
+	Status string                  `json:"status"`
+	Error  string                  `json:"error,omitempty"`
+	Checks map[string]healthStatus `json:"checks,omitempty"`
+}
+
+func (srv *Server) WithHealthCheck(name string, check HealthCheck) *Server {
+	return srv.WithHealthCheckTimeout(name, defaultHealthTimeout, check)
+}
+
+func (srv *Server) WithHealthCheckTimeout(name string, timeout time.Duration, check HealthCheck) *Server {
+	srv.healthMtx.Lock()
+	defer srv.healthMtx.Unlock()
+	srv.healthChecks = append(srv.healthChecks, healthCheck{
+		check:   check,
+		name:    name,
+		timeout: timeout,
+	})
+	return srv
+}
+
+func (srv *Server) registerHealthCheckers() {
+	if srv.httpJsonRPC != nil {
+		if checker, ok := srv.httpJsonRPC.base.(HealthChecker); ok {
+			srv.WithHealthCheck("jsonrpc", checker.HealthCheck)
+		}
+	}
+	if srv.httpUser != nil {
+		if checker, ok := srv.httpUser.base.(HealthChecker); ok {
+			srv.WithHealthCheck("user", checker.HealthCheck)
+		}
+	}
+}
+
+func (srv *Server) ServeHealth(address string) (err error) {
+
+	srv.srvHealth = &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			switch string(ctx.Path()) {
+			case "/live":
+				sendResponse(srv.log, ctx, healthStatus{Status: healthOK})
+			// root path is kept for probes of previous versions, which answered on any path
+			case "/", "/ready":
+				srv.readiness(ctx)
+			default:
+				ctx.SetStatusCode(fasthttp.StatusNotFound)
+			}
+		},
+		ReadTimeout: time.Second * 10,
+	}
+	return srv.serve(address, "health", func(listener net.Listener) error {
+		return srv.srvHealth.Serve(listener)
+	})
+}
+
+func (srv *Server) readiness(ctx *fasthttp.RequestCtx) {
+
+	if atomic.LoadInt32(&srv.stopping) != 0 {
+		ctx.SetConnectionClose()
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		sendResponse(srv.log, ctx, healthStatus{Status: healthShutdown})
+		return
+	}
+
+	srv.healthMtx.RLock()
+	checks := append([]healthCheck(nil), srv.healthChecks...)
+	srv.healthMtx.RUnlock()
+
+	status := healthStatus{
+		Checks: make(map[string]healthStatus, len(checks)),
+		Status: healthOK,
+	}
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check healthCheck) {
+			defer wg.Done()
+			checkStatus := healthStatus{Status: healthOK}
+			if err := runHealthCheck(srv.ctx, check); err != nil {
+				checkStatus = healthStatus{
+					Error:  err.Error(),
+					Status: healthFail,
+				}
+			}
+			mtx.Lock()
+			status.Checks[check.name] = checkStatus
+			if checkStatus.Status != healthOK {
+				status.Status = healthFail
+			}
+			mtx.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	if status.Status != healthOK {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}
+	sendResponse(srv.log, ctx, status)
+}
+
+func runHealthCheck(ctx context.Context, check healthCheck) (err error) {
+
+	ctx, cancel := context.WithTimeout(ctx, check.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check.check(ctx)
+	}()
+	select {
+	case err = <-done:
+		return
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -2,25 +2,45 @@
 package transport
 
 import (
+	"context"
+	"runtime/debug"
+	"time"
+
 	"github.com/fasthttp/router"
 	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
 
 	"github.com/seniorGolang/tg/example/interfaces"
 )
 
 type httpJsonRPC struct {
-	log          logrus.FieldLogger
-	errorHandler ErrorHandler
-	svc          *serverJsonRPC
-	base         interfaces.JsonRPC
+	log              logrus.FieldLogger
+	errorHandler     ErrorHandler
+	svc              *serverJsonRPC
+	base             interfaces.JsonRPC
+	timeout          time.Duration
+	baseCtx          context.Context
+	panicHandler     PanicHandler
+	compressMinSize  int
+	maxBatchSize     int
+	maxParallelBatch int
+	metrics          *Metrics
+	cors             *CORSConfig
 }
 
 func NewJsonRPC(log logrus.FieldLogger, svcJsonRPC interfaces.JsonRPC) (srv *httpJsonRPC) {
 
 	srv = &httpJsonRPC{
-		base: svcJsonRPC,
-		log:  log,
-		svc:  newServerJsonRPC(svcJsonRPC),
+		base:            svcJsonRPC,
+		compressMinSize: defaultCompressMinSize,
+		cors: &CORSConfig{
+			AllowedHeaders: []string{"Authorization", "X-Client-Id"},
+			AllowedOrigins: []string{"http://example.test"},
+			MaxAge:         600 * time.Second,
+		},
+		log:              log,
+		maxParallelBatch: defaultMaxParallelBatch,
+		svc:              newServerJsonRPC(svcJsonRPC),
 	}
 	return
 }
@@ -39,8 +59,9 @@ func (http *httpJsonRPC) WithTrace() *httpJsonRPC {
 	return http
 }
 
-func (http *httpJsonRPC) WithMetrics() *httpJsonRPC {
-	http.svc.WithMetrics()
+func (http *httpJsonRPC) WithMetrics(metrics *Metrics) *httpJsonRPC {
+	http.metrics = metrics
+	http.svc.WithMetrics(metrics)
 	return http
 }
 
@@ -49,8 +70,69 @@ func (http *httpJsonRPC) WithErrorHandler(handler ErrorHandler) *httpJsonRPC {
 	return http
 }
 
+func (http *httpJsonRPC) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		timeout = http.timeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (http *httpJsonRPC) detach(ctx *fasthttp.RequestCtx) context.Context {
+	if http.baseCtx == nil {
+		return ctx
+	}
+	return detachedContext{
+		Context: requestContext(ctx, http.baseCtx),
+		values:  ctx,
+	}
+}
+
+func (http *httpJsonRPC) onPanic(ctx *fasthttp.RequestCtx, method string, recovered interface{}) {
+
+	stack := debug.Stack()
+	if http.metrics != nil {
+		http.metrics.panics.With("method", method, "service", "JsonRPC").Add(1)
+	}
+	if http.panicHandler != nil {
+		http.panicHandler(ctx, method, recovered, stack)
+		return
+	}
+	http.log.WithFields(logrus.Fields{
+		"method":    method,
+		"requestID": ctx.UserValue(headerRequestID),
+		"service":   "JsonRPC",
+		"stack":     string(stack),
+	}).Errorf("panic: %v", recovered)
+}
+
+func (http *httpJsonRPC) compress(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		compressResponse(ctx, http.compressMinSize)
+	}
+}
+
+func (http *httpJsonRPC) withCORS(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		http.cors.setHeaders(ctx)
+	}
+}
+
+func (http *httpJsonRPC) preflight(methods ...string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		http.cors.preflight(ctx, methods)
+	}
+}
+
 func (http *httpJsonRPC) SetRoutes(route *router.Router) {
 
-	route.POST("/jsonrpc", http.serveBatch)
-	route.POST("/jsonRPC/test", http.serveTest)
+	route.POST("/jsonrpc", http.withCORS(http.compress(http.serveBatch)))
+	route.POST("/jsonRPC/test", http.withCORS(http.compress(http.serveTest)))
+
+	route.OPTIONS("/jsonrpc", http.preflight("POST"))
+	route.OPTIONS("/jsonRPC/test", http.preflight("POST"))
 }
@@ -2,10 +2,11 @@
 package transport
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
@@ -17,6 +18,17 @@ func (http *httpJsonRPC) serveTest(ctx *fasthttp.RequestCtx) {
 	http.serveMethod(ctx, "test", http.test)
 }
 func (http *httpJsonRPC) test(span opentracing.Span, ctx *fasthttp.RequestCtx, requestBase baseJsonRPC) (responseBase *baseJsonRPC) {
+	defer func() {
+		http.observeJsonRPC("test", responseBase)
+	}()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("msg", fmt.Sprintf("panic: %v", recovered))
+			http.onPanic(ctx, "test", recovered)
+			responseBase = makeErrorResponseJsonRPC(requestBase.ID, internalError, "internal error", nil)
+		}
+	}()
 
 	var err error
 	var request requestJsonRPCTest
@@ -35,7 +47,14 @@ func (http *httpJsonRPC) test(span opentracing.Span, ctx *fasthttp.RequestCtx, r
 		return makeErrorResponseJsonRPC(requestBase.ID, parseError, "incorrect protocol version: "+requestBase.Version, nil)
 	}
 
-	methodContext := opentracing.ContextWithSpan(ctx, span)
+	methodContext, cancel := http.withTimeout(opentracing.ContextWithSpan(http.detach(ctx), span), 0)
+	defer cancel()
+
+	if err = request.validate(); err != nil {
+		ext.Error.Set(span, true)
+		span.SetTag("msg", "invalid params: "+err.Error())
+		return makeErrorResponseJsonRPC(requestBase.ID, invalidParamsError, "invalid params: "+err.Error(), err)
+	}
 
 	var response responseJsonRPCTest
 
@@ -46,9 +65,15 @@ func (http *httpJsonRPC) test(span opentracing.Span, ctx *fasthttp.RequestCtx, r
 			err = http.errorHandler(err)
 		}
 		ext.Error.Set(span, true)
-		span.SetTag("msg", err)
+		span.SetTag("msg", err.Error())
 		span.SetTag("errData", toString(err))
-		return makeErrorResponseJsonRPC(requestBase.ID, internalError, err.Error(), err)
+		code := internalError
+		if errCoder, ok := err.(withErrorCodeJsonRPC); ok {
+			code = errCoder.JsonRPCCode()
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			code = timeoutError
+		}
+		return makeErrorResponseJsonRPC(requestBase.ID, code, err.Error(), err)
 	}
 
 	responseBase = &baseJsonRPC{
@@ -69,6 +94,7 @@ func (http *httpJsonRPC) serveBatch(ctx *fasthttp.RequestCtx) {
 	batchSpan := extractSpan(http.log, fmt.Sprintf("jsonRPC:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, batchSpan, ctx)
 	defer batchSpan.Finish()
+	defer http.observeHTTP("batch", ctx)
 	methodHTTP := gotils.B2S(ctx.Method())
 
 	if methodHTTP != fasthttp.MethodPost {
@@ -82,47 +108,37 @@ func (http *httpJsonRPC) serveBatch(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	var err error
-	var requests []baseJsonRPC
-
-	if err = json.Unmarshal(ctx.PostBody(), &requests); err != nil {
+	requests, errResponse := decodeBatch(ctx.PostBody(), http.maxBatchSize)
+	if errResponse != nil {
 		ext.Error.Set(batchSpan, true)
-		batchSpan.SetTag("msg", "request body could not be decoded: "+err.Error())
-		sendResponse(http.log, ctx, makeErrorResponseJsonRPC([]byte("\"0\""), parseError, "request body could not be decoded: "+err.Error(), nil))
+		batchSpan.SetTag("msg", errResponse.Error.Message)
+		sendResponse(http.log, ctx, errResponse)
 		return
 	}
 
-	responses := make(jsonrpcResponses, 0, len(requests))
-
-	var wg sync.WaitGroup
-
-	for _, request := range requests {
-
-		methodNameOrigin := request.Method
-		method := strings.ToLower(request.Method)
-
-		span := opentracing.StartSpan(request.Method, opentracing.ChildOf(batchSpan.Context()))
-		span.SetTag("batch", true)
+	responses := callBatch(requests, http.maxParallelBatch, func(request baseJsonRPC) *baseJsonRPC {
+		return http.batchCall(batchSpan, ctx, request)
+	})
+	sendResponse(http.log, ctx, responses)
+}
 
-		switch method {
+func (http *httpJsonRPC) batchCall(batchSpan opentracing.Span, ctx *fasthttp.RequestCtx, request baseJsonRPC) (response *baseJsonRPC) {
 
-		case "test":
+	methodNameOrigin := request.Method
+	method := strings.ToLower(request.Method)
 
-			wg.Add(1)
-			func(request baseJsonRPC) {
-				responses.append(http.test(span, ctx, request))
-				wg.Done()
-			}(request)
+	span := opentracing.StartSpan(request.Method, opentracing.ChildOf(batchSpan.Context()))
+	span.SetTag("batch", true)
+	defer span.Finish()
 
-		default:
-			ext.Error.Set(span, true)
-			span.SetTag("msg", "invalid method '"+methodNameOrigin+"'")
-			responses.append(makeErrorResponseJsonRPC(request.ID, methodNotFoundError, "invalid method '"+methodNameOrigin+"'", nil))
-		}
-		span.Finish()
+	switch method {
+	case "test":
+		return http.test(span, ctx, request)
+	default:
+		ext.Error.Set(span, true)
+		span.SetTag("msg", "invalid method '"+methodNameOrigin+"'")
+		return makeErrorResponseJsonRPC(request.ID, methodNotFoundError, "invalid method '"+methodNameOrigin+"'", nil)
 	}
-	wg.Wait()
-	sendResponse(http.log, ctx, responses)
 }
 
 func (http *httpJsonRPC) serveMethod(ctx *fasthttp.RequestCtx, methodName string, methodHandler methodJsonRPC) {
@@ -130,6 +146,7 @@ func (http *httpJsonRPC) serveMethod(ctx *fasthttp.RequestCtx, methodName string
 	span := extractSpan(http.log, fmt.Sprintf("jsonRPC:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, span, ctx)
 	defer span.Finish()
+	defer http.observeHTTP(methodName, ctx)
 
 	methodHTTP := gotils.B2S(ctx.Method())
 
@@ -29,15 +29,15 @@ func (m loggerJsonRPC) Test(ctx context.Context, arg0 int, arg1 string, opts ...
 	defer func(begin time.Time) {
 		fields := logrus.Fields{
 			"method": "test",
-			"request": viewer.Sprintf("%+v", requestJsonRPCTest{
+			"request": viewer.Sprintf("%+v", redactLog(requestJsonRPCTest{
 				Arg0: arg0,
 				Arg1: arg1,
 				Opts: opts,
-			}),
-			"response": viewer.Sprintf("%+v", responseJsonRPCTest{
+			})),
+			"response": viewer.Sprintf("%+v", redactLog(responseJsonRPCTest{
 				Ret1: ret1,
 				Ret2: ret2,
-			}),
+			})),
 			"service": "JsonRPC",
 			"took":    time.Since(begin),
 		}
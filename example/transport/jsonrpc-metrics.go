@@ -4,26 +4,32 @@ package transport
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/valyala/fasthttp"
 
 	"github.com/seniorGolang/tg/example/interfaces"
 )
 
 type metricsJsonRPC struct {
-	next            interfaces.JsonRPC
-	requestCount    metrics.Counter
-	requestCountAll metrics.Counter
-	requestLatency  metrics.Histogram
+	next             interfaces.JsonRPC
+	requestCount     metrics.Counter
+	requestCountAll  metrics.Counter
+	requestLatency   metrics.Histogram
+	requestsInFlight metrics.Gauge
 }
 
-func metricsMiddlewareJsonRPC(next interfaces.JsonRPC) interfaces.JsonRPC {
-	return &metricsJsonRPC{
-		next:            next,
-		requestCount:    RequestCount.With("service", "JsonRPC"),
-		requestCountAll: RequestCountAll.With("service", "JsonRPC"),
-		requestLatency:  RequestLatency.With("service", "JsonRPC"),
+func metricsMiddlewareJsonRPC(metrics *Metrics) MiddlewareJsonRPC {
+	return func(next interfaces.JsonRPC) interfaces.JsonRPC {
+		return &metricsJsonRPC{
+			next:             next,
+			requestCount:     metrics.requestCount.With("service", "JsonRPC"),
+			requestCountAll:  metrics.requestCountAll.With("service", "JsonRPC"),
+			requestLatency:   metrics.requestLatency.With("service", "JsonRPC"),
+			requestsInFlight: metrics.requestsInFlight.With("service", "JsonRPC"),
+		}
 	}
 }
 
@@ -35,7 +41,32 @@ func (m metricsJsonRPC) Test(ctx context.Context, arg0 int, arg1 string, opts ..
 
 	defer m.requestCount.With("method", "test", "success", fmt.Sprint(err == nil)).Add(1)
 
+	m.requestsInFlight.With("method", "test").Add(1)
+	defer m.requestsInFlight.With("method", "test").Add(-1)
+
 	m.requestCountAll.With("method", "test").Add(1)
 
 	return m.next.Test(ctx, arg0, arg1, opts...)
 }
+
+func (http *httpJsonRPC) observeHTTP(method string, ctx *fasthttp.RequestCtx) {
+
+	if http.metrics == nil {
+		return
+	}
+	http.metrics.requestSize.With("method", method, "service", "JsonRPC").Observe(float64(len(ctx.Request.Body())))
+	http.metrics.responseSize.With("method", method, "service", "JsonRPC").Observe(float64(len(ctx.Response.Body())))
+	http.metrics.httpResponses.With("method", method, "service", "JsonRPC", "code", strconv.Itoa(ctx.Response.StatusCode())).Add(1)
+}
+
+func (http *httpJsonRPC) observeJsonRPC(method string, response *baseJsonRPC) {
+
+	if http.metrics == nil || response == nil {
+		return
+	}
+	code := 0
+	if response.Error != nil {
+		code = response.Error.Code
+	}
+	http.metrics.jsonRPCResponses.With("method", method, "service", "JsonRPC", "code", strconv.Itoa(code)).Add(1)
+}
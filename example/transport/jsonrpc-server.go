@@ -19,7 +19,7 @@ type MiddlewareSetJsonRPC interface {
 	WrapTest(m MiddlewareJsonRPCTest)
 
 	WithTrace()
-	WithMetrics()
+	WithMetrics(metrics *Metrics)
 	WithLog(log logrus.FieldLogger)
 }
 
@@ -47,8 +47,8 @@ func (srv *serverJsonRPC) WithTrace() {
 	srv.Wrap(traceMiddlewareJsonRPC)
 }
 
-func (srv *serverJsonRPC) WithMetrics() {
-	srv.Wrap(metricsMiddlewareJsonRPC)
+func (srv *serverJsonRPC) WithMetrics(metrics *Metrics) {
+	srv.Wrap(metricsMiddlewareJsonRPC(metrics))
 }
 
 func (srv *serverJsonRPC) WithLog(log logrus.FieldLogger) {
@@ -14,7 +14,6 @@ import (
 )
 
 const (
-	maxParallelBatch = 100
 	// Version defines the version of the JSON RPC implementation
 	Version = "2.0"
 	// contentTypeJson defines the content type to be served
@@ -30,6 +29,14 @@ const (
 	invalidParamsError = -32602
 	// InternalError defines a server error
 	internalError = -32603
+	// TimeoutError defines the method call exceeded its deadline
+	timeoutError = -32001
+	// UnauthorizedError defines the request has no valid credentials for the method
+	unauthorizedError = -32002
+	// ForbiddenError defines the principal has no role required by the method
+	forbiddenError = -32003
+	// LimitExceededError defines the call is rejected by rate or concurrency limit of the method
+	limitExceededError = -32004
 )
 
 type idJsonRPC = json.RawMessage
@@ -66,11 +73,14 @@ func (responses *jsonrpcResponses) append(response *baseJsonRPC) {
 
 type methodJsonRPC func(span opentracing.Span, ctx *fasthttp.RequestCtx, requestBase baseJsonRPC) (responseBase *baseJsonRPC)
 
+const defaultMaxParallelBatch = 100
+
 func (srv *Server) serveBatch(ctx *fasthttp.RequestCtx) {
 
 	batchSpan := extractSpan(srv.log, fmt.Sprintf("jsonRPC:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(srv.log, batchSpan, ctx)
 	defer batchSpan.Finish()
+	defer srv.observeBatch(ctx)
 	methodHTTP := gotils.B2S(ctx.Method())
 
 	if methodHTTP != fasthttp.MethodPost {
@@ -90,70 +100,95 @@ func (srv *Server) serveBatch(ctx *fasthttp.RequestCtx) {
 
 	ctx.SetContentType(contentTypeJson)
 
-	var err error
-	var requests []baseJsonRPC
-
-	if err = json.Unmarshal(ctx.PostBody(), &requests); err != nil {
+	requests, errResponse := decodeBatch(ctx.PostBody(), srv.maxBatchSize)
+	if errResponse != nil {
 		ext.Error.Set(batchSpan, true)
-		batchSpan.SetTag("msg", "request body could not be decoded: "+err.Error())
+		batchSpan.SetTag("msg", errResponse.Error.Message)
 
 		for _, handler := range srv.httpAfter {
 			handler(ctx)
 		}
-		sendResponse(srv.log, ctx, makeErrorResponseJsonRPC([]byte("\"0\""), parseError, "request body could not be decoded: "+err.Error(), nil))
+		sendResponse(srv.log, ctx, errResponse)
 		return
 	}
 
-	responses := make(jsonrpcResponses, 0, len(requests))
+	responses := callBatch(requests, srv.maxParallelBatch, func(request baseJsonRPC) *baseJsonRPC {
+		return srv.batchCall(batchSpan, ctx, request)
+	})
 
-	var n int
-	var wg sync.WaitGroup
+	for _, handler := range srv.httpAfter {
+		handler(ctx)
+	}
+	sendResponse(srv.log, ctx, responses)
+}
 
-	for _, request := range requests {
-
-		methodNameOrigin := request.Method
-		method := strings.ToLower(request.Method)
-
-		switch method {
-
-		case "jsonrpc.test":
-			wg.Add(1)
-			go func(request baseJsonRPC) {
-
-				span := opentracing.StartSpan(request.Method, opentracing.ChildOf(batchSpan.Context()))
-				span.SetTag("batch", true)
-				defer span.Finish()
-				if request.ID != nil {
-					responses.append(srv.httpJsonRPC.test(span, ctx, request))
-					wg.Done()
-					return
-				}
-				srv.httpJsonRPC.test(span, ctx, request)
-				wg.Done()
-			}(request)
-
-		default:
-			span := opentracing.StartSpan(request.Method, opentracing.ChildOf(batchSpan.Context()))
-			span.SetTag("batch", true)
-			ext.Error.Set(span, true)
-			span.SetTag("msg", "invalid method '"+methodNameOrigin+"'")
-			responses.append(makeErrorResponseJsonRPC(request.ID, methodNotFoundError, "invalid method '"+methodNameOrigin+"'", nil))
-			span.Finish()
-		}
+func (srv *Server) batchCall(batchSpan opentracing.Span, ctx *fasthttp.RequestCtx, request baseJsonRPC) (response *baseJsonRPC) {
 
-		if n > maxParallelBatch {
-			n = 0
-			wg.Wait()
-		}
-		n++
+	methodNameOrigin := request.Method
+	method := strings.ToLower(request.Method)
+
+	span := opentracing.StartSpan(request.Method, opentracing.ChildOf(batchSpan.Context()))
+	span.SetTag("batch", true)
+	defer span.Finish()
+
+	switch method {
+	case "jsonrpc.test":
+		return srv.httpJsonRPC.test(span, ctx, request)
+	case "rpc.discover":
+		return srv.discover(request)
+	default:
+		ext.Error.Set(span, true)
+		span.SetTag("msg", "invalid method '"+methodNameOrigin+"'")
+		return makeErrorResponseJsonRPC(request.ID, methodNotFoundError, "invalid method '"+methodNameOrigin+"'", nil)
 	}
+}
+
+func decodeBatch(body []byte, maxBatchSize int) (requests []baseJsonRPC, errResponse *baseJsonRPC) {
 
+	if err := json.Unmarshal(body, &requests); err != nil {
+		return nil, makeErrorResponseJsonRPC([]byte("\"0\""), parseError, "request body could not be decoded: "+err.Error(), nil)
+	}
+	if len(requests) == 0 {
+		return nil, makeErrorResponseJsonRPC([]byte("\"0\""), invalidRequestError, "empty batch", nil)
+	}
+	if maxBatchSize > 0 && len(requests) > maxBatchSize {
+		return nil, makeErrorResponseJsonRPC([]byte("\"0\""), invalidRequestError, fmt.Sprintf("batch size exceeded, max %d requests", maxBatchSize), nil)
+	}
+	return
+}
+
+func callBatch(requests []baseJsonRPC, workers int, call func(request baseJsonRPC) *baseJsonRPC) (responses jsonrpcResponses) {
+
+	if workers <= 0 || workers > len(requests) {
+		workers = len(requests)
+	}
+
+	// each worker writes only to its own element of results, order of requests is kept
+	results := make([]*baseJsonRPC, len(requests))
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for n := range queue {
+				results[n] = call(requests[n])
+			}
+		}()
+	}
+	for n := range requests {
+		queue <- n
+	}
+	close(queue)
 	wg.Wait()
 
-	for _, handler := range srv.httpAfter {
-		handler(ctx)
+	responses = make(jsonrpcResponses, 0, len(requests))
+	for _, response := range results {
+		responses.append(response)
 	}
-	sendResponse(srv.log, ctx, responses)
+	return
 }
 
 func makeErrorResponseJsonRPC(id idJsonRPC, code int, msg string, data interface{}) *baseJsonRPC {
@@ -0,0 +1,115 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"container/list"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const limiterMaxKeys = 10000
+
+// ctxKeyLimit is key of header value in context of method, which is key of limiter
+type ctxKeyLimit struct{}
+
+// ErrLimitExceeded is returned for calls rejected by rate-limit or max-concurrent annotations
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+type tokenBucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+type rateLimiter struct {
+	lock    sync.Mutex
+	rate    float64
+	burst   float64
+	recent  *list.List
+	buckets map[string]*list.Element
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[string]*list.Element),
+		burst:   float64(burst),
+		rate:    rate,
+		recent:  list.New(),
+	}
+}
+
+func (l *rateLimiter) allow(key string) bool {
+
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	element, found := l.buckets[key]
+	if found {
+		l.recent.MoveToFront(element)
+	} else {
+		if l.recent.Len() >= limiterMaxKeys {
+			oldest := l.recent.Back()
+			l.recent.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*tokenBucket).key)
+		}
+		element = l.recent.PushFront(&tokenBucket{
+			key:     key,
+			tokens:  l.burst,
+			updated: now,
+		})
+		l.buckets[key] = element
+	}
+	bucket := element.Value.(*tokenBucket)
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.rate)
+	bucket.updated = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+type concurrencyLimiter struct {
+	lock   sync.Mutex
+	max    int
+	active map[string]int
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		active: make(map[string]int),
+		max:    max,
+	}
+}
+
+func (l *concurrencyLimiter) acquire(key string) bool {
+
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.active[key] >= l.max {
+		return false
+	}
+	l.active[key]++
+	return true
+}
+
+func (l *concurrencyLimiter) release(key string) {
+
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.active[key]--; l.active[key] <= 0 {
+		delete(l.active, key)
+	}
+}
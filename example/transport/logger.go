@@ -0,0 +1,102 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	logMask      = "*****"
+	logMaxBytes  = 64
+	logMaxString = 1024
+	logMaxDepth  = 16
+)
+
+func redactLog(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(value), 0).Interface()
+}
+
+func redactValue(value reflect.Value, depth int) reflect.Value {
+
+	if depth > logMaxDepth {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		elem := redactValue(value.Elem(), depth+1)
+		if value.Kind() == reflect.Ptr {
+			result := reflect.New(elem.Type())
+			result.Elem().Set(elem)
+			return result
+		}
+		result := reflect.New(value.Type()).Elem()
+		result.Set(elem)
+		return result
+	case reflect.String:
+		if value.Len() <= logMaxString {
+			return value
+		}
+		result := reflect.New(value.Type()).Elem()
+		result.SetString(fmt.Sprintf("%s...(%d bytes)", value.String()[:logMaxString], value.Len()))
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			if value.Len() > logMaxBytes {
+				return value.Slice(0, logMaxBytes)
+			}
+			return value
+		}
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(redactValue(value.Index(i), depth+1))
+		}
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), redactValue(iter.Value(), depth+1))
+		}
+		return result
+	case reflect.Struct:
+		return redactStruct(value, depth)
+	}
+	return value
+}
+
+func redactStruct(value reflect.Value, depth int) reflect.Value {
+
+	result := reflect.New(value.Type()).Elem()
+	result.Set(value)
+
+	for i := 0; i < value.NumField(); i++ {
+
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Tag.Get("log") == "-" {
+			if field.Type.Kind() == reflect.String {
+				result.Field(i).SetString(logMask)
+				continue
+			}
+			result.Field(i).Set(reflect.Zero(field.Type))
+			continue
+		}
+		result.Field(i).Set(redactValue(value.Field(i), depth+1))
+	}
+	return result
+}
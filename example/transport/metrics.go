@@ -2,48 +2,214 @@
 package transport
 
 import (
+	"errors"
+	"net"
+	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	kitPrometheus "github.com/go-kit/kit/metrics/prometheus"
 	stdPrometheus "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 )
 
-var srvMetrics *fasthttp.Server
-
-var RequestCount = kitPrometheus.NewCounterFrom(stdPrometheus.CounterOpts{
-	Help:      "Number of requests received",
-	Name:      "count",
-	Namespace: "service",
-	Subsystem: "requests",
-}, []string{"method", "service", "success"})
-
-var RequestCountAll = kitPrometheus.NewCounterFrom(stdPrometheus.CounterOpts{
-	Help:      "Number of all requests received",
-	Name:      "all_count",
-	Namespace: "service",
-	Subsystem: "requests",
-}, []string{"method", "service"})
-
-var RequestLatency = kitPrometheus.NewSummaryFrom(stdPrometheus.SummaryOpts{
-	Help:      "Total duration of requests in microseconds",
-	Name:      "latency_microseconds",
-	Namespace: "service",
-	Subsystem: "requests",
-}, []string{"method", "service", "success"})
-
-func ServeMetrics(log logrus.FieldLogger, address string) {
-
-	srvMetrics = &fasthttp.Server{
-		Handler:     fasthttpadaptor.NewFastHTTPHandler(promhttp.Handler()),
+type MetricsConfig struct {
+	Namespace   string
+	Subsystem   string
+	ConstLabels stdPrometheus.Labels
+	Buckets     []float64
+	SizeBuckets []float64
+	Registerer  stdPrometheus.Registerer
+}
+
+type Metrics struct {
+	gatherer stdPrometheus.Gatherer
+
+	requestCount     metrics.Counter
+	requestCountAll  metrics.Counter
+	requestLatency   metrics.Histogram
+	requestsInFlight metrics.Gauge
+	requestSize      metrics.Histogram
+	responseSize     metrics.Histogram
+	httpResponses    metrics.Counter
+	jsonRPCResponses metrics.Counter
+	panics           metrics.Counter
+	rejections       metrics.Counter
+}
+
+func NewMetrics(config MetricsConfig) (m *Metrics, err error) {
+
+	if config.Namespace == "" {
+		config.Namespace = "service"
+	}
+	if config.Subsystem == "" {
+		config.Subsystem = "requests"
+	}
+	if len(config.Buckets) == 0 {
+		config.Buckets = stdPrometheus.DefBuckets
+	}
+	if len(config.SizeBuckets) == 0 {
+		config.SizeBuckets = stdPrometheus.ExponentialBuckets(64, 4, 8)
+	}
+	if config.Registerer == nil {
+		config.Registerer = stdPrometheus.DefaultRegisterer
+	}
+
+	m = &Metrics{gatherer: stdPrometheus.DefaultGatherer}
+	if gatherer, ok := config.Registerer.(stdPrometheus.Gatherer); ok {
+		m.gatherer = gatherer
+	}
+
+	var collector stdPrometheus.Collector
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of requests received",
+		Name:        "count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service", "success"})); err != nil {
+		return nil, err
+	}
+	m.requestCount = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of all requests received",
+		Name:        "all_count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.requestCountAll = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewHistogramVec(stdPrometheus.HistogramOpts{
+		Buckets:     config.Buckets,
+		ConstLabels: config.ConstLabels,
+		Help:        "Duration of requests in seconds",
+		Name:        "latency_seconds",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service", "success"})); err != nil {
+		return nil, err
+	}
+	m.requestLatency = kitPrometheus.NewHistogram(collector.(*stdPrometheus.HistogramVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewGaugeVec(stdPrometheus.GaugeOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of requests in progress",
+		Name:        "in_flight",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.requestsInFlight = kitPrometheus.NewGauge(collector.(*stdPrometheus.GaugeVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewHistogramVec(stdPrometheus.HistogramOpts{
+		Buckets:     config.SizeBuckets,
+		ConstLabels: config.ConstLabels,
+		Help:        "Size of HTTP request bodies in bytes",
+		Name:        "request_size_bytes",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.requestSize = kitPrometheus.NewHistogram(collector.(*stdPrometheus.HistogramVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewHistogramVec(stdPrometheus.HistogramOpts{
+		Buckets:     config.SizeBuckets,
+		ConstLabels: config.ConstLabels,
+		Help:        "Size of HTTP response bodies in bytes",
+		Name:        "response_size_bytes",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.responseSize = kitPrometheus.NewHistogram(collector.(*stdPrometheus.HistogramVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of HTTP responses by status code",
+		Name:        "http_responses_count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service", "code"})); err != nil {
+		return nil, err
+	}
+	m.httpResponses = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of jsonRPC responses by error code",
+		Name:        "jsonrpc_responses_count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service", "code"})); err != nil {
+		return nil, err
+	}
+	m.jsonRPCResponses = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of recovered panics of methods",
+		Name:        "panics_count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.panics = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	if collector, err = registerCollector(config.Registerer, stdPrometheus.NewCounterVec(stdPrometheus.CounterOpts{
+		ConstLabels: config.ConstLabels,
+		Help:        "Number of calls rejected by rate or concurrency limits",
+		Name:        "rejections_count",
+		Namespace:   config.Namespace,
+		Subsystem:   config.Subsystem,
+	}, []string{"method", "service"})); err != nil {
+		return nil, err
+	}
+	m.rejections = kitPrometheus.NewCounter(collector.(*stdPrometheus.CounterVec))
+	return
+}
+
+func registerCollector(registerer stdPrometheus.Registerer, collector stdPrometheus.Collector) (stdPrometheus.Collector, error) {
+
+	if err := registerer.Register(collector); err != nil {
+		var registered stdPrometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector, nil
+		}
+		return nil, err
+	}
+	return collector, nil
+}
+
+func ConfigureMetrics(config MetricsConfig) Option {
+	return func(srv *Server) {
+		srv.metricsConfig = config
+	}
+}
+
+func (srv *Server) ServeMetrics(address string) (err error) {
+
+	if srv.metrics == nil {
+		if srv.metrics, err = NewMetrics(srv.metricsConfig); err != nil {
+			return
+		}
+	}
+	srv.srvMetrics = &fasthttp.Server{
+		Handler:     fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(srv.metrics.gatherer, promhttp.HandlerOpts{})),
 		ReadTimeout: time.Second * 10,
 	}
 
-	go func() {
-		err := srvMetrics.ListenAndServe(address)
-		ExitOnError(log, err, "serve metrics on "+address)
-	}()
+	return srv.serve(address, "metrics", func(listener net.Listener) error {
+		return srv.srvMetrics.Serve(listener)
+	})
+}
+
+func (srv *Server) observeBatch(ctx *fasthttp.RequestCtx) {
+
+	if srv.metrics == nil {
+		return
+	}
+	srv.metrics.requestSize.With("method", "batch", "service", "").Observe(float64(len(ctx.Request.Body())))
+	srv.metrics.responseSize.With("method", "batch", "service", "").Observe(float64(len(ctx.Response.Body())))
+	srv.metrics.httpResponses.With("method", "batch", "service", "", "code", strconv.Itoa(ctx.Response.StatusCode())).Add(1)
 }
@@ -2,6 +2,8 @@
 package transport
 
 import (
+	"time"
+
 	"github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 )
@@ -13,6 +15,7 @@ type ServiceRoute interface {
 type Option func(srv *Server)
 type Handler = fasthttp.RequestHandler
 type ErrorHandler func(err error) error
+type PanicHandler func(ctx *fasthttp.RequestCtx, method string, recovered interface{}, stack []byte)
 
 func Service(svc ServiceRoute) Option {
 	return func(srv *Server) {
@@ -23,6 +26,10 @@ func Service(svc ServiceRoute) Option {
 func JsonRPC(svc *httpJsonRPC) Option {
 	return func(srv *Server) {
 		srv.httpJsonRPC = svc
+		svc.baseCtx = srv.ctx
+		if srv.timeout != 0 {
+			svc.timeout = srv.timeout
+		}
 		svc.SetRoutes(srv.Router())
 	}
 }
@@ -30,10 +37,20 @@ func JsonRPC(svc *httpJsonRPC) Option {
 func User(svc *httpUser) Option {
 	return func(srv *Server) {
 		srv.httpUser = svc
+		svc.baseCtx = srv.ctx
+		if srv.timeout != 0 {
+			svc.timeout = srv.timeout
+		}
 		svc.SetRoutes(srv.Router())
 	}
 }
 
+func OnPanic(handler PanicHandler) Option {
+	return func(srv *Server) {
+		srv.panicHandler = handler
+	}
+}
+
 func AfterHTTP(handler Handler) Option {
 	return func(srv *Server) {
 		srv.httpAfter = append(srv.httpAfter, handler)
@@ -51,3 +68,38 @@ func MaxBodySize(max int) Option {
 		srv.maxRequestBodySize = max
 	}
 }
+
+// ShutdownDelay sets time between readiness reports 503 and closing of listeners by Shutdown,
+// so balancers stop sending new requests before connections are refused
+func ShutdownDelay(delay time.Duration) Option {
+	return func(srv *Server) {
+		srv.shutdownDelay = delay
+	}
+}
+
+// Timeout sets default timeout of methods calls, timeout annotation of method or interface has priority
+func Timeout(timeout time.Duration) Option {
+	return func(srv *Server) {
+		srv.timeout = timeout
+		if srv.httpJsonRPC != nil {
+			srv.httpJsonRPC.timeout = timeout
+		}
+		if srv.httpUser != nil {
+			srv.httpUser.timeout = timeout
+		}
+	}
+}
+
+// MaxBatchSize limits count of requests in jsonRPC batch, zero value means no limit
+func MaxBatchSize(size int) Option {
+	return func(srv *Server) {
+		srv.maxBatchSize = size
+	}
+}
+
+// MaxParallelBatch limits count of jsonRPC batch requests executed in parallel
+func MaxParallelBatch(workers int) Option {
+	return func(srv *Server) {
+		srv.maxParallelBatch = workers
+	}
+}
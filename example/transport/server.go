@@ -2,11 +2,17 @@
 package transport
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fasthttp/router"
@@ -27,12 +33,30 @@ type Server struct {
 	httpBefore []Handler
 
 	maxRequestBodySize int
-
-	srvHTTP   *fasthttp.Server
-	srvHealth *fasthttp.Server
-	srvPPROF  *fasthttp.Server
-
-	reporterCloser io.Closer
+	timeout            time.Duration
+	shutdownDelay      time.Duration
+	maxBatchSize       int
+	maxParallelBatch   int
+
+	srvHTTP    *fasthttp.Server
+	srvHealth  *fasthttp.Server
+	srvPPROF   *fasthttp.Server
+	srvMetrics *fasthttp.Server
+
+	reporterCloser  io.Closer
+	healthMtx       sync.RWMutex
+	healthChecks    []healthCheck
+	panicHandler    PanicHandler
+	compressMinSize int
+	authenticator   Authenticator
+	cors            *CORSConfig
+
+	metrics       *Metrics
+	metricsConfig MetricsConfig
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping int32
 
 	router *router.Router
 
@@ -43,18 +67,47 @@ type Server struct {
 func New(log logrus.FieldLogger, options ...Option) (srv *Server) {
 
 	srv = &Server{
+		compressMinSize:    defaultCompressMinSize,
 		log:                log,
+		maxParallelBatch:   defaultMaxParallelBatch,
 		maxRequestBodySize: maxRequestBodySize,
 		router:             router.New(),
 	}
-	srv.router.POST("/", srv.serveBatch)
+	srv.ctx, srv.cancel = context.WithCancel(context.Background())
+	srv.router.POST("/", srv.withCORS(srv.compress(srv.serveBatch)))
+	srv.router.OPTIONS("/", srv.preflight("POST"))
 	for _, option := range options {
 		option(srv)
 	}
+	if srv.httpJsonRPC != nil {
+		srv.httpJsonRPC.panicHandler = srv.panicHandler
+		srv.httpJsonRPC.compressMinSize = srv.compressMinSize
+		srv.httpJsonRPC.maxBatchSize = srv.maxBatchSize
+		srv.httpJsonRPC.maxParallelBatch = srv.maxParallelBatch
+		if srv.cors != nil {
+			srv.httpJsonRPC.cors = srv.cors
+		}
+	}
+	if srv.httpUser != nil {
+		srv.httpUser.panicHandler = srv.panicHandler
+		srv.httpUser.compressMinSize = srv.compressMinSize
+		srv.httpUser.authenticator = srv.authenticator
+		if srv.cors != nil {
+			srv.httpUser.cors = srv.cors
+		}
+	}
+	if srv.cors == nil {
+		srv.cors = &CORSConfig{
+			AllowedHeaders: []string{"Authorization", "X-Client-Id"},
+			AllowedOrigins: []string{"http://example.test"},
+			MaxAge:         600 * time.Second,
+		}
+	}
+	srv.registerHealthCheckers()
 	return
 }
 
-func (srv *Server) ServeHTTP(address string, wraps ...middleware) {
+func (srv *Server) ServeHTTP(address string, wraps ...middleware) (err error) {
 
 	srv.log.WithField("address", address).Info("enable HTTP transport")
 	handler := srv.httpHandler()
@@ -63,17 +116,17 @@ func (srv *Server) ServeHTTP(address string, wraps ...middleware) {
 		handler = wrap(handler)
 	}
 	srv.srvHTTP = &fasthttp.Server{
+		CloseOnShutdown:    true,
 		Handler:            handler,
 		MaxRequestBodySize: srv.maxRequestBodySize,
 		ReadTimeout:        time.Second * 10,
 	}
-	go func() {
-		err := srv.srvHTTP.ListenAndServe(address)
-		ExitOnError(srv.log, err, "serve http on "+address)
-	}()
+	return srv.serve(address, "http", func(listener net.Listener) error {
+		return srv.srvHTTP.Serve(closeNotifyListener{Listener: listener})
+	})
 }
 
-func (srv *Server) ServeHTTPS(address, certFile, keyFile string, wraps ...middleware) {
+func (srv *Server) ServeHTTPS(address, certFile, keyFile string, wraps ...middleware) (err error) {
 
 	srv.log.WithField("address", address).Info("enable HTTP transport")
 	handler := srv.httpHandler()
@@ -82,19 +135,35 @@ func (srv *Server) ServeHTTPS(address, certFile, keyFile string, wraps ...middle
 		handler = wrap(handler)
 	}
 	srv.srvHTTP = &fasthttp.Server{
+		CloseOnShutdown:    true,
 		Handler:            handler,
 		MaxRequestBodySize: srv.maxRequestBodySize,
 		ReadTimeout:        time.Second * 10,
 	}
-	go func() {
-		err := srv.srvHTTP.ListenAndServeTLS(address, certFile, keyFile)
-		ExitOnError(srv.log, err, "serve http on "+address)
-	}()
+	var cert tls.Certificate
+	if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	return srv.serve(address, "https", func(listener net.Listener) error {
+		return srv.srvHTTP.Serve(closeNotifyListener{Listener: tls.NewListener(listener, config)})
+	})
 }
 
 func (srv *Server) httpHandler() fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 
+		requestCtx, cancel := context.WithCancel(srv.ctx)
+		defer cancel()
+		if conn, ok := ctx.Conn().(closeNotifier); ok {
+			conn.notifyClose(cancel)
+		}
+		ctx.SetUserValue(userValueRequestContext, requestCtx)
+
+		if statusCode, err := decompressRequest(ctx, srv.maxRequestBodySize); err != nil {
+			ctx.Error(err.Error(), statusCode)
+			return
+		}
 		for _, before := range srv.httpBefore {
 			before(ctx)
 		}
@@ -131,16 +200,25 @@ func (srv *Server) WithTrace() *Server {
 }
 
 func (srv *Server) WithMetrics() *Server {
+
+	if srv.metrics == nil {
+		var err error
+		if srv.metrics, err = NewMetrics(srv.metricsConfig); err != nil {
+			// ServeMetrics returns error of registration
+			srv.log.WithError(err).Error("could not register metrics")
+			return srv
+		}
+	}
 	if srv.httpJsonRPC != nil {
-		srv.httpJsonRPC = srv.JsonRPC().WithMetrics()
+		srv.httpJsonRPC = srv.JsonRPC().WithMetrics(srv.metrics)
 	}
 	if srv.httpUser != nil {
-		srv.httpUser = srv.User().WithMetrics()
+		srv.httpUser = srv.User().WithMetrics(srv.metrics)
 	}
 	return srv
 }
 
-func (srv *Server) ServePPROF(address string) {
+func (srv *Server) ServePPROF(address string) (err error) {
 
 	runtime.SetBlockProfileRate(1)
 	runtime.SetMutexProfileFraction(5)
@@ -150,43 +228,76 @@ func (srv *Server) ServePPROF(address string) {
 		ReadTimeout: time.Second * 10,
 	}
 
-	go func() {
-		err := srv.srvPPROF.ListenAndServe(address)
-		ExitOnError(srv.log, err, "serve PPROF on "+address)
-	}()
+	return srv.serve(address, "PPROF", func(listener net.Listener) error {
+		return srv.srvPPROF.Serve(listener)
+	})
 }
 
-func (srv *Server) ServeHealth(address string) {
+func (srv *Server) serve(address, name string, serve func(net.Listener) error) (err error) {
 
-	srv.srvHealth = &fasthttp.Server{
-		Handler: func(ctx *fasthttp.RequestCtx) {
-			ctx.SetStatusCode(fasthttp.StatusOK)
-			_, _ = ctx.WriteString("ok")
-		},
-		ReadTimeout: time.Second * 10,
+	var listener net.Listener
+	if listener, err = net.Listen("tcp4", address); err != nil {
+		return fmt.Errorf("listen %s on %s: %w", name, address, err)
 	}
 	go func() {
-		err := srv.srvHealth.ListenAndServe(address)
-		ExitOnError(srv.log, err, "serve health on "+address)
+		if err := serve(listener); err != nil {
+			srv.log.WithError(err).Error("serve " + name + " on " + address)
+		}
 	}()
+	return
 }
 
-func (srv *Server) Shutdown() {
+func (srv *Server) Shutdown(ctx context.Context) (err error) {
 
-	if srv.srvHTTP != nil {
-		_ = srv.srvHTTP.Shutdown()
+	atomic.StoreInt32(&srv.stopping, 1)
+	if srv.shutdownDelay > 0 {
+		select {
+		case <-time.After(srv.shutdownDelay):
+		case <-ctx.Done():
+		}
 	}
 
-	if srv.srvHealth != nil {
-		_ = srv.srvHealth.Shutdown()
+	var errs shutdownErrors
+	var mtx sync.Mutex
+	collect := func(name string, err error) {
+		if err != nil {
+			mtx.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			mtx.Unlock()
+		}
 	}
 
-	if srvMetrics != nil {
-		_ = srvMetrics.Shutdown()
+	collect("http", shutdownServer(ctx, srv.srvHTTP))
+	srv.cancel()
+
+	collect("health", shutdownServer(ctx, srv.srvHealth))
+	collect("PPROF", shutdownServer(ctx, srv.srvPPROF))
+	collect("metrics", shutdownServer(ctx, srv.srvMetrics))
+
+	if srv.reporterCloser != nil {
+		collect("tracer", srv.reporterCloser.Close())
+	}
+
+	if len(errs) != 0 {
+		return errs
 	}
+	return
+}
 
-	if srv.srvPPROF != nil {
-		_ = srv.srvPPROF.Shutdown()
+func shutdownServer(ctx context.Context, server *fasthttp.Server) (err error) {
+
+	if server == nil {
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown()
+	}()
+	select {
+	case err = <-done:
+		return
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -199,10 +310,10 @@ func sendResponse(log logrus.FieldLogger, ctx *fasthttp.RequestCtx, resp interfa
 	}
 }
 
-func (srv Server) JsonRPC() *httpJsonRPC {
+func (srv *Server) JsonRPC() *httpJsonRPC {
 	return srv.httpJsonRPC
 }
 
-func (srv Server) User() *httpUser {
+func (srv *Server) User() *httpUser {
 	return srv.httpUser
 }
@@ -2,6 +2,10 @@
 package transport
 
 import (
+	"context"
+	"runtime/debug"
+	"time"
+
 	"github.com/fasthttp/router"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -11,18 +15,31 @@ import (
 )
 
 type httpUser struct {
-	log          logrus.FieldLogger
-	errorHandler ErrorHandler
-	svc          *serverUser
-	base         interfaces.User
+	log             logrus.FieldLogger
+	errorHandler    ErrorHandler
+	svc             *serverUser
+	base            interfaces.User
+	timeout         time.Duration
+	baseCtx         context.Context
+	panicHandler    PanicHandler
+	compressMinSize int
+	authenticator   Authenticator
+	metrics         *Metrics
+	cors            *CORSConfig
 }
 
 func NewUser(log logrus.FieldLogger, svcUser interfaces.User) (srv *httpUser) {
 
 	srv = &httpUser{
-		base: svcUser,
-		log:  log,
-		svc:  newServerUser(svcUser),
+		base:            svcUser,
+		compressMinSize: defaultCompressMinSize,
+		cors: &CORSConfig{
+			AllowedHeaders: []string{"Authorization", "X-Client-Id"},
+			AllowedOrigins: []string{"http://example.test"},
+			MaxAge:         600 * time.Second,
+		},
+		log: log,
+		svc: newServerUser(svcUser),
 	}
 	return
 }
@@ -41,8 +58,9 @@ func (http *httpUser) WithTrace() *httpUser {
 	return http
 }
 
-func (http *httpUser) WithMetrics() *httpUser {
-	http.svc.WithMetrics()
+func (http *httpUser) WithMetrics(metrics *Metrics) *httpUser {
+	http.metrics = metrics
+	http.svc.WithMetrics(metrics)
 	return http
 }
 
@@ -51,12 +69,75 @@ func (http *httpUser) WithErrorHandler(handler ErrorHandler) *httpUser {
 	return http
 }
 
+func (http *httpUser) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		timeout = http.timeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (http *httpUser) detach(ctx *fasthttp.RequestCtx) context.Context {
+	if http.baseCtx == nil {
+		return ctx
+	}
+	return detachedContext{
+		Context: requestContext(ctx, http.baseCtx),
+		values:  ctx,
+	}
+}
+
+func (http *httpUser) onPanic(ctx *fasthttp.RequestCtx, method string, recovered interface{}) {
+
+	stack := debug.Stack()
+	if http.metrics != nil {
+		http.metrics.panics.With("method", method, "service", "User").Add(1)
+	}
+	if http.panicHandler != nil {
+		http.panicHandler(ctx, method, recovered, stack)
+		return
+	}
+	http.log.WithFields(logrus.Fields{
+		"method":    method,
+		"requestID": ctx.UserValue(headerRequestID),
+		"service":   "User",
+		"stack":     string(stack),
+	}).Errorf("panic: %v", recovered)
+}
+
+func (http *httpUser) compress(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		compressResponse(ctx, http.compressMinSize)
+	}
+}
+
+func (http *httpUser) withCORS(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		handler(ctx)
+		http.cors.setHeaders(ctx)
+	}
+}
+
+func (http *httpUser) preflight(methods ...string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		http.cors.preflight(ctx, methods)
+	}
+}
+
 func (http *httpUser) SetRoutes(route *router.Router) {
 
-	route.GET("/api/v2/user/info", http.serveGetUser)
-	route.POST("/api/v2/user/file", http.serveUploadFile)
-	route.PATCH("/api/v2/user/custom/response", http.serveCustomResponse)
-	route.DELETE("/api/v2/user/custom", func(ctx *fasthttp.RequestCtx) {
+	route.GET("/api/v2/user/info", http.withCORS(http.compress(http.serveGetUser)))
+	route.POST("/api/v2/user/file", http.withCORS(http.compress(http.serveUploadFile)))
+	route.PATCH("/api/v2/user/custom/response", http.withCORS(http.compress(http.serveCustomResponse)))
+	route.DELETE("/api/v2/user/custom", http.withCORS(func(ctx *fasthttp.RequestCtx) {
 		implement.CustomHandler(ctx, http.base)
-	})
+	}))
+
+	route.OPTIONS("/api/v2/user/info", http.preflight("GET"))
+	route.OPTIONS("/api/v2/user/file", http.preflight("POST"))
+	route.OPTIONS("/api/v2/user/custom/response", http.preflight("PATCH"))
+	route.OPTIONS("/api/v2/user/custom", http.preflight("DELETE"))
 }
@@ -30,11 +30,11 @@ func (m loggerUser) GetUser(ctx context.Context, cookie string, userAgent string
 	defer func(begin time.Time) {
 		fields := logrus.Fields{
 			"method": "getUser",
-			"request": viewer.Sprintf("%+v", requestUserGetUser{
+			"request": viewer.Sprintf("%+v", redactLog(requestUserGetUser{
 				Cookie:    cookie,
 				UserAgent: userAgent,
-			}),
-			"response": viewer.Sprintf("%+v", responseUserGetUser{User: user}),
+			})),
+			"response": viewer.Sprintf("%+v", redactLog(responseUserGetUser{User: user})),
 			"service":  "User",
 			"took":     time.Since(begin),
 		}
@@ -54,8 +54,8 @@ func (m loggerUser) UploadFile(ctx context.Context, fileBytes []byte) (err error
 	defer func(begin time.Time) {
 		fields := logrus.Fields{
 			"method":   "uploadFile",
-			"request":  viewer.Sprintf("%+v", requestUserUploadFile{FileBytes: fileBytes}),
-			"response": viewer.Sprintf("%+v", responseUserUploadFile{}),
+			"request":  viewer.Sprintf("%+v", redactLog(requestUserUploadFile{})),
+			"response": viewer.Sprintf("%+v", redactLog(responseUserUploadFile{})),
 			"service":  "User",
 			"took":     time.Since(begin),
 		}
@@ -63,7 +63,7 @@ func (m loggerUser) UploadFile(ctx context.Context, fileBytes []byte) (err error
 			fields["requestID"] = ctx.Value(headerRequestID)
 		}
 		if err != nil {
-			m.log.WithError(err).WithFields(fields).Info("call uploadFile")
+			m.log.WithError(err).WithFields(fields).Warn("call uploadFile")
 			return
 		}
 		m.log.WithFields(fields).Info("call uploadFile")
@@ -75,12 +75,12 @@ func (m loggerUser) CustomResponse(ctx context.Context, arg0 int, arg1 string, o
 	defer func(begin time.Time) {
 		fields := logrus.Fields{
 			"method": "customResponse",
-			"request": viewer.Sprintf("%+v", requestUserCustomResponse{
+			"request": viewer.Sprintf("%+v", redactLog(requestUserCustomResponse{
 				Arg0: arg0,
 				Arg1: arg1,
 				Opts: opts,
-			}),
-			"response": viewer.Sprintf("%+v", responseUserCustomResponse{}),
+			})),
+			"response": viewer.Sprintf("%+v", redactLog(responseUserCustomResponse{})),
 			"service":  "User",
 			"took":     time.Since(begin),
 		}
@@ -100,12 +100,12 @@ func (m loggerUser) CustomHandler(ctx context.Context, arg0 int, arg1 string, op
 	defer func(begin time.Time) {
 		fields := logrus.Fields{
 			"method": "customHandler",
-			"request": viewer.Sprintf("%+v", requestUserCustomHandler{
+			"request": viewer.Sprintf("%+v", redactLog(requestUserCustomHandler{
 				Arg0: arg0,
 				Arg1: arg1,
 				Opts: opts,
-			}),
-			"response": viewer.Sprintf("%+v", responseUserCustomHandler{}),
+			})),
+			"response": viewer.Sprintf("%+v", redactLog(responseUserCustomHandler{})),
 			"service":  "User",
 			"took":     time.Since(begin),
 		}
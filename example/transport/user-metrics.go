@@ -4,27 +4,33 @@ package transport
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/valyala/fasthttp"
 
 	"github.com/seniorGolang/tg/example/interfaces"
 	"github.com/seniorGolang/tg/example/interfaces/types"
 )
 
 type metricsUser struct {
-	next            interfaces.User
-	requestCount    metrics.Counter
-	requestCountAll metrics.Counter
-	requestLatency  metrics.Histogram
+	next             interfaces.User
+	requestCount     metrics.Counter
+	requestCountAll  metrics.Counter
+	requestLatency   metrics.Histogram
+	requestsInFlight metrics.Gauge
 }
 
-func metricsMiddlewareUser(next interfaces.User) interfaces.User {
-	return &metricsUser{
-		next:            next,
-		requestCount:    RequestCount.With("service", "User"),
-		requestCountAll: RequestCountAll.With("service", "User"),
-		requestLatency:  RequestLatency.With("service", "User"),
+func metricsMiddlewareUser(metrics *Metrics) MiddlewareUser {
+	return func(next interfaces.User) interfaces.User {
+		return &metricsUser{
+			next:             next,
+			requestCount:     metrics.requestCount.With("service", "User"),
+			requestCountAll:  metrics.requestCountAll.With("service", "User"),
+			requestLatency:   metrics.requestLatency.With("service", "User"),
+			requestsInFlight: metrics.requestsInFlight.With("service", "User"),
+		}
 	}
 }
 
@@ -36,6 +42,9 @@ func (m metricsUser) GetUser(ctx context.Context, cookie string, userAgent strin
 
 	defer m.requestCount.With("method", "getUser", "success", fmt.Sprint(err == nil)).Add(1)
 
+	m.requestsInFlight.With("method", "getUser").Add(1)
+	defer m.requestsInFlight.With("method", "getUser").Add(-1)
+
 	m.requestCountAll.With("method", "getUser").Add(1)
 
 	return m.next.GetUser(ctx, cookie, userAgent)
@@ -49,6 +58,9 @@ func (m metricsUser) UploadFile(ctx context.Context, fileBytes []byte) (err erro
 
 	defer m.requestCount.With("method", "uploadFile", "success", fmt.Sprint(err == nil)).Add(1)
 
+	m.requestsInFlight.With("method", "uploadFile").Add(1)
+	defer m.requestsInFlight.With("method", "uploadFile").Add(-1)
+
 	m.requestCountAll.With("method", "uploadFile").Add(1)
 
 	return m.next.UploadFile(ctx, fileBytes)
@@ -62,6 +74,9 @@ func (m metricsUser) CustomResponse(ctx context.Context, arg0 int, arg1 string,
 
 	defer m.requestCount.With("method", "customResponse", "success", fmt.Sprint(err == nil)).Add(1)
 
+	m.requestsInFlight.With("method", "customResponse").Add(1)
+	defer m.requestsInFlight.With("method", "customResponse").Add(-1)
+
 	m.requestCountAll.With("method", "customResponse").Add(1)
 
 	return m.next.CustomResponse(ctx, arg0, arg1, opts...)
@@ -75,7 +90,20 @@ func (m metricsUser) CustomHandler(ctx context.Context, arg0 int, arg1 string, o
 
 	defer m.requestCount.With("method", "customHandler", "success", fmt.Sprint(err == nil)).Add(1)
 
+	m.requestsInFlight.With("method", "customHandler").Add(1)
+	defer m.requestsInFlight.With("method", "customHandler").Add(-1)
+
 	m.requestCountAll.With("method", "customHandler").Add(1)
 
 	return m.next.CustomHandler(ctx, arg0, arg1, opts...)
 }
+
+func (http *httpUser) observeHTTP(method string, ctx *fasthttp.RequestCtx) {
+
+	if http.metrics == nil {
+		return
+	}
+	http.metrics.requestSize.With("method", method, "service", "User").Observe(float64(len(ctx.Request.Body())))
+	http.metrics.responseSize.With("method", method, "service", "User").Observe(float64(len(ctx.Response.Body())))
+	http.metrics.httpResponses.With("method", method, "service", "User", "code", strconv.Itoa(ctx.Response.StatusCode())).Add(1)
+}
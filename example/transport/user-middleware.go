@@ -19,3 +19,36 @@ type MiddlewareUserGetUser func(next UserGetUser) UserGetUser
 type MiddlewareUserUploadFile func(next UserUploadFile) UserUploadFile
 type MiddlewareUserCustomResponse func(next UserCustomResponse) UserCustomResponse
 type MiddlewareUserCustomHandler func(next UserCustomHandler) UserCustomHandler
+
+func limitMiddlewareUserGetUser(rate *rateLimiter, concurrency *concurrencyLimiter) MiddlewareUserGetUser {
+	return func(next UserGetUser) UserGetUser {
+		return func(ctx context.Context, cookie string, userAgent string) (user *types.User, err error) {
+
+			key, _ := ctx.Value(ctxKeyLimit{}).(string)
+			if !rate.allow(key) || !concurrency.acquire(key) {
+				err = ErrLimitExceeded
+				return
+			}
+			defer concurrency.release(key)
+			return next(ctx, cookie, userAgent)
+		}
+	}
+}
+
+func limitMiddlewareUserUploadFile(rate *rateLimiter, concurrency *concurrencyLimiter) MiddlewareUserUploadFile {
+	return func(next UserUploadFile) UserUploadFile {
+		return func(ctx context.Context, fileBytes []byte) (err error) {
+
+			var key string
+			if principal, ok := PrincipalFromContext(ctx); ok {
+				key = principal.ID()
+			}
+			if !rate.allow(key) || !concurrency.acquire(key) {
+				err = ErrLimitExceeded
+				return
+			}
+			defer concurrency.release(key)
+			return next(ctx, fileBytes)
+		}
+	}
+}
@@ -4,7 +4,9 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
@@ -39,6 +41,15 @@ func (http *httpUser) serveGetUser(ctx *fasthttp.RequestCtx) {
 	span := extractSpan(http.log, fmt.Sprintf("request:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, span, ctx)
 	defer span.Finish()
+	defer http.observeHTTP("getUser", ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("msg", fmt.Sprintf("panic: %v", recovered))
+			http.onPanic(ctx, "getUser", recovered)
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+		}
+	}()
 
 	if value := ctx.Value(CtxCancelRequest); value != nil {
 		ext.Error.Set(span, true)
@@ -65,7 +76,11 @@ func (http *httpUser) serveGetUser(ctx *fasthttp.RequestCtx) {
 	var result interface{}
 
 	var response responseUserGetUser
-	response, err = http.getUser(opentracing.ContextWithSpan(ctx, span), request)
+
+	methodContext, cancel := http.withTimeout(opentracing.ContextWithSpan(http.detach(ctx), span), 5*time.Second)
+	defer cancel()
+	methodContext = context.WithValue(methodContext, ctxKeyLimit{}, string(ctx.Request.Header.Peek("X-Client-Id")))
+	response, err = http.getUser(methodContext, request)
 	result = response
 
 	if err == nil {
@@ -74,8 +89,15 @@ func (http *httpUser) serveGetUser(ctx *fasthttp.RequestCtx) {
 
 	if err != nil {
 		result = err
-		if errCoder, ok := err.(withErrorCode); ok {
+		if errors.Is(err, ErrLimitExceeded) {
+			ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+			if http.metrics != nil {
+				http.metrics.rejections.With("method", "getUser", "service", "User").Add(1)
+			}
+		} else if errCoder, ok := err.(withErrorCode); ok {
 			ctx.SetStatusCode(errCoder.Code())
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			ctx.SetStatusCode(fasthttp.StatusGatewayTimeout)
 		} else {
 			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 		}
@@ -108,6 +130,15 @@ func (http *httpUser) serveUploadFile(ctx *fasthttp.RequestCtx) {
 	span := extractSpan(http.log, fmt.Sprintf("request:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, span, ctx)
 	defer span.Finish()
+	defer http.observeHTTP("uploadFile", ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("msg", fmt.Sprintf("panic: %v", recovered))
+			http.onPanic(ctx, "uploadFile", recovered)
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+		}
+	}()
 
 	if value := ctx.Value(CtxCancelRequest); value != nil {
 		ext.Error.Set(span, true)
@@ -115,6 +146,13 @@ func (http *httpUser) serveUploadFile(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	principal, authorized := authHTTP(http.authenticator, ctx, "User", "uploadFile", []string{"bearer", "apiKey:X-Api-Key"}, []string{"admin"})
+	if !authorized {
+		ext.Error.Set(span, true)
+		span.SetTag("msg", fasthttp.StatusMessage(ctx.Response.StatusCode()))
+		return
+	}
+
 	var err error
 	var request requestUserUploadFile
 
@@ -128,7 +166,11 @@ func (http *httpUser) serveUploadFile(ctx *fasthttp.RequestCtx) {
 	var result interface{}
 
 	var response responseUserUploadFile
-	response, err = http.uploadFile(opentracing.ContextWithSpan(ctx, span), request)
+
+	methodContext, cancel := http.withTimeout(opentracing.ContextWithSpan(http.detach(ctx), span), 0)
+	defer cancel()
+	methodContext = withPrincipal(methodContext, principal)
+	response, err = http.uploadFile(methodContext, request)
 	result = response
 
 	if err == nil {
@@ -137,8 +179,15 @@ func (http *httpUser) serveUploadFile(ctx *fasthttp.RequestCtx) {
 
 	if err != nil {
 		result = err
-		if errCoder, ok := err.(withErrorCode); ok {
+		if errors.Is(err, ErrLimitExceeded) {
+			ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+			if http.metrics != nil {
+				http.metrics.rejections.With("method", "uploadFile", "service", "User").Add(1)
+			}
+		} else if errCoder, ok := err.(withErrorCode); ok {
 			ctx.SetStatusCode(errCoder.Code())
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			ctx.SetStatusCode(fasthttp.StatusGatewayTimeout)
 		} else {
 			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 		}
@@ -171,6 +220,15 @@ func (http *httpUser) serveCustomResponse(ctx *fasthttp.RequestCtx) {
 	span := extractSpan(http.log, fmt.Sprintf("request:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, span, ctx)
 	defer span.Finish()
+	defer http.observeHTTP("customResponse", ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("msg", fmt.Sprintf("panic: %v", recovered))
+			http.onPanic(ctx, "customResponse", recovered)
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+		}
+	}()
 
 	if value := ctx.Value(CtxCancelRequest); value != nil {
 		ext.Error.Set(span, true)
@@ -189,6 +247,13 @@ func (http *httpUser) serveCustomResponse(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if err = request.validate(); err != nil {
+		ext.Error.Set(span, true)
+		span.SetTag("msg", "invalid params: "+err.Error())
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		sendResponse(http.log, ctx, err)
+		return
+	}
 	implement.CustomResponseHandler(ctx, http.base, err, request.Arg0, request.Arg1, request.Opts...)
 }
 
@@ -217,6 +282,15 @@ func (http *httpUser) serveCustomHandler(ctx *fasthttp.RequestCtx) {
 	span := extractSpan(http.log, fmt.Sprintf("request:%s", gotils.B2S(ctx.URI().Path())), ctx)
 	defer injectSpan(http.log, span, ctx)
 	defer span.Finish()
+	defer http.observeHTTP("customHandler", ctx)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("msg", fmt.Sprintf("panic: %v", recovered))
+			http.onPanic(ctx, "customHandler", recovered)
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+		}
+	}()
 
 	if value := ctx.Value(CtxCancelRequest); value != nil {
 		ext.Error.Set(span, true)
@@ -238,7 +312,10 @@ func (http *httpUser) serveCustomHandler(ctx *fasthttp.RequestCtx) {
 	var result interface{}
 
 	var response responseUserCustomHandler
-	response, err = http.customHandler(opentracing.ContextWithSpan(ctx, span), request)
+
+	methodContext, cancel := http.withTimeout(opentracing.ContextWithSpan(http.detach(ctx), span), 0)
+	defer cancel()
+	response, err = http.customHandler(methodContext, request)
 	result = response
 
 	if err == nil {
@@ -249,6 +326,8 @@ func (http *httpUser) serveCustomHandler(ctx *fasthttp.RequestCtx) {
 		result = err
 		if errCoder, ok := err.(withErrorCode); ok {
 			ctx.SetStatusCode(errCoder.Code())
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			ctx.SetStatusCode(fasthttp.StatusGatewayTimeout)
 		} else {
 			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 		}
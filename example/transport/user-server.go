@@ -11,11 +11,13 @@ import (
 )
 
 type serverUser struct {
-	svc            interfaces.User
-	getUser        UserGetUser
-	uploadFile     UserUploadFile
-	customResponse UserCustomResponse
-	customHandler  UserCustomHandler
+	svc             interfaces.User
+	getUser         UserGetUser
+	uploadFile      UserUploadFile
+	customResponse  UserCustomResponse
+	customHandler   UserCustomHandler
+	limitGetUser    MiddlewareUserGetUser
+	limitUploadFile MiddlewareUserUploadFile
 }
 
 type MiddlewareSetUser interface {
@@ -26,24 +28,28 @@ type MiddlewareSetUser interface {
 	WrapCustomHandler(m MiddlewareUserCustomHandler)
 
 	WithTrace()
-	WithMetrics()
+	WithMetrics(metrics *Metrics)
 	WithLog(log logrus.FieldLogger)
 }
 
-func newServerUser(svc interfaces.User) *serverUser {
-	return &serverUser{
-		customHandler:  svc.CustomHandler,
-		customResponse: svc.CustomResponse,
-		getUser:        svc.GetUser,
-		svc:            svc,
-		uploadFile:     svc.UploadFile,
+func newServerUser(svc interfaces.User) (srv *serverUser) {
+
+	srv = &serverUser{
+		customHandler:   svc.CustomHandler,
+		customResponse:  svc.CustomResponse,
+		limitGetUser:    limitMiddlewareUserGetUser(newRateLimiter(100.0, 100), nil),
+		limitUploadFile: limitMiddlewareUserUploadFile(newRateLimiter(10.0, 20), newConcurrencyLimiter(4)),
+		svc:             svc,
 	}
+	srv.getUser = srv.limitGetUser(svc.GetUser)
+	srv.uploadFile = srv.limitUploadFile(svc.UploadFile)
+	return
 }
 
 func (srv *serverUser) Wrap(m MiddlewareUser) {
 	srv.svc = m(srv.svc)
-	srv.getUser = srv.svc.GetUser
-	srv.uploadFile = srv.svc.UploadFile
+	srv.getUser = srv.limitGetUser(srv.svc.GetUser)
+	srv.uploadFile = srv.limitUploadFile(srv.svc.UploadFile)
 	srv.customResponse = srv.svc.CustomResponse
 	srv.customHandler = srv.svc.CustomHandler
 }
@@ -84,8 +90,8 @@ func (srv *serverUser) WithTrace() {
 	srv.Wrap(traceMiddlewareUser)
 }
 
-func (srv *serverUser) WithMetrics() {
-	srv.Wrap(metricsMiddlewareUser)
+func (srv *serverUser) WithMetrics(metrics *Metrics) {
+	srv.Wrap(metricsMiddlewareUser(metrics))
 }
 
 func (srv *serverUser) WithLog(log logrus.FieldLogger) {
@@ -0,0 +1,43 @@
+// GENERATED BY 'T'ransport 'G'enerator. DO NOT EDIT.
+package transport
+
+type validationError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (e validationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+func (request requestJsonRPCTest) validate() (err error) {
+	if request.Arg0 < 1 {
+		return validationError{
+			Field:  "arg0",
+			Reason: "must be at least 1",
+		}
+	}
+	if request.Arg0 > 100 {
+		return validationError{
+			Field:  "arg0",
+			Reason: "must be at most 100",
+		}
+	}
+	if request.Arg1 == "" {
+		return validationError{
+			Field:  "arg1",
+			Reason: "is required",
+		}
+	}
+	return
+}
+
+func (request requestUserCustomResponse) validate() (err error) {
+	if request.Arg1 != "asc" && request.Arg1 != "desc" {
+		return validationError{
+			Field:  "arg1",
+			Reason: "must be one of asc, desc",
+		}
+	}
+	return
+}
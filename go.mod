@@ -3,7 +3,7 @@ module github.com/seniorGolang/tg
 go 1.16
 
 require (
-	github.com/andybalholm/brotli v1.0.1 // indirect
+	github.com/andybalholm/brotli v1.0.1
 	github.com/cpuguy83/go-md2man/v2 v2.0.0 // indirect
 	github.com/dave/jennifer v1.4.1
 	github.com/fasthttp/router v1.2.2
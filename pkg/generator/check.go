@@ -0,0 +1,551 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (check.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/tags"
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+var packageTags = utils.SliceStringToMap([]string{
+	"title", "version", "description", "servers", tagPackageUUID, tagGRPCPackage, tagTracer, tagOpenAPI, tagRPCDiscover,
+	tagEngine, tagCorsOrigins, tagCorsMethods, tagCorsHeaders, tagCorsExpose, tagCorsCreds, tagCorsMaxAge,
+})
+
+var serviceTags = utils.SliceStringToMap([]string{
+	tagServerHTTP, tagServerJsonRPC, tagServerGRPC, tagLogger, tagTrace, tagMetrics, tagTests, tagHttpPrefix, tagHttpPath, tagSwaggerTags,
+	tagTimeout, tagLogSkip, tagLogLevel, tagLogErrorLevel, tagAudience, tagAuth, tagRoles, tagRateLimit, tagBurst,
+	tagMaxConcurrent, tagLimitKey, tagCorsOrigins, tagCorsMethods, tagCorsHeaders, tagCorsExpose, tagCorsCreds, tagCorsMaxAge,
+	tagCompress,
+})
+
+var methodTags = utils.SliceStringToMap([]string{
+	tagSummary, tagDesc, tagMethodHTTP, tagHttpPath, tagHttpArg, tagHttpHeader, tagHttpCookies, tagHttpSuccess, tagUploadVars,
+	tagDownloadVars, tagHttpResponse, tagHandler, tagDeprecated, tagSwaggerTags, tagPackageUUID, tagTimeout, tagLogSkip,
+	tagLogLevel, tagLogErrorLevel, tagAudience, tagAuth, tagRoles, tagRateLimit, tagBurst, tagMaxConcurrent, tagLimitKey,
+	tagCompress,
+})
+
+var varTags = utils.SliceStringToMap([]string{
+	tagType, tagFormat, tagExample, tagDesc, tagTag, tagRequired, tagMin, tagMax, tagPattern, tagEnum,
+})
+
+var httpMethods = utils.SliceStringToMap([]string{
+	"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS",
+})
+
+type Diagnostic struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+}
+
+type docPosition struct {
+	token.Position
+	tags map[string]token.Position
+}
+
+type checker struct {
+	doc         *swagger
+	validator   *validator
+	positions   map[string]docPosition
+	diagnostics []Diagnostic
+}
+
+// Check validates package, interface and method annotations against known tags and methods signatures.
+func (tr Transport) Check() (diagnostics []Diagnostic, err error) {
+
+	c := &checker{doc: newSwagger(&tr), validator: newValidator(tr.log)}
+
+	if c.positions, err = docPositions(tr.svcDir); err != nil {
+		return
+	}
+
+	c.checkKeys(c.positions[""], tr.tags, packageTags)
+	c.checkTracer(tr)
+	c.checkCORS(c.positions[""], tr.tags)
+
+	for _, serviceName := range tr.serviceKeys() {
+
+		svc := tr.services[serviceName]
+		c.checkService(svc)
+
+		for _, method := range svc.methods {
+			c.checkMethod(svc, method)
+		}
+	}
+	sort.SliceStable(c.diagnostics, func(i, j int) bool {
+		if c.diagnostics[i].File != c.diagnostics[j].File {
+			return c.diagnostics[i].File < c.diagnostics[j].File
+		}
+		return c.diagnostics[i].Line < c.diagnostics[j].Line
+	})
+	return c.diagnostics, nil
+}
+
+func (c *checker) errorf(pos docPosition, tagName string, format string, args ...interface{}) {
+
+	if tagPos, found := pos.tags[tagName]; found {
+		pos.Position = tagPos
+	}
+	c.diagnostics = append(c.diagnostics, Diagnostic{File: pos.Filename, Line: pos.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) checkKeys(pos docPosition, docTags tags.DocTags, known map[string]int, vars ...types.Variable) {
+
+	for _, key := range sortedKeys(docTags) {
+
+		if _, found := known[key]; found {
+			continue
+		}
+		if _, err := strconv.Atoi(key); err == nil {
+			continue
+		}
+		if tokens := strings.SplitN(key, ".", 2); len(tokens) == 2 && len(vars) != 0 {
+
+			if _, found := varTags[tokens[1]]; !found {
+				c.errorf(pos, key, "unknown tag '%s'", key)
+				continue
+			}
+			if !hasVariable(vars, tokens[0]) {
+				c.errorf(pos, key, "tag '%s' refers to unknown variable '%s'", key, tokens[0])
+			}
+			continue
+		}
+		c.errorf(pos, key, "unknown tag '%s'", key)
+	}
+}
+
+func (c *checker) checkService(svc *service) {
+
+	pos := c.positions[svc.Name]
+
+	c.checkKeys(pos, svc.tags, serviceTags)
+	c.checkErrors(pos, svc.tags, !svc.tags.Contains(tagServerJsonRPC))
+	c.checkTimeout(pos, svc.tags)
+	c.checkLogLevels(pos, svc.tags)
+	c.checkAudience(pos, svc.tags)
+	c.checkAuth(pos, svc.tags)
+	c.checkLimits(pos, svc.tags)
+	c.checkCORS(pos, svc.tags)
+	c.checkCompress(pos, svc.tags)
+}
+
+func (c *checker) checkTracer(tr Transport) {
+
+	if tracer := tr.tags.Value(tagTracer, TracerOpentracing); !isKnownTracer(tracer) {
+		c.errorf(c.positions[""], tagTracer, "unknown tracer '%s', must be one of opentracing, jaeger, zipkin, otel, none", tracer)
+	}
+	if version := tr.tags.Value(tagOpenAPI, OpenAPI30); openAPIVersions[version] == "" {
+		c.errorf(c.positions[""], tagOpenAPI, "unknown OpenAPI version '%s', must be one of 3.0, 3.1", version)
+	}
+	if engine := tr.tags.Value(tagEngine, EngineFastHTTP); !isKnownEngine(engine) {
+		c.errorf(c.positions[""], tagEngine, "unknown HTTP engine '%s', must be one of %s, %s", engine, EngineFastHTTP, EngineNetHTTP)
+	}
+	if tr.tracer.isNone() {
+		for _, serviceName := range tr.serviceKeys() {
+			if tr.services[serviceName].tags.Contains(tagTrace) {
+				c.errorf(c.positions[serviceName], tagTrace, "interface %s is tagged '%s', but tracer is '%s'", serviceName, tagTrace, TracerNone)
+			}
+		}
+	}
+}
+
+func (c *checker) checkTimeout(pos docPosition, docTags tags.DocTags) {
+
+	if docTags.IsSet(tagTimeout) {
+		if timeout, err := time.ParseDuration(docTags.Value(tagTimeout)); err != nil || timeout <= 0 {
+			c.errorf(pos, tagTimeout, "invalid timeout '%s', must be positive duration like '5s' or '300ms'", docTags.Value(tagTimeout))
+		}
+	}
+}
+
+func (c *checker) checkLogLevels(pos docPosition, docTags tags.DocTags) {
+
+	for _, tagName := range []string{tagLogLevel, tagLogErrorLevel} {
+		if docTags.IsSet(tagName) {
+			if _, found := logLevels[strings.ToLower(docTags.Value(tagName))]; !found {
+				c.errorf(pos, tagName, "unknown log level '%s', must be one of debug, info, warn, error", docTags.Value(tagName))
+			}
+		}
+	}
+}
+
+func (c *checker) checkAudience(pos docPosition, docTags tags.DocTags) {
+
+	if docTags.IsSet(tagAudience) && len(swaggerAudiences(docTags.Value(tagAudience))) == 0 {
+		c.errorf(pos, tagAudience, "'%s' must contain audience names", tagAudience)
+	}
+}
+
+func (c *checker) checkAuth(pos docPosition, docTags tags.DocTags) {
+
+	if !docTags.IsSet(tagAuth) {
+		return
+	}
+	for _, scheme := range strings.Split(docTags.Value(tagAuth), "|") {
+		switch tokens := strings.SplitN(strings.TrimSpace(scheme), ":", 2); tokens[0] {
+		case authNone, authBearer, authBasic:
+		case authAPIKey:
+			if len(tokens) != 2 || strings.TrimSpace(tokens[1]) == "" {
+				c.errorf(pos, tagAuth, "scheme '%s' must contain header name, like '%s:X-Api-Key'", authAPIKey, authAPIKey)
+			}
+		default:
+			c.errorf(pos, tagAuth, "unknown auth scheme '%s', must be one of none, bearer, basic, apiKey:<header>", scheme)
+		}
+	}
+}
+
+func (c *checker) checkLimits(pos docPosition, docTags tags.DocTags) {
+
+	if docTags.IsSet(tagRateLimit) {
+		if _, err := parseRate(docTags.Value(tagRateLimit)); err != nil {
+			c.errorf(pos, tagRateLimit, "%s", err)
+		}
+	}
+	if docTags.IsSet(tagBurst) {
+		if burst, err := strconv.Atoi(docTags.Value(tagBurst)); err != nil || burst <= 0 {
+			c.errorf(pos, tagBurst, "invalid burst '%s', must be positive number", docTags.Value(tagBurst))
+		}
+	}
+	if docTags.IsSet(tagMaxConcurrent) {
+		if max, err := strconv.Atoi(docTags.Value(tagMaxConcurrent)); err != nil || max <= 0 {
+			c.errorf(pos, tagMaxConcurrent, "invalid max-concurrent '%s', must be positive number", docTags.Value(tagMaxConcurrent))
+		}
+	}
+	if docTags.IsSet(tagLimitKey) {
+		key := docTags.Value(tagLimitKey)
+		if tokens := strings.SplitN(key, ":", 2); key != limitKeyPrincipal && (len(tokens) != 2 || tokens[0] != limitKeyHeader || strings.TrimSpace(tokens[1]) == "") {
+			c.errorf(pos, tagLimitKey, "unknown limit key '%s', must be '%s' or '%s:<header>'", key, limitKeyPrincipal, limitKeyHeader)
+		}
+	}
+}
+
+func (c *checker) checkCORS(pos docPosition, docTags tags.DocTags) {
+
+	if docTags.IsSet(tagCorsOrigins) && len(corsList(docTags.Value(tagCorsOrigins))) == 0 {
+		c.errorf(pos, tagCorsOrigins, "'%s' must contain origins, like 'https://example.com,https://*.example.com' or '*'", tagCorsOrigins)
+	}
+	for _, origin := range corsList(docTags.Value(tagCorsOrigins)) {
+		if strings.Count(origin, "*") > 1 {
+			c.errorf(pos, tagCorsOrigins, "origin '%s' must contain one wildcard at most", origin)
+		}
+	}
+	for _, method := range corsList(strings.ToUpper(docTags.Value(tagCorsMethods))) {
+		if _, found := httpMethods[method]; !found && method != "*" {
+			c.errorf(pos, tagCorsMethods, "unknown HTTP method '%s' in '%s'", method, tagCorsMethods)
+		}
+	}
+	if docTags.IsSet(tagCorsCreds) {
+		if creds := docTags.Value(tagCorsCreds); creds != "" && creds != "true" && creds != "false" {
+			c.errorf(pos, tagCorsCreds, "invalid value '%s', must be true or false", creds)
+		}
+		if docTags.Value(tagCorsOrigins) == "*" && docTags.Value(tagCorsCreds) != "false" {
+			c.errorf(pos, tagCorsCreds, "'%s' with wildcard origin exposes credentials to any site, list origins instead", tagCorsCreds)
+		}
+	}
+	if docTags.IsSet(tagCorsMaxAge) {
+		if maxAge, err := time.ParseDuration(docTags.Value(tagCorsMaxAge)); err != nil || maxAge < time.Second {
+			c.errorf(pos, tagCorsMaxAge, "invalid max-age '%s', must be duration of one second at least, like '10m'", docTags.Value(tagCorsMaxAge))
+		}
+	}
+}
+
+func (c *checker) checkCompress(pos docPosition, docTags tags.DocTags) {
+
+	if compress := docTags.Value(tagCompress, "true"); compress != "" && compress != "true" && compress != "false" {
+		c.errorf(pos, tagCompress, "invalid value '%s', must be true or false", compress)
+	}
+}
+
+func (c *checker) checkMethod(svc *service, method *method) {
+
+	pos := c.positions[svc.Name+"."+method.Name]
+
+	c.checkKeys(pos, method.tags, methodTags, method.variables()...)
+	c.checkErrors(pos, method.tags, method.isHTTP())
+	c.checkTimeout(pos, method.tags)
+	c.checkLogLevels(pos, method.tags)
+	c.checkAudience(pos, method.tags)
+	c.checkAuth(pos, method.tags)
+	c.checkLimits(pos, method.tags)
+	c.checkCompress(pos, method.tags)
+	c.checkConstraints(pos, method)
+
+	if len(method.authRoles()) != 0 && len(method.authSchemes()) == 0 {
+		c.errorf(pos, tagRoles, "'%s' is set, but method %s has no '%s' tag", tagRoles, method.Name, tagAuth)
+	}
+	if method.limitKey() == limitKeyPrincipal && len(method.authSchemes()) == 0 {
+		c.errorf(pos, tagLimitKey, "'%s' is '%s', but method %s has no '%s' tag", tagLimitKey, limitKeyPrincipal, method.Name, tagAuth)
+	}
+
+	if method.tags.IsSet(tagMethodHTTP) {
+		if !svc.tags.IsSet(tagServerHTTP) {
+			c.errorf(pos, tagMethodHTTP, "'%s' is set, but interface %s has no '%s' tag", tagMethodHTTP, svc.Name, tagServerHTTP)
+		}
+		if _, found := httpMethods[method.httpMethod()]; !found {
+			c.errorf(pos, tagMethodHTTP, "unknown http method '%s'", method.tags.Value(tagMethodHTTP))
+		}
+	}
+	if method.tags.IsSet(tagHttpSuccess) {
+		if code, err := strconv.Atoi(method.tags.Value(tagHttpSuccess)); err != nil || statusText[code] == "" {
+			c.errorf(pos, tagHttpSuccess, "invalid http status code '%s'", method.tags.Value(tagHttpSuccess))
+		}
+	}
+	for _, tagName := range []string{tagHandler, tagHttpResponse} {
+		if method.tags.IsSet(tagName) {
+			if tokens := strings.Split(method.tags.Value(tagName), ":"); len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+				c.errorf(pos, tagName, "'%s' must be in format 'package/path:Name'", tagName)
+			}
+		}
+	}
+
+	for _, argName := range sortedKeys(method.argPathMap()) {
+		c.checkStringVar(pos, tagHttpPath, method, argName, method.Args)
+	}
+	for _, tagName := range []string{tagHttpArg, tagHttpHeader, tagHttpCookies, tagUploadVars, tagDownloadVars} {
+		for _, pair := range strings.Split(method.tags.Value(tagName), ",") {
+			if pair = strings.TrimSpace(pair); pair != "" && len(strings.Split(pair, "|")) != 2 {
+				c.errorf(pos, tagName, "'%s' value '%s' must be in format 'variable|name'", tagName, pair)
+			}
+		}
+	}
+	for _, argName := range sortedKeys(method.argParamMap()) {
+		c.checkStringVar(pos, tagHttpArg, method, argName, method.Args)
+	}
+	for _, varName := range sortedKeys(method.varHeaderMap()) {
+		c.checkStringVar(pos, tagHttpHeader, method, varName, method.variables())
+	}
+	for _, varName := range sortedKeys(method.varCookieMap()) {
+		c.checkStringVar(pos, tagHttpCookies, method, varName, method.variables())
+	}
+	for _, argName := range sortedKeys(method.uploadVarsMap()) {
+		c.checkBytesVar(pos, tagUploadVars, argName, method.argByName(argName))
+	}
+	for _, retName := range sortedKeys(method.downloadVarsMap()) {
+		c.checkBytesVar(pos, tagDownloadVars, retName, method.resultByName(retName))
+	}
+}
+
+func (c *checker) checkConstraints(pos docPosition, method *method) {
+
+	for _, arg := range method.argsWithoutContext() {
+
+		argTags := method.tags.Sub(arg.Name)
+
+		info := c.validator.valueOf(method.svc.pkgPath, arg.Type)
+		for info.kind == valuePointer {
+			info = c.validator.valueOf(info.pkgPath, info.next)
+		}
+		for _, tagName := range []string{tagMin, tagMax} {
+
+			if !argTags.IsSet(tagName) {
+				continue
+			}
+			switch value := argTags.Value(tagName); info.kind {
+			case valueNumber:
+				if err := numberError(info.typeName, value); err != nil {
+					c.errorf(pos, arg.Name+"."+tagName, "invalid '%s.%s' value: %s", arg.Name, tagName, err)
+				}
+			case valueString, valueList:
+				if length, err := strconv.Atoi(value); err != nil || length < 0 {
+					c.errorf(pos, arg.Name+"."+tagName, "invalid '%s.%s' value '%s', must be length", arg.Name, tagName, value)
+				}
+			case valueOther:
+				// type is not resolved, nothing to compare with
+			default:
+				c.errorf(pos, arg.Name+"."+tagName, "'%s.%s' is not applicable to type of '%s'", arg.Name, tagName, arg.Name)
+			}
+		}
+		if info.kind == valueNumber && argTags.IsSet(tagEnum) {
+			for _, item := range strings.Split(argTags.Value(tagEnum), ",") {
+				if err := numberError(info.typeName, strings.TrimSpace(item)); err != nil {
+					c.errorf(pos, arg.Name+"."+tagEnum, "invalid '%s.%s' value: %s", arg.Name, tagEnum, err)
+				}
+			}
+		}
+		if argTags.IsSet(tagPattern) {
+			if _, err := regexp.Compile(argTags.Value(tagPattern)); err != nil {
+				c.errorf(pos, arg.Name+"."+tagPattern, "invalid '%s.%s' value: %s", arg.Name, tagPattern, err)
+			}
+		}
+	}
+}
+
+func (c *checker) checkStringVar(pos docPosition, tagName string, method *method, varName string, vars []types.Variable) {
+
+	tokens := strings.Split(varName, ".")
+
+	for _, variable := range vars {
+
+		if variable.Name != tokens[0] {
+			continue
+		}
+		varType := nestedType(variable.Type, method.svc.pkgPath, tokens[1:])
+		if ptr, ok := varType.(types.TPointer); ok {
+			varType = ptr.Next
+		}
+		if varType == nil || !isStringConvertible(varType) {
+			c.errorf(pos, tagName, "'%s' variable '%s' must be string, numeric, UUID or Time", tagName, varName)
+		}
+		return
+	}
+	c.errorf(pos, tagName, "'%s' refers to unknown variable '%s' of method %s", tagName, varName, method.Name)
+}
+
+func (c *checker) checkBytesVar(pos docPosition, tagName, varName string, variable *types.Variable) {
+
+	if variable == nil {
+		c.errorf(pos, tagName, "'%s' refers to unknown variable '%s'", tagName, varName)
+		return
+	}
+	if variable.Type.String() != "[]byte" {
+		c.errorf(pos, tagName, "'%s' variable '%s' must be []byte", tagName, varName)
+	}
+}
+
+func (c *checker) checkErrors(pos docPosition, docTags tags.DocTags, httpCodes bool) {
+
+	for _, key := range sortedKeys(docTags) {
+
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		if _, found := statusText[code]; httpCodes && !found {
+			c.errorf(pos, key, "unknown http status code %d", code)
+			continue
+		}
+		value := strings.TrimSpace(docTags[key])
+		if value == "" || value == "-" || value == "skip" {
+			continue
+		}
+		tokens := strings.Split(value, ":")
+		if len(tokens) != 2 {
+			c.errorf(pos, key, "error type '%s' must be in format 'package/path:Type'", value)
+			continue
+		}
+		if c.doc.searchType(tokens[0], tokens[1]) == nil {
+			c.errorf(pos, key, "error type '%s' not found", value)
+		}
+	}
+}
+
+func hasVariable(vars []types.Variable, name string) bool {
+
+	for _, variable := range vars {
+		if variable.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// docPositions maps package (""), interfaces ("Iface") and methods ("Iface.Method") to positions of its annotations.
+func docPositions(svcDir string) (positions map[string]docPosition, err error) {
+
+	positions = make(map[string]docPosition)
+	positions[""] = docPosition{tags: make(map[string]token.Position)}
+
+	var files []os.FileInfo
+	if files, err = ioutil.ReadDir(svcDir); err != nil {
+		return
+	}
+
+	wd, _ := os.Getwd()
+	fileSet := token.NewFileSet()
+
+	for _, file := range files {
+
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".go") {
+			continue
+		}
+
+		filePath := path.Join(svcDir, file.Name())
+		if relPath, err := filepath.Rel(wd, filePath); err == nil && !strings.HasPrefix(relPath, "..") {
+			filePath = relPath
+		}
+
+		var fileAst *ast.File
+		if fileAst, err = parser.ParseFile(fileSet, filePath, nil, parser.ParseComments); err != nil {
+			return
+		}
+
+		if pkgPos := positions[""]; pkgPos.Filename == "" || fileAst.Doc != nil {
+			positions[""] = docTagPositions(fileSet, fileAst.Package, fileAst.Doc, pkgPos.tags)
+		}
+
+		for _, decl := range fileAst.Decls {
+
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+
+				typeSpec := spec.(*ast.TypeSpec)
+				iface, ok := typeSpec.Type.(*ast.InterfaceType)
+				if !ok {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = genDecl.Doc
+				}
+				positions[typeSpec.Name.Name] = docTagPositions(fileSet, typeSpec.Pos(), doc, nil)
+
+				for _, field := range iface.Methods.List {
+					for _, name := range field.Names {
+						positions[typeSpec.Name.Name+"."+name.Name] = docTagPositions(fileSet, name.Pos(), field.Doc, nil)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func docTagPositions(fileSet *token.FileSet, declPos token.Pos, doc *ast.CommentGroup, tagPositions map[string]token.Position) (pos docPosition) {
+
+	if tagPositions == nil {
+		tagPositions = make(map[string]token.Position)
+	}
+	pos = docPosition{Position: fileSet.Position(declPos), tags: tagPositions}
+
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, "@tg") {
+			continue
+		}
+		values, _ := tags.TagScanner(text[len("@tg"):])
+		for key := range values {
+			pos.tags[key] = fileSet.Position(comment.Pos())
+		}
+	}
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (check_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const checkServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+// @tg timeout=fast
+type Files interface {
+	// @tg http-method=FETCH
+	// @tg http-path=/files/{id}
+	// @tg http-args=limit|limit
+	// @tg 499=gentest/errs:Missing
+	// @tg unknown=value
+	Get(ctx context.Context, id string) (name string, err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/files
+	// @tg roles=admin
+	// @tg rate-limit=often
+	// @tg log-level=loud
+	// @tg 404=gentest/errs:Missing
+	List(ctx context.Context) (names []string, err error)
+}
+`
+
+// TestCheck checks, that errors of annotations are reported with positions of their tags.
+func TestCheck(t *testing.T) {
+
+	tr, _ := testTransport(t, map[string]string{
+		"interfaces/interface.go": checkServices,
+	})
+	diagnostics, err := tr.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reported []string
+	for _, diagnostic := range diagnostics {
+		reported = append(reported, fmt.Sprintf("%d: %s", diagnostic.Line, diagnostic.Message))
+	}
+	for _, want := range []string{
+		"6: invalid timeout 'fast'",
+		"8: unknown http method 'FETCH'",
+		"10: 'http-args' refers to unknown variable 'limit' of method Get",
+		"11: unknown http status code 499",
+		"12: unknown tag 'unknown'",
+		"17: 'roles' is set, but method List has no 'auth' tag",
+		"18: invalid rate 'often'",
+		"19: unknown log level 'loud'",
+		"20: error type 'gentest/errs:Missing' not found",
+	} {
+		found := false
+		for _, diagnostic := range reported {
+			found = found || strings.HasPrefix(diagnostic, want)
+		}
+		if !found {
+			t.Errorf("check does not report %q:\n%s", want, strings.Join(reported, "\n"))
+		}
+	}
+}
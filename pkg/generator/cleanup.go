@@ -28,18 +28,25 @@ func (tr Transport) cleanup(outDir string) {
 
 		filePath := path.Join(outDir, file.Name())
 
-		if goFile, err := os.Open(filePath); err == nil {
-
-			if firstLine, err := bufio.NewReader(goFile).ReadString('\n'); err == nil {
-
-				if strings.TrimSpace(strings.TrimPrefix(firstLine, "//")) == doNotEdit {
-					if err = os.Remove(filePath); err != nil {
-						tr.log.WithError(err).Warn("cleanup")
-					}
-				}
+		if isGenerated(filePath) {
+			if err = os.Remove(filePath); err != nil {
+				tr.log.WithError(err).Warn("cleanup")
 			}
-			_ = goFile.Close()
 		}
 	}
 	return
 }
+
+func isGenerated(filePath string) (generated bool) {
+
+	goFile, err := os.Open(filePath)
+	if err != nil {
+		return
+	}
+	defer goFile.Close()
+
+	if firstLine, err := bufio.NewReader(goFile).ReadString('\n'); err == nil {
+		generated = strings.TrimSpace(strings.TrimPrefix(firstLine, "//")) == doNotEdit
+	}
+	return
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (client-http.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+func (tr Transport) renderClientHTTP(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageUUID, "uuid")
+	srcFile.ImportName(packageLogrus, "logrus")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+	srcFile.ImportAlias(packageOpentracing, "otg")
+
+	srcFile.Line().Add(tr.httpClientStructFunc())
+	srcFile.Line().Add(tr.errorHTTP())
+
+	srcFile.Line().Func().Id("NewHTTP").Params(Id("name").String(), Id("log").Qual(packageLogrus, "FieldLogger"), Id("url").String(), Id("opts").Op("...").Id("Option")).Params(Id("cli").Op("*").Id("ClientHTTP")).Block(
+
+		Id("cli").Op("=").Op("&").Id("ClientHTTP").Values(DictFunc(func(dict Dict) {
+			dict[Id("name")] = Id("name")
+			dict[Id("log")] = Id("log")
+			dict[Id("url")] = Id("url")
+			if tr.isNetHTTP() {
+				dict[Id("clientOptions")] = Id("clientOptions").Values(Dict{Id("httpClient"): Qual(packageHttp, "DefaultClient")})
+			} else {
+				dict[Id("client")] = Qual(packageFastHttp, "Client").Values(Dict{})
+			}
+		})),
+
+		Line().For(List(Id("_"), Id("opt")).Op(":=").Range().Id("opts")).Block(
+			Id("opt").Call(Op("&").Id("cli").Dot("clientOptions")),
+		),
+		Return(),
+	)
+
+	for _, serviceName := range tr.serviceKeys() {
+		svc := tr.services[serviceName]
+		if svc.tags.Contains(tagServerHTTP) {
+			srcFile.Line().Func().Params(Id("cli").Op("*").Id("ClientHTTP")).Id(svc.Name).Params().Params(Op("*").Id(svc.clientHTTPName())).Block(
+				Return(Op("&").Id(svc.clientHTTPName()).Values(Dict{
+					Id("ClientHTTP"): Id("cli"),
+				})),
+			)
+		}
+	}
+
+	srcFile.Line().Add(tr.httpClientCallFunc())
+	srcFile.Line().Add(tr.httpClientDecodeErrorFunc())
+
+	return srcFile.Save(path.Join(outDir, "http.go"))
+}
+
+func (tr Transport) httpClientStructFunc() Code {
+
+	return Type().Id("ClientHTTP").StructFunc(func(g *Group) {
+		g.Id("clientOptions")
+		g.Line().Id("url").String()
+		g.Id("name").String()
+		g.Id("log").Qual(packageLogrus, "FieldLogger")
+		if !tr.isNetHTTP() {
+			g.Id("client").Qual(packageFastHttp, "Client")
+		}
+	})
+}
+
+func (tr Transport) errorHTTP() Code {
+
+	return Type().Id("errorHTTP").Struct(
+		Id("code").Int(),
+		Id("body").String(),
+	).
+		Line().Func().Params(Err().Id("errorHTTP")).Id("Error").Params().Params(String()).Block(
+		If(Err().Dot("body").Op("!=").Lit("")).Block(
+			Return(Err().Dot("body")),
+		),
+		Return(tr.engine.statusText(Err().Dot("code"))),
+	).
+		Line().Func().Params(Err().Id("errorHTTP")).Id("Code").Params().Params(Int()).Block(
+		Return(Err().Dot("code")),
+	)
+}
+
+// httpClientCallFunc renders sending of request, response of net/http is read with decoding of its body.
+func (tr Transport) httpClientCallFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Params(Id("cli").Op("*").Id("ClientHTTP")).Id("httpCall").
+			Params(Id(_ctx_).Qual(packageContext, "Context"), Id("span").Add(tr.tracer.spanType()), Id("request").Op("*").Qual(packageHttp, "Request"), Id("body").Op("[]").Byte()).Params(Id("response").Op("*").Id("httpResponse"), Err().Error()).Block(
+
+			Line().Add(tr.clientRequestHeaders("request")),
+
+			Line().If(Err().Op("=").Id("cli").Dot("compressRequest").Call(Id("request"), Id("body")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			),
+			Id("injectSpan").Call(Id("cli").Dot("log"), Id("span"), Id("request")),
+			Return(Id("cli").Dot("do").Call(Id("request"))),
+		)
+	}
+	return Func().Params(Id("cli").Op("*").Id("ClientHTTP")).Id("httpCall").
+		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("span").Add(tr.tracer.spanType()), Id("request").Op("*").Qual(packageFastHttp, "Request"), Id("response").Op("*").Qual(packageFastHttp, "Response")).Params(Err().Error()).Block(
+
+		Line().List(Id("requestID"), Id("_")).Op(":=").Id(_ctx_).Dot("Value").Call(Id("headerRequestID")).Op(".(").String().Op(")"),
+		If(Id("requestID").Op("==").Lit("")).Block(
+			Id("requestID").Op("=").Qual(packageUUID, "NewV4").Call().Dot("String").Call(),
+		),
+		Id("request").Dot("Header").Dot("Set").Call(Id("headerRequestID"), Id("requestID")),
+		For(List(Id("_"), Id("header")).Op(":=").Range().Id("cli").Dot("headers")).Block(
+			If(List(Id("value"), Id("ok")).Op(":=").Id(_ctx_).Dot("Value").Call(Id("header")).Op(".(").String().Op(")")).Op(";").Id("ok").Block(
+				Id("request").Dot("Header").Dot("Set").Call(Id("header"), Id("value")),
+			),
+		),
+
+		Line().Id("cli").Dot("compressRequest").Call(Id("request")),
+		Id("injectSpan").Call(Id("cli").Dot("log"), Id("span"), Id("request")),
+		If(Err().Op("=").Id("doContext").Call(Id(_ctx_), Op("&").Id("cli").Dot("client"), Id("request"), Id("response")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Return(Id("decompressResponse").Call(Id("response"))),
+	)
+}
+
+// clientRequestHeaders renders request ID and headers, which are passed from context of call.
+func (tr Transport) clientRequestHeaders(request string) Code {
+
+	return List(Id("requestID"), Id("_")).Op(":=").Id(_ctx_).Dot("Value").Call(Id("headerRequestID")).Op(".(").String().Op(")").Line().
+		If(Id("requestID").Op("==").Lit("")).Block(
+		Id("requestID").Op("=").Qual(packageUUID, "NewV4").Call().Dot("String").Call(),
+	).Line().
+		Id(request).Dot("Header").Dot("Set").Call(Id("headerRequestID"), Id("requestID")).Line().
+		For(List(Id("_"), Id("header")).Op(":=").Range().Id("cli").Dot("headers")).Block(
+		If(List(Id("value"), Id("ok")).Op(":=").Id(_ctx_).Dot("Value").Call(Id("header")).Op(".(").String().Op(")")).Op(";").Id("ok").Block(
+			Id(request).Dot("Header").Dot("Set").Call(Id("header"), Id("value")),
+		),
+	)
+}
+
+func (tr Transport) httpClientDecodeErrorFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Params(Id("cli").Op("*").Id("ClientHTTP")).Id("decodeError").
+			Params(Id("response").Op("*").Id("httpResponse")).Params(Err().Error()).Block(
+
+			Line().If(Id("cli").Dot("errorDecoder").Op("!=").Nil()).Block(
+				Return(Id("cli").Dot("errorDecoder").Call(Id("response").Dot("body"))),
+			),
+			Return(Id("errorHTTP").Values(Dict{
+				Id("code"): Id("response").Dot("StatusCode"),
+				Id("body"): String().Call(Id("response").Dot("body")),
+			})),
+		)
+	}
+	return Func().Params(Id("cli").Op("*").Id("ClientHTTP")).Id("decodeError").
+		Params(Id("response").Op("*").Qual(packageFastHttp, "Response")).Params(Err().Error()).Block(
+
+		Line().If(Id("cli").Dot("errorDecoder").Op("!=").Nil()).Block(
+			Return(Id("cli").Dot("errorDecoder").Call(Id("response").Dot("Body").Call())),
+		),
+		Return(Id("errorHTTP").Values(Dict{
+			Id("code"): Id("response").Dot("StatusCode").Call(),
+			Id("body"): String().Call(Id("response").Dot("Body").Call()),
+		})),
+	)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (client-http_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const clientHTTPServices = `package interfaces
+
+import (
+	"context"
+)
+
+// @tg http-prefix=api
+// @tg http-server
+type Files interface {
+
+	// @tg http-method=GET
+	// @tg http-path=/files/{id}
+	// @tg http-args=limit|limit
+	// @tg http-headers=token|X-Token
+	// @tg http-cookies=session|session
+	Get(ctx context.Context, id string, limit int, token string, session string) (name string, size int, err error)
+
+	// @tg http-method=POST
+	// @tg http-path=/files/slow
+	Slow(ctx context.Context) (err error)
+
+	// @tg http-method=DELETE
+	// @tg http-path=/files/custom
+	// @tg handler=gentest/implement:Custom
+	Custom(ctx context.Context, id string) (err error)
+}
+`
+
+// clientHTTPHandler reads request of custom handler by contract of swagger
+const clientHTTPHandler = `package implement
+
+import (
+	"encoding/json"
+
+	"github.com/valyala/fasthttp"
+
+	"gentest/interfaces"
+)
+
+var Deleted = make(chan string, 1)
+
+func Custom(ctx *fasthttp.RequestCtx, svc interfaces.Files) {
+
+	var request struct {
+		ID string ` + "`json:\"id\"`" + `
+	}
+	if err := json.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		return
+	}
+	Deleted <- request.ID
+}
+`
+
+const clientHTTPCheck = `package gentest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/implement"
+	"gentest/interfaces"
+	"gentest/transport"
+)
+
+// client implements whole interface, methods of custom handlers too
+var _ interfaces.Files = (*clients.ClientFiles)(nil)
+
+type files struct{}
+
+func (files) Get(ctx context.Context, id string, limit int, token string, session string) (name string, size int, err error) {
+	return fmt.Sprintf("%s/%s/%s", id, token, session), limit, nil
+}
+
+func (files) Slow(ctx context.Context) (err error) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+	}
+	return
+}
+
+func (files) Custom(ctx context.Context, id string) (err error) {
+	return
+}
+
+func TestClientHTTP(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	srv := transport.New(log, transport.Files(transport.NewFiles(log, files{})))
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+
+	cli := clients.NewHTTP("files", log, "http://"+address).Files()
+	name, size, err := cli.Get(context.Background(), "42", 7, "secret", "cookie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "42/secret/cookie" || size != 7 {
+		t.Errorf("unexpected result %s %d", name, size)
+	}
+
+	if err = cli.Custom(context.Background(), "13"); err != nil {
+		t.Fatal(err)
+	}
+	if id := <-implement.Deleted; id != "13" {
+		t.Errorf("custom handler got id %s", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err = cli.Slow(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error of canceled call: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("canceled call returned after %s", elapsed)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start = time.Now()
+	if err = cli.Slow(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error of canceled call: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("canceled call returned after %s", elapsed)
+	}
+}
+`
+
+// TestClientHTTP renders REST client and checks, that it implements interface and is canceled by context.
+func TestClientHTTP(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"client_test.go":          clientHTTPCheck,
+		"implement/custom.go":     clientHTTPHandler,
+		"interfaces/interface.go": clientHTTPServices,
+	}, nil, WithTracer(TracerNone))
+}
@@ -6,8 +6,13 @@ package generator
 import (
 	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	. "github.com/dave/jennifer/jen"
+
+	"github.com/seniorGolang/tg/pkg/tags"
 )
 
 func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
@@ -18,13 +23,14 @@ func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
 	srcFile.ImportName(packageHttp, "http")
 	srcFile.ImportName(packageJaegerlog, "log")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 	srcFile.ImportAlias(packageOpentracing, "otg")
 
 	srcFile.Line().Add(tr.jsonrpcConstants(true))
 
 	srcFile.Line().Add(tr.idJsonRPC())
-	srcFile.Type().Id("ErrorDecoder").Func().Params(Id("errData").Qual(packageJson, "RawMessage")).Params(Error())
 	srcFile.Line().Add(tr.baseJsonRPC(true))
 	srcFile.Line().Add(tr.errorJsonRPC())
 	srcFile.Line().Add(tr.jsonrpcClientStructFunc())
@@ -32,21 +38,27 @@ func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
 
 	srcFile.Line().Func().Id("New").Params(Id("name").String(), Id("log").Qual(packageLogrus, "FieldLogger"), Id("url").String(), Id("opts").Op("...").Id("Option")).Params(Id("cli").Op("*").Id("ClientJsonRPC")).Block(
 
-		Id("cli").Op("=").Op("&").Id("ClientJsonRPC").Values(Dict{
-			Id("name"):         Id("name"),
-			Id("log"):          Id("log"),
-			Id("url"):          Id("url"),
-			Id("client"):       Qual(packageFastHttp, "Client").Values(Dict{}),
-			Id("errorDecoder"): Id("defaultErrorDecoder"),
-		}),
+		Id("cli").Op("=").Op("&").Id("ClientJsonRPC").Values(DictFunc(func(dict Dict) {
+			dict[Id("name")] = Id("name")
+			dict[Id("log")] = Id("log")
+			dict[Id("url")] = Id("url")
+			if tr.isNetHTTP() {
+				dict[Id("clientOptions")] = Id("clientOptions").Values(Dict{
+					Id("httpClient"): Qual(packageHttp, "DefaultClient"),
+				})
+				return
+			}
+			dict[Id("client")] = Qual(packageFastHttp, "Client").Values(Dict{})
+		})),
 
 		Line().For(List(Id("_"), Id("opt")).Op(":=").Range().Id("opts")).Block(
-			Id("opt").Call(Id("cli")),
+			Id("opt").Call(Op("&").Id("cli").Dot("clientOptions")),
 		),
 		Return(),
 	)
 
-	for _, svc := range tr.services {
+	for _, serviceName := range tr.serviceKeys() {
+		svc := tr.services[serviceName]
 		if svc.tags.Contains(tagServerJsonRPC) {
 			srcFile.Line().Func().Params(Id("cli").Op("*").Id("ClientJsonRPC")).Id(svc.Name).Params().Params(Op("*").Id("Client" + svc.Name)).Block(
 				Return(Op("&").Id("Client" + svc.Name).Values(Dict{
@@ -56,18 +68,11 @@ func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
 		}
 	}
 
-	srcFile.Line().Func().Id("defaultErrorDecoder").Params(Id("errData").Qual(packageJson, "RawMessage")).Params(Err().Error()).Block(
-
-		Line().Var().Id("jsonrpcError").Id("errorJsonRPC"),
-		If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id("errData"), Op("&").Id("jsonrpcError")).Op(";").Err().Op("!=").Nil()).Block(
-			Return(),
-		),
-		Return(Id("jsonrpcError")),
-	)
+	srcFile.Line().Add(tr.decodeErrorJsonRPCFunc())
 
 	srcFile.Line().Func().Params(Id("cli").Op("*").Id("ClientJsonRPC")).Id("Batch").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("requests").Op("...").Id("baseJsonRPC")).Params(Err().Error()).Block(
 
-		Line().Id("span").Op(":=").Id("extractSpan").Call(Id("cli").Dot("log"), Id(_ctx_), Id("cli").Dot("name")),
+		Line().Id("span").Op(":=").Add(tr.tracer.extractSpan("cli", Id(_ctx_), Id("cli").Dot("name"))),
 		Return(Id("cli").Dot("jsonrpcCall").Call(Id(_ctx_), Id("cli").Dot("log"), Id("span"), Id("requests").Op("..."))),
 	)
 
@@ -76,7 +81,7 @@ func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
 		Line().Var().Id("requests").Id("Batch"),
 
 		Line().Id("batchFunc").Call(Op("&").Id("requests")),
-		Id("span").Op(":=").Id("extractSpan").Call(Id("cli").Dot("log"), Id(_ctx_), Id("cli").Dot("name")),
+		Id("span").Op(":=").Add(tr.tracer.extractSpan("cli", Id(_ctx_), Id("cli").Dot("name"))),
 
 		Line().Return(Id("cli").Dot("jsonrpcCall").Call(Id(_ctx_), Id("cli").Dot("log"), Id("span"), Id("requests").Op("..."))),
 	)
@@ -86,24 +91,75 @@ func (tr Transport) renderClientJsonRPC(outDir string) (err error) {
 	return srcFile.Save(path.Join(outDir, "jsonrpc.go"))
 }
 
-func (tr Transport) jsonrpcClientStructFunc() Code {
+// decodeErrorJsonRPCFunc renders decoding of jsonRPC error, typed restores error type declared for code of method.
+func (tr Transport) decodeErrorJsonRPCFunc() Code {
 
-	return Type().Id("ClientJsonRPC").Struct(
-		Id("url").String(),
-		Id("name").String(),
-		Id("log").Qual(packageLogrus, "FieldLogger"),
-		Id("client").Qual(packageFastHttp, "Client"),
-		Id("headers").Op("[]").String(),
-		Line().Id("errorDecoder").Id("ErrorDecoder"),
+	return Func().Id("decodeErrorJsonRPC").Params(Id("errData").Qual(packageJson, "RawMessage"), Id("typed").Func().Params(Id("code").Int(), Id("data").Qual(packageJson, "RawMessage")).Error()).Params(Err().Error()).Block(
+
+		Line().Var().Id("jsonrpcError").Id("errorJsonRPC"),
+		If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id("errData"), Op("&").Id("jsonrpcError")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Var().Id("typedError").Struct(
+			Id("Data").Qual(packageJson, "RawMessage").Tag(map[string]string{"json": "data"}),
+		),
+		If(Id("typed").Op("!=").Nil().Op("&&").Qual(packageJson, "Unmarshal").Call(Id("errData"), Op("&").Id("typedError")).Op("==").Nil().Op("&&").Len(Id("typedError").Dot("Data")).Op("!=").Lit(0)).Block(
+			If(Err().Op("=").Id("typed").Call(Id("jsonrpcError").Dot("Code"), Id("typedError").Dot("Data")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			),
+		),
+		Return(Id("jsonrpcError")),
 	)
 }
 
+// jsonrpcErrorTypes collects error types of method by codes, annotation of method overrides annotation of interface.
+func (svc *service) jsonrpcErrorTypes(method *method) (codes []int, errorTypes map[int]string) {
+
+	errorTypes = make(map[int]string)
+
+	for _, docTags := range []tags.DocTags{svc.tags, method.tags} {
+
+		for _, key := range sortedKeys(docTags) {
+
+			code, err := strconv.Atoi(key)
+			if err != nil {
+				continue
+			}
+			delete(errorTypes, code)
+			if tokens := strings.Split(strings.TrimSpace(docTags[key]), ":"); len(tokens) == 2 && tokens[0] != "" && tokens[1] != "" {
+				errorTypes[code] = strings.Join(tokens, ":")
+			}
+		}
+	}
+	for code := range errorTypes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return
+}
+
+func (tr Transport) jsonrpcClientStructFunc() Code {
+
+	return Type().Id("ClientJsonRPC").StructFunc(func(g *Group) {
+		g.Id("clientOptions")
+		g.Line().Id("url").String()
+		g.Id("name").String()
+		g.Id("log").Qual(packageLogrus, "FieldLogger")
+		if !tr.isNetHTTP() {
+			g.Id("client").Qual(packageFastHttp, "Client")
+		}
+	})
+}
+
 func (tr Transport) jsonrpcClientCallFunc() Code {
 
+	if tr.isNetHTTP() {
+		return tr.jsonrpcClientCallNetHTTPFunc()
+	}
 	return Func().Params(Id("cli").Op("*").Id("ClientJsonRPC")).Id("jsonrpcCall").
-		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOpentracing, "Span"), Id("requests").Op("...").Id("baseJsonRPC")).Params(Err().Error()).Block(
+		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Add(tr.tracer.spanType()), Id("requests").Op("...").Id("baseJsonRPC")).Params(Err().Error()).Block(
 
-		Line().Defer().Id("span").Dot("Finish").Call(),
+		Line().Add(tr.tracer.deferFinish("span")),
 
 		Line().Id("req").Op(":=").Qual(packageFastHttp, "AcquireRequest").Call(),
 		Id("resp").Op(":=").Qual(packageFastHttp, "AcquireResponse").Call(),
@@ -126,35 +182,73 @@ func (tr Transport) jsonrpcClientCallFunc() Code {
 			Return(),
 		),
 
-		Line().Id("injectSpan").Call(Id("log"), Id("span"), Id("req")),
-		If(Err().Op("=").Id("cli").Dot("client").Dot("Do").Call(Id("req"), Id("resp")).Op(";").Err().Op("!=").Nil()).Block(
+		Line().Id("cli").Dot("compressRequest").Call(Id("req")),
+		Id("injectSpan").Call(Id("log"), Id("span"), Id("req")),
+		If(Err().Op("=").Id("doContext").Call(Id(_ctx_), Op("&").Id("cli").Dot("client"), Id("req"), Id("resp")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		If(Err().Op("=").Id("decompressResponse").Call(Id("resp")).Op(";").Err().Op("!=").Nil()).Block(
 			Return(),
 		),
 
-		Id("responseMap").Op(":=").Make(Map(String()).Func().Params(Id("baseJsonRPC"))),
+		tr.jsonrpcClientResponses(Id("resp").Dot("Body").Call()),
+	)
+}
 
-		Line().For(List(Id("_"), Id("request")).Op(":=").Range().Id("requests")).Block(
+// jsonrpcClientCallNetHTTPFunc renders sending of batch by net/http client.
+func (tr Transport) jsonrpcClientCallNetHTTPFunc() Code {
 
-			If(Id("request").Dot("ID").Op("!=").Nil()).Block(
-				Id("responseMap").Op("[").String().Call(Id("request").Dot("ID")).Op("]").Op("=").Id("request").Dot("retHandler"),
-			),
+	return Func().Params(Id("cli").Op("*").Id("ClientJsonRPC")).Id("jsonrpcCall").
+		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Add(tr.tracer.spanType()), Id("requests").Op("...").Id("baseJsonRPC")).Params(Err().Error()).Block(
+
+		Line().Add(tr.tracer.deferFinish("span")),
+
+		Line().Var().Id("body").Op("[]").Byte(),
+		If(List(Id("body"), Err()).Op("=").Qual(packageJson, "Marshal").Call(Id("requests")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
 		),
+		Var().Id("req").Op("*").Qual(packageHttp, "Request"),
+		If(List(Id("req"), Err()).Op("=").Qual(packageHttp, "NewRequestWithContext").Call(Id(_ctx_), Qual(packageHttp, "MethodPost"), Id("cli").Dot("url"), Nil()).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Id("req").Dot("Header").Dot("Set").Call(Lit("Content-Type"), Lit(contentJSON)),
 
-		Line().Var().Id("responses").Op("[]").Id("baseJsonRPC"),
+		Line().Add(tr.clientRequestHeaders("req")),
 
-		Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id("resp").Dot("Body").Call(), Op("&").Id("responses")).Op(";").Err().Op("!=").Nil()).Block(
-			Id("cli").Dot("log").Dot("WithError").Call(Err()).Dot("WithField").Call(Lit("response"), String().Call(Id("resp").Dot("Body").Call())).Dot("Error").Call(Lit("unmarshal response error")),
+		Line().If(Err().Op("=").Id("cli").Dot("compressRequest").Call(Id("req"), Id("body")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Id("injectSpan").Call(Id("log"), Id("span"), Id("req")),
+		Var().Id("resp").Op("*").Id("httpResponse"),
+		If(List(Id("resp"), Err()).Op("=").Id("cli").Dot("do").Call(Id("req")).Op(";").Err().Op("!=").Nil()).Block(
 			Return(),
 		),
+		tr.jsonrpcClientResponses(Id("resp").Dot("body")),
+	)
+}
 
-		Line().For(List(Id("_"), Id("response")).Op(":=").Range().Id("responses")).Block(
+// jsonrpcClientResponses renders passing of responses of batch to handlers of requests.
+func (tr Transport) jsonrpcClientResponses(body Code) Code {
 
-			If(List(Id("handler"), Id("found")).Op(":=").Id("responseMap").Op("[").String().Call(Id("response").Dot("ID")).Op("]").Op(";").Id("found")).Block(
-				Id("handler").Call(Id("response")),
-			),
+	return Id("responseMap").Op(":=").Make(Map(String()).Func().Params(Id("baseJsonRPC"))).Line().
+		Line().For(List(Id("_"), Id("request")).Op(":=").Range().Id("requests")).Block(
+
+		If(Id("request").Dot("ID").Op("!=").Nil()).Block(
+			Id("responseMap").Op("[").String().Call(Id("request").Dot("ID")).Op("]").Op("=").Id("request").Dot("retHandler"),
 		),
+	).Line().
+		Line().Var().Id("responses").Op("[]").Id("baseJsonRPC").Line().
+		Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(body, Op("&").Id("responses")).Op(";").Err().Op("!=").Nil()).Block(
+		Id("cli").Dot("log").Dot("WithError").Call(Err()).Dot("WithField").Call(Lit("response"), String().Call(body)).Dot("Error").Call(Lit("unmarshal response error")),
 		Return(),
-	)
+	).Line().
+		Line().For(List(Id("_"), Id("response")).Op(":=").Range().Id("responses")).Block(
+
+		If(List(Id("handler"), Id("found")).Op(":=").Id("responseMap").Op("[").String().Call(Id("response").Dot("ID")).Op("]").Op(";").Id("found")).Block(
+			Id("handler").Call(Id("response")),
+		),
+	).Line().
+		Return()
 }
 
 func (tr Transport) jsonrpcBatchTypeFunc() Code {
@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (client-jsonrpc_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const clientJsonRPCServices = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server
+// @tg -32010=gentest/errs:Limit
+type Calc interface {
+	// @tg 1001=gentest/errs:Overflow
+	Add(ctx context.Context, a int, b int) (c int, err error)
+}
+`
+
+const clientJsonRPCErrors = `package errs
+
+import "fmt"
+
+type Overflow struct {
+	Max int ` + "`json:\"max\"`" + `
+}
+
+func (e Overflow) Error() string {
+	return fmt.Sprintf("overflow of %d", e.Max)
+}
+
+func (e Overflow) JsonRPCCode() int {
+	return 1001
+}
+
+type Limit struct {
+	Calls int ` + "`json:\"calls\"`" + `
+}
+
+func (e *Limit) Error() string {
+	return fmt.Sprintf("limit of %d calls", e.Calls)
+}
+
+func (e *Limit) JsonRPCCode() int {
+	return -32010
+}
+`
+
+const clientJsonRPCCheck = `package gentest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/errs"
+	"gentest/transport"
+)
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, a int, b int) (c int, err error) {
+	if a < 0 || b < 0 {
+		return 0, &errs.Limit{Calls: 3}
+	}
+	if c = a + b; c > 100 {
+		return 0, errs.Overflow{Max: 100}
+	}
+	return
+}
+
+func TestClientJsonRPC(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	srv := transport.New(log, transport.Calc(transport.NewCalc(log, calc{})))
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+
+	cli := clients.New("calc", log, "http://"+address).Calc()
+	if c, err := cli.Add(context.Background(), 2, 3); err != nil || c != 5 {
+		t.Fatalf("unexpected result %d: %v", c, err)
+	}
+
+	var overflow errs.Overflow
+	if _, err := cli.Add(context.Background(), 100, 1); !errors.As(err, &overflow) || overflow.Max != 100 {
+		t.Errorf("error of method code 1001 is not decoded: %#v", err)
+	}
+	var limit *errs.Limit
+	if _, err := cli.Add(context.Background(), -1, 1); !errors.As(err, &limit) || limit.Calls != 3 {
+		t.Errorf("error of interface code -32010 is not decoded: %#v", err)
+	}
+}
+`
+
+// TestClientJsonRPCErrors checks, that custom codes of jsonRPC errors pass check and are decoded by client to its types.
+func TestClientJsonRPCErrors(t *testing.T) {
+
+	t.Run("check", func(t *testing.T) {
+		tr, _ := testTransport(t, map[string]string{
+			"errs/errs.go":            clientJsonRPCErrors,
+			"interfaces/interface.go": clientJsonRPCServices,
+		})
+		diagnostics, err := tr.Check()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, diagnostic := range diagnostics {
+			t.Errorf("unexpected diagnostic: %s", diagnostic.Message)
+		}
+	})
+
+	testGenerated(t, map[string]string{
+		"client_test.go":          clientJsonRPCCheck,
+		"errs/errs.go":            clientJsonRPCErrors,
+		"interfaces/interface.go": clientJsonRPCServices,
+	}, nil, WithTracer(TracerNone))
+}
@@ -17,17 +17,130 @@ func (tr Transport) renderClientOptions(outDir string) (err error) {
 
 	srcFile.Const().Id("headerRequestID").Op("=").Lit("X-Request-Id")
 
-	srcFile.Line().Type().Id("Option").Func().Params(Id("cli").Op("*").Id("ClientJsonRPC"))
+	srcFile.Line().Type().Id("ErrorDecoder").Func().Params(Id("errData").Qual(packageJson, "RawMessage")).Params(Error())
+
+	srcFile.Line().Type().Id("clientOptions").StructFunc(func(g *Group) {
+		g.Id("headers").Op("[]").String()
+		g.Id("errorDecoder").Id("ErrorDecoder")
+		g.Id("compress").String()
+		if tr.isNetHTTP() {
+			g.Id("httpClient").Op("*").Qual(packageHttp, "Client")
+		}
+		if tr.tracer.isOTel() {
+			g.Id("tracerProvider").Qual(packageOTelTrace, "TracerProvider")
+		}
+	})
+
+	srcFile.Line().Type().Id("Option").Func().Params(Id("cli").Op("*").Id("clientOptions"))
 
 	srcFile.Line().Func().Id("DecodeError").Params(Id("decoder").Id("ErrorDecoder")).Params(Id("Option")).Block(
-		Return(Func().Params(Id("cli").Op("*").Id("ClientJsonRPC"))).Block(
+		Return(Func().Params(Id("cli").Op("*").Id("clientOptions"))).Block(
 			Id("cli").Dot("errorDecoder").Op("=").Id("decoder"),
 		),
 	)
 	srcFile.Line().Func().Id("Headers").Params(Id("headers").Op("...").String()).Params(Id("Option")).Block(
-		Return(Func().Params(Id("cli").Op("*").Id("ClientJsonRPC"))).Block(
+		Return(Func().Params(Id("cli").Op("*").Id("clientOptions"))).Block(
 			Id("cli").Dot("headers").Op("=").Id("headers"),
 		),
 	)
+	if tr.isNetHTTP() {
+		srcFile.Line().Func().Id("HTTPClient").Params(Id("client").Op("*").Qual(packageHttp, "Client")).Params(Id("Option")).Block(
+			Return(Func().Params(Id("cli").Op("*").Id("clientOptions"))).Block(
+				Id("cli").Dot("httpClient").Op("=").Id("client"),
+			),
+		)
+	}
+	if tr.isNetHTTP() {
+		srcFile.Line().Add(tr.clientDoFunc())
+	} else {
+		srcFile.Line().Add(tr.clientDoContextFunc())
+	}
+	if tr.tracer.isOTel() {
+		srcFile.Line().Comment("TracerProvider sets provider of client spans, global provider is used by default")
+		srcFile.Func().Id("TracerProvider").Params(Id("provider").Qual(packageOTelTrace, "TracerProvider")).Params(Id("Option")).Block(
+			Return(Func().Params(Id("cli").Op("*").Id("clientOptions"))).Block(
+				Id("cli").Dot("tracerProvider").Op("=").Id("provider"),
+			),
+		)
+	}
 	return srcFile.Save(path.Join(outDir, "options.go"))
 }
+
+// clientDoFunc renders sending of request by client of net/http, body of response is read and decoded by it.
+func (tr Transport) clientDoFunc() Code {
+
+	return Type().Id("httpResponse").Struct(
+		Op("*").Qual(packageHttp, "Response"),
+		Id("body").Op("[]").Byte(),
+	).
+		Line().
+		Line().Func().Params(Id("response").Op("*").Id("httpResponse")).Id("cookie").Params(Id("name").String()).String().Block(
+		For(List(Id("_"), Id("cookie")).Op(":=").Range().Id("response").Dot("Cookies").Call()).Block(
+			If(Id("cookie").Dot("Name").Op("==").Id("name")).Block(
+				Return(Id("cookie").Dot("Value")),
+			),
+		),
+		Return(Lit("")),
+	).
+		Line().
+		Line().Func().Params(Id("cli").Op("*").Id("clientOptions")).Id("do").Params(Id("request").Op("*").Qual(packageHttp, "Request")).Params(Id("response").Op("*").Id("httpResponse"), Err().Error()).Block(
+		Var().Id("resp").Op("*").Qual(packageHttp, "Response"),
+		If(List(Id("resp"), Err()).Op("=").Id("cli").Dot("httpClient").Dot("Do").Call(Id("request")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Defer().Id("resp").Dot("Body").Dot("Close").Call(),
+		Return(Id("decompressResponse").Call(Id("resp"))),
+	)
+}
+
+// clientDoContextFunc renders sending of request by fasthttp client, which could not be canceled,
+// so call is abandoned when context is done and copies of request and response are released after it.
+func (tr Transport) clientDoContextFunc() Code {
+
+	return Comment("doContext sends request, deadline of context is deadline of request, caller is returned when context is done").Line().
+		Func().Id("doContext").Params(
+		Id(_ctx_).Qual(packageContext, "Context"),
+		Id("client").Op("*").Qual(packageFastHttp, "Client"),
+		Id("request").Op("*").Qual(packageFastHttp, "Request"),
+		Id("response").Op("*").Qual(packageFastHttp, "Response"),
+	).Params(Err().Error()).Block(
+
+		Line().If(Id(_ctx_).Dot("Done").Call().Op("==").Nil()).Block(
+			Return(Id("client").Dot("Do").Call(Id("request"), Id("response"))),
+		),
+		Id("req").Op(":=").Qual(packageFastHttp, "AcquireRequest").Call(),
+		Id("resp").Op(":=").Qual(packageFastHttp, "AcquireResponse").Call(),
+		Id("request").Dot("CopyTo").Call(Id("req")),
+		Id("done").Op(":=").Make(Chan().Error(), Lit(1)),
+		List(Id("deadline"), Id("hasDeadline")).Op(":=").Id(_ctx_).Dot("Deadline").Call(),
+		Go().Func().Params().Block(
+			If(Id("hasDeadline")).Block(
+				Id("done").Op("<-").Id("client").Dot("DoDeadline").Call(Id("req"), Id("resp"), Id("deadline")),
+				Return(),
+			),
+			Id("done").Op("<-").Id("client").Dot("Do").Call(Id("req"), Id("resp")),
+		).Call(),
+		Id("release").Op(":=").Func().Params().Block(
+			Qual(packageFastHttp, "ReleaseRequest").Call(Id("req")),
+			Qual(packageFastHttp, "ReleaseResponse").Call(Id("resp")),
+		),
+		Select().Block(
+			Case(Err().Op("=").Op("<-").Id("done")).Block(
+				Comment("deadline of request is deadline of context"),
+				If(Id("hasDeadline").Op("&&").Err().Op("==").Qual(packageFastHttp, "ErrTimeout")).Block(
+					Err().Op("=").Qual(packageContext, "DeadlineExceeded"),
+				),
+				Id("resp").Dot("CopyTo").Call(Id("response")),
+				Id("release").Call(),
+				Return(),
+			),
+			Case(Op("<-").Id(_ctx_).Dot("Done").Call()).Block(
+				Go().Func().Params().Block(
+					Op("<-").Id("done"),
+					Id("release").Call(),
+				).Call(),
+				Return(Id(_ctx_).Dot("Err").Call()),
+			),
+		),
+	)
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (client-tracer-none.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+func (tr Transport) renderClientTracerNone(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageLogrus, "logrus")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Type().Id("noopSpan").Struct()
+
+	srcFile.Line().Func().Id("extractSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id(_ctx_).Qual(packageContext, "Context"), Id("opName").String()).Params(Id("span").Id("noopSpan")).Block(
+		Return(),
+	)
+
+	srcFile.Line().Func().Id("injectSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Id("noopSpan"), tr.clientRequestParam()).Params().Block()
+
+	return srcFile.Save(path.Join(outDir, "tracer.go"))
+}
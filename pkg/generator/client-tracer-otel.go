@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (client-tracer-otel.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+func (tr Transport) renderClientTracerOTel(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageHttp, "http")
+	srcFile.ImportName(packageOTel, "otel")
+	srcFile.ImportName(packageLogrus, "logrus")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+	srcFile.ImportName(packageOTelTrace, "trace")
+	srcFile.ImportName(packageOTelAttribute, "attribute")
+	srcFile.ImportName(packageOTelPropagation, "propagation")
+
+	srcFile.Const().Id("tracerName").Op("=").Lit(otelScope)
+
+	srcFile.Line().Var().Id("tracePropagator").Op("=").Qual(packageOTelPropagation, "NewCompositeTextMapPropagator").Call(
+		Qual(packageOTelPropagation, "TraceContext").Values(),
+		Qual(packageOTelPropagation, "Baggage").Values(),
+	)
+
+	srcFile.Line().Add(tr.extractSpanClientOTelFunc())
+	srcFile.Line().Add(tr.injectSpanClientOTelFunc())
+
+	return srcFile.Save(path.Join(outDir, "tracer.go"))
+}
+
+func (tr Transport) extractSpanClientOTelFunc() Code {
+
+	return Func().Id("extractSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("provider").Qual(packageOTelTrace, "TracerProvider"), Id(_ctx_).Qual(packageContext, "Context"), Id("opName").String()).Params(Id("span").Qual(packageOTelTrace, "Span")).Block(
+
+		Line().If(Op("!").Qual(packageOTelTrace, "SpanContextFromContext").Call(Id(_ctx_)).Dot("IsValid").Call()).Block(
+			Id("log").Dot("Debug").Call(Lit("context does not contain span")),
+		),
+
+		Line().If(Id("provider").Op("==").Nil()).Block(
+			Id("provider").Op("=").Qual(packageOTel, "GetTracerProvider").Call(),
+		),
+		List(Id("_"), Id("span")).Op("=").Id("provider").Dot("Tracer").Call(Id("tracerName")).Dot("Start").Call(Id(_ctx_), Id("opName"), Qual(packageOTelTrace, "WithSpanKind").Call(Qual(packageOTelTrace, "SpanKindClient"))),
+		Return(),
+	)
+}
+
+func (tr Transport) injectSpanClientOTelFunc() Code {
+
+	return Func().Id("injectSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOTelTrace, "Span"), tr.clientRequestParam()).Params().Block(
+
+		Line().Id("span").Dot("SetAttributes").Call(
+			Qual(packageOTelAttribute, "String").Call(Lit("http.request.method"), tr.clientRequestAttr("method")),
+			Qual(packageOTelAttribute, "String").Call(Lit("url.full"), tr.clientRequestAttr("url")),
+		),
+
+		Line().Id("headers").Op(":=").Make(Qual(packageHttp, "Header")),
+		Id("tracePropagator").Dot("Inject").Call(Qual(packageOTelTrace, "ContextWithSpan").Call(Qual(packageContext, "Background").Call(), Id("span")), Qual(packageOTelPropagation, "HeaderCarrier").Call(Id("headers"))),
+
+		Line().For(List(Id("key"), Id("values")).Op(":=").Range().Id("headers")).Block(
+			Id("request").Dot("Header").Dot("Del").Call(Id("key")),
+			For(List(Id("_"), Id("value")).Op(":=").Range().Id("values")).Block(
+				Id("request").Dot("Header").Dot("Add").Call(Id("key"), Id("value")),
+			),
+		),
+	)
+}
+
+func (tr Transport) clientRequestAttr(name string) Code {
+
+	if name == "method" {
+		if tr.isNetHTTP() {
+			return Id("request").Dot("Method")
+		}
+		return String().Call(Id("request").Dot("Header").Dot("Method").Call())
+	}
+	if tr.isNetHTTP() {
+		return Id("request").Dot("URL").Dot("String").Call()
+	}
+	return Id("request").Dot("URI").Call().Dot("String").Call()
+}
@@ -12,13 +12,22 @@ import (
 
 func (tr Transport) renderClientTracer(outDir string) (err error) {
 
+	if tr.tracer.isNone() {
+		return tr.renderClientTracerNone(outDir)
+	}
+	if tr.tracer.isOTel() {
+		return tr.renderClientTracerOTel(outDir)
+	}
+
 	srcFile := newSrc(filepath.Base(outDir))
 	srcFile.PackageComment(doNotEdit)
 
 	srcFile.ImportName(packageHttp, "http")
 	srcFile.ImportName(packageJaegerlog, "log")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 	srcFile.ImportAlias(packageOpentracing, "otg")
 
 	srcFile.Line().Add(tr.extractSpanClientFunc())
@@ -45,9 +54,17 @@ func (tr Transport) extractSpanClientFunc() Code {
 	)
 }
 
+func (tr Transport) clientRequestParam() Code {
+
+	if tr.isNetHTTP() {
+		return Id("request").Op("*").Qual(packageHttp, "Request")
+	}
+	return Id("request").Op("*").Qual(packageFastHttp, "Request")
+}
+
 func (tr Transport) injectSpanClientFunc() Code {
 
-	return Func().Id("injectSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOpentracing, "Span"), Id("request").Op("*").Qual(packageFastHttp, "Request")).Params().Block(
+	return Func().Id("injectSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOpentracing, "Span"), tr.clientRequestParam()).Params().Block(
 
 		Line().Id("headers").Op(":=").Make(Qual(packageHttp, "Header")),
 
@@ -5,14 +5,22 @@ package generator
 
 const (
 	packageOS                    = "os"
+	packageNet                   = "net"
+	packageTLS                   = "crypto/tls"
 	packageIO                    = "io"
+	packageErrors                = "errors"
+	packageURL                   = "net/url"
 	_ctx_                        = "ctx"
 	packageFmt                   = "fmt"
+	packageBytes                 = "bytes"
 	packageTime                  = "time"
 	_next_                       = "next"
 	packageSync                  = "sync"
+	packageAtomic                = "sync/atomic"
 	packageTesting               = "testing"
 	packageReflect               = "reflect"
+	packageDebug                 = "runtime/debug"
+	packageRegexp                = "regexp"
 	packageHttp                  = "net/http"
 	packageContext               = "context"
 	packageStrconv               = "strconv"
@@ -20,14 +28,19 @@ const (
 	packageRuntime               = "runtime"
 	packageIOUtil                = "io/ioutil"
 	packageJson                  = "encoding/json"
+	packageBase64                = "encoding/base64"
+	packageMath                  = "math"
+	packageList                  = "container/list"
+	packageGzip                  = "compress/gzip"
+	packageZlib                  = "compress/zlib"
 	packagePPROF                 = "net/http/pprof"
 	packageMultipart             = "mime/multipart"
-	packageCors                  = "github.com/lab259/cors"
 	packageUUID                  = "github.com/satori/go.uuid"
 	packageGotils                = "github.com/savsgio/gotils"
 	packageFastHttpRouter        = "github.com/fasthttp/router"
 	packageLogrus                = "github.com/sirupsen/logrus"
 	packageFastHttp              = "github.com/valyala/fasthttp"
+	packageBrotli                = "github.com/andybalholm/brotli"
 	packageGoKitMetrics          = "github.com/go-kit/kit/metrics"
 	packageGoKitEndpoint         = "github.com/go-kit/kit/endpoint"
 	packageOpenZipkin            = "github.com/openzipkin/zipkin-go"
@@ -44,4 +57,17 @@ const (
 	packageStdPrometheus         = "github.com/prometheus/client_golang/prometheus"
 	packageOpenZipkinOpenTracing = "github.com/openzipkin-contrib/zipkin-go-opentracing"
 	packagePrometheusHttp        = "github.com/prometheus/client_golang/prometheus/promhttp"
+	packageGRPC                  = "google.golang.org/grpc"
+	packageGRPCCodes             = "google.golang.org/grpc/codes"
+	packageGRPCStatus            = "google.golang.org/grpc/status"
+	packageGRPCMetadata          = "google.golang.org/grpc/metadata"
+	packageTimestamp             = "google.golang.org/protobuf/types/known/timestamppb"
+	packageOTel                  = "go.opentelemetry.io/otel"
+	packageOTelCodes             = "go.opentelemetry.io/otel/codes"
+	packageOTelTrace             = "go.opentelemetry.io/otel/trace"
+	packageOTelSDK               = "go.opentelemetry.io/otel/sdk/trace"
+	packageOTelResource          = "go.opentelemetry.io/otel/sdk/resource"
+	packageOTelAttribute         = "go.opentelemetry.io/otel/attribute"
+	packageOTelPropagation       = "go.opentelemetry.io/otel/propagation"
+	packageOTelOTLPHttp          = "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 )
@@ -0,0 +1,278 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (diff.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const diffContext = 3
+
+type FileDiff struct {
+	Path string
+	Diff string
+}
+
+// DiffServer renders transport into temporary directory and compares it with generated files in outDir.
+func (tr Transport) DiffServer(outDir string) (diffs []FileDiff, err error) {
+
+	var tmpDir string
+	if tmpDir, err = ioutil.TempDir("", "tg"); err != nil {
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// package name of generated files is taken from output directory name
+	tmpOut := path.Join(tmpDir, filepath.Base(outDir))
+	if err = os.MkdirAll(tmpOut, 0777); err != nil {
+		return
+	}
+	// imports of generated code must point to outDir, not to temporary directory
+	tr.pkgDir = outDir
+	if err = tr.RenderServer(tmpOut); err != nil {
+		return
+	}
+
+	fileNames := make(map[string]struct{})
+	for _, dir := range []string{outDir, tmpOut} {
+		// proto definitions of gRPC transport are placed to 'pb' subdirectory
+		for _, subDir := range []string{"", "pb"} {
+
+			files, _ := ioutil.ReadDir(path.Join(dir, subDir))
+			for _, file := range files {
+
+				fileName := path.Join(subDir, file.Name())
+				if !file.IsDir() && (strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, ".proto")) && isGenerated(path.Join(dir, fileName)) {
+					fileNames[fileName] = struct{}{}
+				}
+			}
+		}
+	}
+	var sorted []string
+	for fileName := range fileNames {
+		sorted = append(sorted, fileName)
+	}
+	sort.Strings(sorted)
+
+	for _, fileName := range sorted {
+
+		var diff FileDiff
+		if diff, err = diffFiles(path.Join(outDir, fileName), path.Join(tmpOut, fileName)); err != nil {
+			return
+		}
+		if diff.Diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	return
+}
+
+// DiffSwagger renders swagger into temporary file and compares it with outFile.
+func (tr Transport) DiffSwagger(outFile string) (diffs []FileDiff, err error) {
+
+	var tmpDir string
+	if tmpDir, err = ioutil.TempDir("", "tg"); err != nil {
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := path.Join(tmpDir, filepath.Base(outFile))
+	if err = tr.RenderSwagger(tmpFile); err != nil {
+		return
+	}
+
+	var diff FileDiff
+	if diff, err = diffFiles(outFile, tmpFile); err == nil && diff.Diff != "" {
+		diffs = append(diffs, diff)
+	}
+	return
+}
+
+func diffFiles(diskPath, renderedPath string) (diff FileDiff, err error) {
+
+	diff.Path = diskPath
+
+	var onDisk, rendered []byte
+	if onDisk, err = ioutil.ReadFile(diskPath); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if rendered, err = ioutil.ReadFile(renderedPath); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	err = nil
+
+	oldName, newName := path.Join("a", diskPath), path.Join("b", diskPath)
+	if onDisk == nil {
+		oldName = "/dev/null"
+	}
+	if rendered == nil {
+		newName = "/dev/null"
+	}
+	diff.Diff = unifiedDiff(oldName, newName, string(onDisk), string(rendered))
+	return
+}
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// positions of old and new lines before each operation
+	oldPos := make([]int, len(ops)+1)
+	newPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if op.kind != '+' {
+			oldPos[i+1]++
+		}
+		if op.kind != '-' {
+			newPos[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	_, _ = fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := 0; i < len(ops); {
+
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				if end += diffContext; end > len(ops) {
+					end = len(ops)
+				}
+				break
+			}
+			end = next
+		}
+
+		oldStart, oldCount := oldPos[start]+1, oldPos[end]-oldPos[start]
+		newStart, newCount := newPos[start]+1, newPos[end]-newPos[start]
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		_, _ = fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+		for _, op := range ops[start:end] {
+			_, _ = fmt.Fprintf(&out, "%c%s\n", op.kind, op.line)
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// diffLines finds shortest edit script by Myers algorithm.
+func diffLines(a, b []string) (ops []diffOp) {
+
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+
+		v = trace[d]
+		k := x - y
+
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+			x, y = x-1, y-1
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{kind: '+', line: b[y-1]})
+			y--
+		} else {
+			ops = append(ops, diffOp{kind: '-', line: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{kind: ' ', line: a[x-1]})
+		x, y = x-1, y-1
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return
+}
+
+func splitLines(text string) (lines []string) {
+
+	if text == "" {
+		return
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
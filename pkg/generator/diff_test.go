@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (diff_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		diff    string
+	}{
+		{
+			name:    "equal",
+			oldText: "a\nb\n",
+			newText: "a\nb\n",
+		},
+		{
+			name: "both empty",
+		},
+		{
+			name:    "created file",
+			newText: "a\nb\n",
+			diff:    "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "removed file",
+			oldText: "a\nb\n",
+			diff:    "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name:    "insert",
+			oldText: "a\nc\n",
+			newText: "a\nb\nc\n",
+			diff:    "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+		{
+			name:    "delete",
+			oldText: "a\nb\nc\n",
+			newText: "a\nc\n",
+			diff:    "--- old\n+++ new\n@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+		},
+		{
+			name:    "replace",
+			oldText: "a\nb\nc\n",
+			newText: "a\nx\nc\n",
+			diff:    "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name:    "context trimming",
+			oldText: lines(1, 20),
+			newText: strings.Replace(lines(1, 20), "10\n", "ten\n", 1),
+			diff:    "--- old\n+++ new\n@@ -7,7 +7,7 @@\n 7\n 8\n 9\n-10\n+ten\n 11\n 12\n 13\n",
+		},
+		{
+			name:    "separate hunks",
+			oldText: lines(1, 20),
+			newText: strings.Replace(strings.Replace(lines(1, 20), "2\n", "two\n", 1), "19\n", "nineteen\n", 1),
+			diff: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -16,5 +16,5 @@\n 16\n 17\n 18\n-19\n+nineteen\n 20\n",
+		},
+		{
+			name:    "merged hunks",
+			oldText: lines(1, 20),
+			newText: strings.Replace(strings.Replace(lines(1, 20), "5\n", "five\n", 1), "10\n", "ten\n", 1),
+			diff:    "--- old\n+++ new\n@@ -2,12 +2,12 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n 9\n-10\n+ten\n 11\n 12\n 13\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := unifiedDiff("old", "new", test.oldText, test.newText); diff != test.diff {
+				t.Errorf("unexpected diff:\n%s\nwant:\n%s", diff, test.diff)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+
+	tests := []struct {
+		name string
+		a, b []string
+		ops  string
+	}{
+		{name: "empty"},
+		{name: "insert only", b: []string{"a", "b"}, ops: "++"},
+		{name: "delete only", a: []string{"a", "b"}, ops: "--"},
+		{name: "insert middle", a: []string{"a", "c"}, b: []string{"a", "b", "c"}, ops: " + "},
+		{name: "delete middle", a: []string{"a", "b", "c"}, b: []string{"a", "c"}, ops: " - "},
+		{name: "shortest script", a: []string{"a", "b", "c", "a", "b", "b", "a"}, b: []string{"c", "b", "a", "b", "a", "c"}, ops: "-- +  - +"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var kinds strings.Builder
+			var oldLines, newLines []string
+			for _, op := range diffLines(test.a, test.b) {
+				kinds.WriteByte(op.kind)
+				if op.kind != '+' {
+					oldLines = append(oldLines, op.line)
+				}
+				if op.kind != '-' {
+					newLines = append(newLines, op.line)
+				}
+			}
+			if strings.Join(oldLines, "\n") != strings.Join(test.a, "\n") || strings.Join(newLines, "\n") != strings.Join(test.b, "\n") {
+				t.Errorf("script does not transform %q to %q", test.a, test.b)
+			}
+			if edits := strings.Count(kinds.String(), "+") + strings.Count(kinds.String(), "-"); edits != strings.Count(test.ops, "+")+strings.Count(test.ops, "-") {
+				t.Errorf("script %q is not shortest, want %q", kinds.String(), test.ops)
+			}
+		})
+	}
+}
+
+func lines(from, to int) string {
+
+	var text strings.Builder
+	for i := from; i <= to; i++ {
+		text.WriteString(strconv.Itoa(i))
+		text.WriteByte('\n')
+	}
+	return text.String()
+}
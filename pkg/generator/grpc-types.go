@@ -0,0 +1,375 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (grpc-types.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/vetcher/go-astra/types"
+)
+
+type protoKind int
+
+const (
+	kindScalar protoKind = iota
+	kindBytes
+	kindTime
+	kindUUID
+	kindMessage
+	kindPointer
+	kindList
+	kindMap
+	kindJSON
+)
+
+type protoInfo struct {
+	kind    protoKind
+	pType   string
+	pkgPath string
+	next    types.Type
+	key     types.Type
+}
+
+var protoScalars = map[string]string{
+	"bool":    "bool",
+	"string":  "string",
+	"int":     "int64",
+	"int64":   "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"rune":    "int32",
+	"uint":    "uint64",
+	"uint64":  "uint64",
+	"uintptr": "uint64",
+	"byte":    "uint32",
+	"uint8":   "uint32",
+	"uint16":  "uint32",
+	"uint32":  "uint32",
+	"float32": "float",
+	"float64": "double",
+}
+
+var protoGoScalars = map[string]string{
+	"bool":   "bool",
+	"string": "string",
+	"int64":  "int64",
+	"int32":  "int32",
+	"uint64": "uint64",
+	"uint32": "uint32",
+	"float":  "float32",
+	"double": "float64",
+}
+
+// protoInfo resolves how Go type is represented in proto message.
+func (gen *grpcGen) protoInfo(pkgPath string, vType types.Type) (info protoInfo) {
+
+	info = protoInfo{kind: kindJSON, pType: "bytes", pkgPath: pkgPath}
+
+	switch vType.String() {
+	case "time.Time":
+		gen.useTimestamp = true
+		return protoInfo{kind: kindTime, pType: "google.protobuf.Timestamp"}
+	case "[]byte", "json.RawMessage":
+		return protoInfo{kind: kindBytes, pType: "bytes"}
+	}
+
+	switch vType := vType.(type) {
+
+	case types.TName:
+
+		if pType, found := protoScalars[vType.TypeName]; found {
+			return protoInfo{kind: kindScalar, pType: pType}
+		}
+		if !types.IsBuiltin(vType) {
+			return gen.namedInfo(pkgPath, vType.TypeName)
+		}
+
+	case types.TImport:
+
+		if vType.Next.String() == "UUID" {
+			return protoInfo{kind: kindUUID, pType: "string", pkgPath: vType.Import.Package}
+		}
+		return gen.namedInfo(vType.Import.Package, vType.Next.String())
+
+	case types.TPointer:
+
+		next := gen.protoInfo(pkgPath, vType.Next)
+		if next.kind == kindList || next.kind == kindMap || next.kind == kindPointer {
+			return
+		}
+		return protoInfo{kind: kindPointer, pType: next.pType, pkgPath: pkgPath, next: vType.Next}
+
+	case types.TArray:
+
+		if !vType.IsSlice {
+			return
+		}
+		return gen.listInfo(pkgPath, vType.Next)
+
+	case types.TEllipsis:
+
+		return gen.listInfo(pkgPath, vType.Next)
+
+	case types.TMap:
+
+		key := gen.protoInfo(pkgPath, vType.Key)
+		value := gen.protoInfo(pkgPath, vType.Value)
+		if key.kind != kindScalar || key.pType == "float" || key.pType == "double" || value.kind == kindList || value.kind == kindMap {
+			return
+		}
+		return protoInfo{kind: kindMap, pType: fmt.Sprintf("map<%s, %s>", key.pType, value.pType), pkgPath: pkgPath, key: vType.Key, next: vType.Value}
+	}
+	return
+}
+
+func (gen *grpcGen) listInfo(pkgPath string, next types.Type) (info protoInfo) {
+
+	item := gen.protoInfo(pkgPath, next)
+	if item.kind == kindList || item.kind == kindMap {
+		return protoInfo{kind: kindJSON, pType: "bytes", pkgPath: pkgPath}
+	}
+	return protoInfo{kind: kindList, pType: "repeated " + item.pType, pkgPath: pkgPath, next: next}
+}
+
+func (gen *grpcGen) namedInfo(pkgPath, typeName string) (info protoInfo) {
+
+	key := pkgPath + "." + typeName
+	if info, found := gen.namedTypes[key]; found {
+		return info
+	}
+	defer func() { gen.namedTypes[key] = info }()
+
+	info = protoInfo{kind: kindJSON, pType: "bytes", pkgPath: pkgPath}
+
+	switch nextType := searchType(pkgPath, typeName).(type) {
+	case nil:
+	case types.Struct:
+		return protoInfo{kind: kindMessage, pType: gen.structMessage(pkgPath, typeName, nextType), pkgPath: pkgPath}
+	case types.TName:
+		// named scalar types are casted to its underlying type
+		if pType, found := protoScalars[nextType.TypeName]; found {
+			return protoInfo{kind: kindScalar, pType: pType}
+		}
+	}
+	return
+}
+
+func (gen *grpcGen) protoType(pkgPath string, vType types.Type) string {
+	return gen.protoInfo(pkgPath, vType).pType
+}
+
+// pbGoType returns Go type of field generated by protoc-gen-go.
+func (gen *grpcGen) pbGoType(pkgPath string, vType types.Type) Code {
+
+	info := gen.protoInfo(pkgPath, vType)
+
+	switch info.kind {
+	case kindScalar:
+		return Id(protoGoScalars[info.pType])
+	case kindUUID:
+		return String()
+	case kindTime:
+		return Op("*").Qual(packageTimestamp, "Timestamp")
+	case kindMessage:
+		return Op("*").Qual(gen.pbPkg, goCamelCase(info.pType))
+	case kindPointer:
+		return gen.pbGoType(pkgPath, info.next)
+	case kindList:
+		return Index().Add(gen.pbGoType(pkgPath, info.next))
+	case kindMap:
+		return Map(gen.pbGoType(pkgPath, info.key)).Add(gen.pbGoType(pkgPath, info.next))
+	}
+	return Index().Byte()
+}
+
+func (gen *grpcGen) goType(pkgPath string, vType types.Type) Code {
+
+	switch vType := vType.(type) {
+	case types.TName:
+		if !types.IsBuiltin(vType) {
+			return Qual(pkgPath, vType.TypeName)
+		}
+	case types.TPointer:
+		return Op("*").Add(gen.goType(pkgPath, vType.Next))
+	case types.TArray:
+		if vType.IsSlice {
+			return Index().Add(gen.goType(pkgPath, vType.Next))
+		}
+	case types.TEllipsis:
+		return Index().Add(gen.goType(pkgPath, vType.Next))
+	case types.TMap:
+		return Map(gen.goType(pkgPath, vType.Key)).Add(gen.goType(pkgPath, vType.Value))
+	}
+	return fieldType(gen.ctx, vType, false)
+}
+
+// valueVar returns name of temporary variable unique in generated function.
+func (gen *grpcGen) valueVar() string {
+	gen.values++
+	return fmt.Sprintf("value%d", gen.values)
+}
+
+func (gen *grpcGen) vars(prefix string) (string, func()) {
+	gen.depth++
+	return fmt.Sprintf("%s%d", prefix, gen.depth), func() { gen.depth-- }
+}
+
+// toProto returns statements which assign Go value src converted to proto representation to dst.
+func (gen *grpcGen) toProto(pkgPath string, vType types.Type, dst, src Code, onErr Code) Code {
+
+	info := gen.protoInfo(pkgPath, vType)
+
+	switch info.kind {
+
+	case kindScalar:
+
+		return Add(dst).Op("=").Id(protoGoScalars[info.pType]).Call(src)
+
+	case kindBytes:
+
+		return Add(dst).Op("=").Index().Byte().Call(src)
+
+	case kindUUID:
+
+		return Add(dst).Op("=").Add(src).Dot("String").Call()
+
+	case kindTime:
+
+		return Add(dst).Op("=").Qual(packageTimestamp, "New").Call(src)
+
+	case kindMessage:
+
+		return If(List(dst, Err()).Op("=").Id("toProto" + info.pType).Call(Op("&").Add(src)).Op(";").Err().Op("!=").Nil()).Block(onErr)
+
+	case kindPointer:
+
+		switch next := gen.protoInfo(pkgPath, info.next); next.kind {
+		case kindMessage:
+			return If(List(dst, Err()).Op("=").Id("toProto" + next.pType).Call(src).Op(";").Err().Op("!=").Nil()).Block(onErr)
+		case kindUUID:
+			return If(Add(src).Op("!=").Nil()).Block(
+				Add(dst).Op("=").Add(src).Dot("String").Call(),
+			)
+		}
+		return If(Add(src).Op("!=").Nil()).Block(
+			gen.toProto(pkgPath, info.next, dst, Op("*").Add(src), onErr),
+		)
+
+	case kindList:
+
+		item, done := gen.vars("item")
+		defer done()
+
+		return Add(dst).Op("=").Make(gen.pbGoType(pkgPath, vType), Lit(0), Len(src)).Line().
+			For(List(Id("_"), Id(item)).Op(":=").Range().Add(src)).Block(
+			Var().Id("pb"+item).Add(gen.pbGoType(pkgPath, info.next)),
+			gen.toProto(pkgPath, info.next, Id("pb"+item), Id(item), onErr),
+			Add(dst).Op("=").Append(dst, Id("pb"+item)),
+		)
+
+	case kindMap:
+
+		item, done := gen.vars("item")
+		defer done()
+		key := "key" + item[len("item"):]
+
+		return Add(dst).Op("=").Make(gen.pbGoType(pkgPath, vType), Len(src)).Line().
+			For(List(Id(key), Id(item)).Op(":=").Range().Add(src)).Block(
+			Var().Id("pb"+key).Add(gen.pbGoType(pkgPath, info.key)),
+			Var().Id("pb"+item).Add(gen.pbGoType(pkgPath, info.next)),
+			gen.toProto(pkgPath, info.key, Id("pb"+key), Id(key), onErr),
+			gen.toProto(pkgPath, info.next, Id("pb"+item), Id(item), onErr),
+			Add(dst).Index(Id("pb"+key)).Op("=").Id("pb"+item),
+		)
+	}
+	return If(List(dst, Err()).Op("=").Qual(packageJson, "Marshal").Call(src).Op(";").Err().Op("!=").Nil()).Block(onErr)
+}
+
+// fromProto returns statements which assign proto value src converted to Go type to dst.
+func (gen *grpcGen) fromProto(pkgPath string, vType types.Type, dst, src Code, onErr Code) Code {
+
+	info := gen.protoInfo(pkgPath, vType)
+
+	switch info.kind {
+
+	case kindScalar, kindBytes:
+
+		return Add(dst).Op("=").Add(gen.goType(pkgPath, vType)).Call(src)
+
+	case kindUUID:
+
+		return Add(dst).Op("=").Qual(info.pkgPath, "FromStringOrNil").Call(src)
+
+	case kindTime:
+
+		return If(Add(src).Op("!=").Nil()).Block(
+			Add(dst).Op("=").Add(src).Dot("AsTime").Call(),
+		)
+
+	case kindMessage:
+
+		value := gen.valueVar()
+
+		return Var().Id(value).Op("*").Add(gen.goType(pkgPath, vType)).Line().
+			If(List(Id(value), Err()).Op("=").Id("fromProto" + info.pType).Call(src).Op(";").Err().Op("!=").Nil()).Block(onErr).Line().
+			If(Id(value).Op("!=").Nil()).Block(
+			Add(dst).Op("=").Op("*").Id(value),
+		)
+
+	case kindPointer:
+
+		next := gen.protoInfo(pkgPath, info.next)
+		if next.kind == kindMessage {
+			return If(List(dst, Err()).Op("=").Id("fromProto" + next.pType).Call(src).Op(";").Err().Op("!=").Nil()).Block(onErr)
+		}
+
+		value := gen.valueVar()
+
+		if next.kind == kindTime {
+			return If(Add(src).Op("!=").Nil()).Block(
+				Id(value).Op(":=").Add(src).Dot("AsTime").Call(),
+				Add(dst).Op("=").Op("&").Id(value),
+			)
+		}
+		return Var().Id(value).Add(gen.goType(pkgPath, info.next)).Line().
+			Add(gen.fromProto(pkgPath, info.next, Id(value), src, onErr)).Line().
+			Add(dst).Op("=").Op("&").Id(value)
+
+	case kindList:
+
+		item, done := gen.vars("item")
+		defer done()
+
+		return If(Len(src).Op("!=").Lit(0)).Block(
+			Add(dst).Op("=").Make(gen.goType(pkgPath, vType), Lit(0), Len(src)),
+			For(List(Id("_"), Id(item)).Op(":=").Range().Add(src)).Block(
+				Var().Id("go"+item).Add(gen.goType(pkgPath, info.next)),
+				gen.fromProto(pkgPath, info.next, Id("go"+item), Id(item), onErr),
+				Add(dst).Op("=").Append(dst, Id("go"+item)),
+			),
+		)
+
+	case kindMap:
+
+		item, done := gen.vars("item")
+		defer done()
+		key := "key" + item[len("item"):]
+
+		return If(Len(src).Op("!=").Lit(0)).Block(
+			Add(dst).Op("=").Make(gen.goType(pkgPath, vType), Len(src)),
+			For(List(Id(key), Id(item)).Op(":=").Range().Add(src)).Block(
+				Var().Id("go"+key).Add(gen.goType(pkgPath, info.key)),
+				Var().Id("go"+item).Add(gen.goType(pkgPath, info.next)),
+				gen.fromProto(pkgPath, info.key, Id("go"+key), Id(key), onErr),
+				gen.fromProto(pkgPath, info.next, Id("go"+item), Id(item), onErr),
+				Add(dst).Index(Id("go"+key)).Op("=").Id("go"+item),
+			),
+		)
+	}
+	return If(Len(src).Op("!=").Lit(0)).Block(
+		If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(src, Op("&").Add(dst)).Op(";").Err().Op("!=").Nil()).Block(onErr),
+	)
+}
@@ -6,6 +6,7 @@ package generator
 import (
 	"path"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vetcher/go-astra/types"
@@ -36,6 +37,8 @@ type method struct {
 	cookieToVar map[string]string
 	cookieToArg map[string]string
 	cookieToRet map[string]string
+
+	hasValidation bool
 }
 
 func newMethod(log logrus.FieldLogger, svc *service, fn *types.Function) (m *method) {
@@ -59,6 +62,26 @@ func (m method) lccName() string {
 	return utils.ToLowerCamel(m.Name)
 }
 
+// timeout returns duration from method or interface annotation, zero means server default.
+func (m method) timeout() (timeout time.Duration) {
+	timeout, _ = time.ParseDuration(m.tags.Value(tagTimeout, m.svc.tags.Value(tagTimeout, "0")))
+	return
+}
+
+func (m method) timeoutCode() Code {
+
+	timeout := m.timeout()
+	switch {
+	case timeout <= 0:
+		return Lit(0)
+	case timeout%time.Second == 0:
+		return Lit(int(timeout/time.Second)).Op("*").Qual(packageTime, "Second")
+	case timeout%time.Millisecond == 0:
+		return Lit(int(timeout/time.Millisecond)).Op("*").Qual(packageTime, "Millisecond")
+	}
+	return Qual(packageTime, "Duration").Call(Lit(int64(timeout)))
+}
+
 func (m method) requestStructName() string {
 	return "request" + m.svc.Name + m.Name
 }
@@ -103,7 +126,7 @@ func (m *method) uploadVarsMap() (headers map[string]string) {
 func (m *method) downloadVarsMap() (headers map[string]string) {
 
 	if m.downloadVars != nil {
-		return m.uploadVars
+		return m.downloadVars
 	}
 
 	m.downloadVars = make(map[string]string)
@@ -236,7 +259,7 @@ func (m method) urlArgs(errStatement func(arg, header string) *Statement) (g *St
 
 	return m.argFromString("urlParam", m.argPathMap(),
 		func(srcName string) Code {
-			return Id(_ctx_).Dot("UserValue").Call(Lit(srcName)).Op(".").Call(String())
+			return m.svc.engine.pathValue(Lit(srcName))
 		},
 		errStatement,
 	)
@@ -246,7 +269,7 @@ func (m method) urlParams(errStatement func(arg, header string) *Statement) (blo
 
 	return m.argFromString("urlParam", m.argParamMap(),
 		func(srcName string) Code {
-			return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("QueryArgs").Call().Dot("Peek").Call(Lit(srcName)))
+			return m.svc.engine.queryArg(Lit(srcName))
 		},
 		errStatement,
 	)
@@ -256,7 +279,7 @@ func (m method) httpArgHeaders(errStatement func(arg, header string) *Statement)
 
 	return m.argFromString("header", m.varHeaderMap(),
 		func(srcName string) Code {
-			return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Lit(srcName)))
+			return m.svc.engine.requestHeader(Lit(srcName))
 		},
 		errStatement,
 	)
@@ -266,7 +289,7 @@ func (m method) httpCookies(errStatement func(arg, header string) *Statement) (b
 
 	return m.argFromString("cookie", m.varCookieMap(),
 		func(srcName string) Code {
-			return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Cookie").Call(Lit(srcName)))
+			return m.svc.engine.requestCookie(Lit(srcName))
 		},
 		errStatement,
 	)
@@ -330,7 +353,7 @@ func (m method) httpRetHeaders() (block *Statement) {
 				continue
 			}
 			block.If(Id("response").Dot(utils.ToCamel(ret)).Op("!=").Lit("").Block(
-				Id(_ctx_).Dot("Response").Dot("Header").Dot("Set").Call(Lit(header), Id("response").Dot(utils.ToCamel(ret))),
+				m.svc.engine.responseHeader("Set", Lit(header), Id("response").Dot(utils.ToCamel(ret))),
 			))
 		}
 	}
@@ -503,6 +526,12 @@ func (m method) argsWithoutContext() (args []types.Variable) {
 	return m.Args
 }
 
+func (m method) variables() (vars []types.Variable) {
+
+	vars = append(vars, m.Args...)
+	return append(vars, m.Results...)
+}
+
 func (m method) argToTypeConverter(from *Statement, vType types.Type, id *Statement, errStatement *Statement) *Statement {
 
 	op := "="
@@ -535,6 +564,74 @@ func (m method) argToTypeConverter(from *Statement, vType types.Type, id *Statem
 	return Line().Add(from)
 }
 
+func (m method) argsToString(varMap map[string]string, setFn func(srcName string, value Code) Code) (block *Statement) {
+
+	block = Line()
+	for _, argName := range sortedKeys(varMap) {
+		srcName := varMap[argName]
+		argTokens := strings.Split(argName, ".")
+		vArg := m.argByName(argTokens[0])
+		if vArg == nil {
+			continue
+		}
+		argID := func() *Statement {
+			id := Id(utils.ToLowerCamel(argTokens[0]))
+			for _, token := range argTokens[1:] {
+				id.Dot(token)
+			}
+			return id
+		}
+		argType := vArg.Type
+		if len(argTokens) > 1 {
+			argType = nestedType(vArg.Type, "", argTokens)
+		}
+		if t, ok := argType.(types.TPointer); ok {
+			block.If(argID().Op("!=").Nil()).Block(
+				setFn(srcName, m.valueToString(Parens(Op("*").Add(argID())), t.NextType())),
+			)
+			continue
+		}
+		block.Add(setFn(srcName, m.valueToString(argID(), argType))).Line()
+	}
+	return
+}
+
+func (m method) valueToString(id *Statement, vType types.Type) *Statement {
+
+	typename := types.TypeName(vType)
+	if typename == nil {
+		return Qual(packageFmt, "Sprint").Call(id)
+	}
+	switch *typename {
+	case "string":
+		return id
+	case "int":
+		return Qual(packageStrconv, "Itoa").Call(id)
+	case "int64", "int32":
+		return Qual(packageStrconv, "FormatInt").Call(Int64().Call(id), Lit(10))
+	case "uint", "uint64", "uint32":
+		return Qual(packageStrconv, "FormatUint").Call(Uint64().Call(id), Lit(10))
+	case "UUID":
+		return id.Dot("String").Call()
+	case "Time":
+		return id.Dot("Format").Call(Qual(packageTime, "RFC3339Nano"))
+	}
+	return Qual(packageFmt, "Sprint").Call(id)
+}
+
+func isStringConvertible(vType types.Type) bool {
+
+	typename := types.TypeName(vType)
+	if typename == nil {
+		return false
+	}
+	switch *typename {
+	case "string", "int", "int64", "int32", "uint", "uint64", "uint32", "UUID", "Time":
+		return true
+	}
+	return false
+}
+
 func (m method) varsToFields(vars []types.Variable, tags tags.DocTags, excludes ...map[string]string) (fields []types.StructField) {
 
 	for _, variable := range vars {
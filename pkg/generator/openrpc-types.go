@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (openrpc-types.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+type orObject struct {
+	OpenRPC    string       `json:"openrpc" yaml:"openrpc"`
+	Info       swInfo       `json:"info" yaml:"info"`
+	Servers    []orServer   `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Methods    []orMethod   `json:"methods" yaml:"methods"`
+	Components orComponents `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+type orServer struct {
+	Name    string `json:"name" yaml:"name"`
+	URL     string `json:"url" yaml:"url"`
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+}
+
+type orMethod struct {
+	Name           string                `json:"name" yaml:"name"`
+	Tags           []orTag               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary        string                `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description    string                `json:"description,omitempty" yaml:"description,omitempty"`
+	ParamStructure string                `json:"paramStructure,omitempty" yaml:"paramStructure,omitempty"`
+	Params         []orContentDescriptor `json:"params" yaml:"params"`
+	Result         orContentDescriptor   `json:"result" yaml:"result"`
+	Errors         []orError             `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Deprecated     bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+type orTag struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+type orContentDescriptor struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool     `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      swSchema `json:"schema" yaml:"schema"`
+}
+
+type orError struct {
+	Code    int    `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type orComponents struct {
+	Schemas swSchemas `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (openrpc.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+	"gopkg.in/yaml.v3"
+
+	"github.com/seniorGolang/tg/pkg/tags"
+)
+
+const openRPCVersion = "1.2.6"
+
+// RenderOpenRPC renders OpenRPC specification of jsonRPC interfaces, all interfaces are documented if ifaces is empty.
+func (tr Transport) RenderOpenRPC(outFile string, ifaces ...string) (err error) {
+
+	var spec orObject
+	if spec, err = tr.openRPC(ifaces...); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(outFile), 0777); err != nil {
+		return
+	}
+
+	var specData []byte
+
+	switch strings.ToLower(filepath.Ext(outFile)) {
+	case ".yaml", ".yml":
+		specData, err = yaml.Marshal(spec)
+	default:
+		specData, err = json.MarshalIndent(spec, "", "    ")
+	}
+	if err != nil {
+		return
+	}
+
+	tr.log.Info("write to ", outFile)
+
+	return ioutil.WriteFile(outFile, specData, 0600)
+}
+
+func (tr Transport) openRPC(ifaces ...string) (spec orObject, err error) {
+
+	doc := newSwagger(&tr)
+	for _, iface := range ifaces {
+		if svc, found := tr.services[iface]; !found || !svc.tags.Contains(tagServerJsonRPC) {
+			return spec, fmt.Errorf("jsonRPC interface '%s' not found", iface)
+		}
+		doc.ifaces[iface] = struct{}{}
+	}
+
+	spec.OpenRPC = openRPCVersion
+	spec.Info.Title = tr.tags.Value("title")
+	spec.Info.Version = tr.tags.Value("version")
+	spec.Info.Description = tr.tags.Value("description")
+	spec.Methods = make([]orMethod, 0)
+
+	for _, tagServer := range strings.Split(tr.tags.Value("servers"), "|") {
+
+		serverValues := strings.Split(tagServer, ";")
+		if serverValues[0] == "" {
+			continue
+		}
+
+		server := orServer{Name: serverValues[0], URL: serverValues[0]}
+		if len(serverValues) > 1 {
+			server.Name = serverValues[1]
+		}
+		spec.Servers = append(spec.Servers, server)
+	}
+
+	for _, serviceName := range tr.serviceKeys() {
+
+		if _, found := doc.ifaces[serviceName]; len(doc.ifaces) != 0 && !found {
+			continue
+		}
+
+		service := tr.services[serviceName]
+
+		for _, method := range service.methods {
+			if method.isJsonRPC() {
+				spec.Methods = append(spec.Methods, doc.openRPCMethod(service, method))
+			}
+		}
+	}
+
+	spec.Components.Schemas = doc.schemas
+	for name, schema := range spec.Components.Schemas {
+		spec.Components.Schemas[name] = upgradeSchema(schema)
+	}
+	return
+}
+
+// openRPCMethod describes method by name, which server dispatches, params are fields of request struct.
+func (doc *swagger) openRPCMethod(svc *service, method *method) (rpcMethod orMethod) {
+
+	rpcMethod = orMethod{
+		Name:           svc.lcName() + "." + method.lcName(),
+		Summary:        method.tags.Value(tagSummary),
+		Description:    method.tags.Value(tagDesc),
+		ParamStructure: "by-name",
+		Params:         make([]orContentDescriptor, 0),
+		Deprecated:     method.tags.Contains(tagDeprecated),
+	}
+
+	for _, tag := range strings.Split(method.tags.Value(tagSwaggerTags, svc.tags.Value(tagSwaggerTags, svc.Name)), ",") {
+		rpcMethod.Tags = append(rpcMethod.Tags, orTag{Name: strings.TrimSpace(tag)})
+	}
+
+	arguments := method.argumentsWithUploads()
+
+	doc.registerStruct(method.requestStructName(), svc.pkgPath, method.tags, arguments)
+	request := doc.schemas[method.requestStructName()]
+	delete(doc.schemas, method.requestStructName())
+
+	required := make(map[string]struct{})
+	for _, name := range request.Required {
+		required[name] = struct{}{}
+	}
+	for _, arg := range arguments {
+
+		name := jsonName(arg)
+		if _, found := request.Properties[name]; !found {
+			continue
+		}
+		_, isRequired := required[name]
+		rpcMethod.Params = append(rpcMethod.Params, orContentDescriptor{
+			Name:     name,
+			Required: isRequired,
+			Schema:   upgradeSchema(request.Properties[name]),
+		})
+	}
+
+	doc.registerStruct(method.responseStructName(), svc.pkgPath, method.tags, method.results())
+	rpcMethod.Result = orContentDescriptor{
+		Name:   "result",
+		Schema: swSchema{Ref: "#/components/schemas/" + method.responseStructName()},
+	}
+
+	var methodTags tags.DocTags
+	rpcMethod.Errors = openRPCErrors(methodTags.Merge(svc.tags).Merge(method.tags))
+	return
+}
+
+// openRPCErrors returns errors annotated by code and type, like '404=pkg/errors:NotFound', and errors of protocol.
+func openRPCErrors(docTags tags.DocTags) (errors []orError) {
+
+	for _, key := range sortedKeys(docTags) {
+
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		if tokens := strings.Split(strings.TrimSpace(docTags[key]), ":"); len(tokens) == 2 && tokens[1] != "" {
+			errors = append(errors, orError{Code: code, Message: tokens[1]})
+		}
+	}
+	return append(errors,
+		orError{Code: -32602, Message: "invalid params"},
+		orError{Code: -32603, Message: "internal error"},
+	)
+}
+
+func (tr Transport) hasDiscover() bool {
+	return tr.hasJsonRPC && tr.tags.Contains(tagRPCDiscover)
+}
+
+// renderDiscover renders OpenRPC specification into transport, server returns it for method 'rpc.discover'.
+func (tr Transport) renderDiscover(outDir string) (err error) {
+
+	var spec orObject
+	if spec, err = tr.openRPC(); err != nil {
+		return
+	}
+
+	var specData []byte
+	if specData, err = json.Marshal(spec); err != nil {
+		return
+	}
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.Const().Id("openRPCDocument").Op("=").Lit(string(specData))
+
+	srcFile.Line().Func().Params(Id("srv").Op("*").Id("Server")).Id("discover").Params(Id("request").Id("baseJsonRPC")).Params(Op("*").Id("baseJsonRPC")).Block(
+		Return(Op("&").Id("baseJsonRPC").Values(Dict{
+			Id("ID"):      Id("request").Dot("ID"),
+			Id("Version"): Id("Version"),
+			Id("Result"):  Qual(packageJson, "RawMessage").Call(Id("openRPCDocument")),
+		})),
+	)
+
+	return srcFile.Save(path.Join(outDir, "openrpc.go"))
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (openrpc_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const openRPCServices = `// @tg title=` + "`Calc API`" + `
+// @tg version=1.0.0
+// @tg rpc-discover
+package interfaces
+
+import "context"
+
+// @tg jsonRPC-server
+type Calc interface {
+	// @tg summary=` + "`sum of numbers`" + `
+	// @tg first.required
+	// @tg 1001=gentest/errs:Overflow
+	Add(ctx context.Context, first int, second int) (c int, err error)
+}
+
+// @tg http-server
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files
+	List(ctx context.Context) (names []string, err error)
+}
+`
+
+const openRPCErrs = `package errs
+
+type Overflow struct{}
+
+func (Overflow) Error() string {
+	return "overflow"
+}
+`
+
+const openRPCCheck = `package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, first int, second int) (c int, err error) {
+	return first + second, nil
+}
+
+func TestDiscover(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	srv := transport.New(log, transport.Calc(transport.NewCalc(log, calc{})))
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+
+	request := ` + "`" + `[{"jsonrpc":"2.0","id":1,"method":"rpc.discover"}]` + "`" + `
+	response, err := http.Post("http://"+address+"/", "application/json", strings.NewReader(request))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var responses []struct {
+		Result struct {
+			OpenRPC string ` + "`json:\"openrpc\"`" + `
+			Methods []struct {
+				Name string ` + "`json:\"name\"`" + `
+			} ` + "`json:\"methods\"`" + `
+		} ` + "`json:\"result\"`" + `
+	}
+	if err = json.NewDecoder(response.Body).Decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0].Result.OpenRPC != "1.2.6" || len(responses[0].Result.Methods) != 1 || responses[0].Result.Methods[0].Name != "calc.add" {
+		t.Errorf("unexpected specification %+v", responses)
+	}
+}
+`
+
+// TestOpenRPC checks specification of jsonRPC methods and its discovery by server.
+func TestOpenRPC(t *testing.T) {
+
+	t.Run("render", func(t *testing.T) {
+
+		tr, dir := testTransport(t, map[string]string{
+			"errs/errs.go":            openRPCErrs,
+			"interfaces/interface.go": openRPCServices,
+		})
+		outFile := filepath.Join(dir, "openrpc.json")
+		if err := tr.RenderOpenRPC(outFile); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(outFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spec struct {
+			OpenRPC string `json:"openrpc"`
+			Info    struct {
+				Title string `json:"title"`
+			} `json:"info"`
+			Methods []struct {
+				Name           string `json:"name"`
+				Summary        string `json:"summary"`
+				ParamStructure string `json:"paramStructure"`
+				Params         []struct {
+					Name     string `json:"name"`
+					Required bool   `json:"required"`
+				} `json:"params"`
+				Errors []struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			} `json:"methods"`
+		}
+		if err = json.Unmarshal(data, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.OpenRPC != "1.2.6" || spec.Info.Title != "Calc API" || len(spec.Methods) != 1 {
+			t.Fatalf("unexpected specification:\n%s", data)
+		}
+		method := spec.Methods[0]
+		if method.Name != "calc.add" || method.Summary != "sum of numbers" || method.ParamStructure != "by-name" {
+			t.Errorf("unexpected method %+v", method)
+		}
+		if params := fmt.Sprintf("%+v", method.Params); params != "[{Name:first Required:true} {Name:second Required:false}]" {
+			t.Errorf("unexpected params %s", params)
+		}
+		var errors []string
+		for _, e := range method.Errors {
+			errors = append(errors, fmt.Sprintf("%d %s", e.Code, e.Message))
+		}
+		if strings.Join(errors, ", ") != "1001 Overflow, -32602 invalid params, -32603 internal error" {
+			t.Errorf("unexpected errors %q", errors)
+		}
+		if err = tr.RenderOpenRPC(outFile, "Files"); err == nil {
+			t.Error("interface without jsonRPC is not reported")
+		}
+	})
+
+	testGenerated(t, map[string]string{
+		"discover_test.go":        openRPCCheck,
+		"errs/errs.go":            openRPCErrs,
+		"interfaces/interface.go": openRPCServices,
+	}, nil, WithTracer(TracerNone))
+}
@@ -16,3 +16,29 @@ func WithImplements(path string) Option {
 		svc.implementsPath = path
 	}
 }
+
+func WithTracer(tracer string) Option {
+	return func(svc *service) {
+		svc.tracer = tracing(tracer)
+	}
+}
+
+// WithOpenAPI sets version of swagger documentation, '3.0' or '3.1'.
+func WithOpenAPI(version string) Option {
+	return func(svc *service) {
+		svc.openAPI = version
+	}
+}
+
+func WithSwaggerJSON(enabled bool) Option {
+	return func(svc *service) {
+		svc.swaggerJSON = enabled
+	}
+}
+
+// WithEngine sets HTTP engine of generated server and clients, 'fasthttp' or 'nethttp'.
+func WithEngine(engine string) Option {
+	return func(svc *service) {
+		svc.engine = httpEngine(engine)
+	}
+}
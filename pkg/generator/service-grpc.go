@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (service-grpc.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+func (gen *grpcGen) renderService(outDir string, svc *service) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(gen.pbPkg, "pb")
+	srcFile.ImportName(packageGRPCCodes, "codes")
+	srcFile.ImportName(packageGRPCStatus, "status")
+	srcFile.ImportName(svc.pkgPath, filepath.Base(svc.pkgPath))
+
+	ctx := gen.ctx
+	gen.ctx = context.WithValue(context.Background(), "code", srcFile)
+	defer func() { gen.ctx = ctx }()
+
+	for _, method := range svc.methods {
+		gen.registerMessage(method.grpcRequestName(), svc.pkgPath, method.argsWithoutContext())
+		gen.registerMessage(method.grpcResponseName(), svc.pkgPath, method.resultsWithoutError())
+	}
+
+	srcFile.Type().Id("grpc"+svc.Name).Struct(
+		Qual(gen.pbPkg, "Unimplemented"+svc.Name+"Server"),
+		Line().Id("http").Op("*").Id("http"+svc.Name),
+	)
+
+	for _, method := range svc.methods {
+		srcFile.Line().Add(gen.grpcMethodFunc(svc, method))
+	}
+	return srcFile.Save(path.Join(outDir, svc.lcName()+"-grpc.go"))
+}
+
+func (gen *grpcGen) grpcMethodFunc(svc *service, method *method) Code {
+
+	request, response := gen.messages[method.grpcRequestName()], gen.messages[method.grpcResponseName()]
+
+	invalidArgument := Return(Nil(), Qual(packageGRPCStatus, "Error").Call(Qual(packageGRPCCodes, "InvalidArgument"), Err().Dot("Error").Call()))
+	internal := Return(Nil(), Qual(packageGRPCStatus, "Error").Call(Qual(packageGRPCCodes, "Internal"), Err().Dot("Error").Call()))
+
+	return Func().Params(Id("grpc").Op("*").Id("grpc"+svc.Name)).Id(method.Name).
+		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("request").Op("*").Qual(gen.pbPkg, goCamelCase(request.name))).
+		Params(Id("response").Op("*").Qual(gen.pbPkg, goCamelCase(response.name)), Err().Error()).BlockFunc(func(bg *Group) {
+
+		gen.values = 0
+
+		bg.Line().List(Id(_ctx_), Id("cancel")).Op(":=").Id("grpc").Dot("http").Dot("withTimeout").Call(Id(_ctx_), method.timeoutCode())
+		bg.Defer().Id("cancel").Call()
+		bg.Add(svc.authGRPC(method))
+
+		bg.Line()
+		for _, field := range request.fields {
+			bg.Var().Id("_" + field.name).Add(gen.goType(svc.pkgPath, field.vType))
+		}
+		for _, field := range response.fields {
+			bg.Var().Id("_" + field.name).Add(gen.goType(svc.pkgPath, field.vType))
+		}
+		for _, field := range request.fields {
+			bg.Add(gen.fromProto(svc.pkgPath, field.vType, Id("_"+field.name), Id("request").Dot(field.goName), invalidArgument))
+		}
+
+		if method.hasValidation {
+			bg.Line().If(Err().Op("=").Parens(Id(method.requestStructName()).Values(DictFunc(func(dict Dict) {
+				for _, field := range request.fields {
+					dict[Id(utils.ToCamel(field.name))] = Id("_" + field.name)
+				}
+			}))).Dot("validate").Call().Op(";").Err().Op("!=").Nil()).Block(
+				Return(Nil(), Qual(packageGRPCStatus, "Error").Call(Qual(packageGRPCCodes, "InvalidArgument"), Err().Dot("Error").Call())),
+			)
+		}
+
+		bg.Line().ListFunc(func(lg *Group) {
+			for _, field := range response.fields {
+				lg.Id("_" + field.name)
+			}
+			lg.Err()
+		}).Op("=").Id("grpc").Dot("http").Dot("svc").Dot(method.Name).CallFunc(func(cg *Group) {
+
+			cg.Id(_ctx_)
+			for _, arg := range method.argsWithoutContext() {
+
+				argCode := Id("_" + arg.Name)
+				if types.IsEllipsis(arg.Type) {
+					argCode.Op("...")
+				}
+				cg.Add(argCode)
+			}
+		})
+
+		bg.If(Err().Op("!=").Nil()).Block(
+			If(Id("grpc").Dot("http").Dot("errorHandler").Op("!=").Nil()).Block(
+				Err().Op("=").Id("grpc").Dot("http").Dot("errorHandler").Call(Err()),
+			),
+			Return(Nil(), Id("grpcError").Call(Err())),
+		)
+
+		bg.Line().Id("response").Op("=").Op("&").Qual(gen.pbPkg, goCamelCase(response.name)).Values()
+		for _, field := range response.fields {
+			bg.Add(gen.toProto(svc.pkgPath, field.vType, Id("response").Dot(field.goName), Id("_"+field.name), internal))
+		}
+		bg.Return()
+	})
+}
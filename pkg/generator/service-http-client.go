@@ -0,0 +1,326 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (service-http-client.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+func (svc *service) renderClientHTTP(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	ctx := context.WithValue(context.Background(), "code", srcFile)
+
+	srcFile.ImportName(packageGotils, "gotils")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Line().Type().Id(svc.clientHTTPName()).Struct(
+		Op("*").Id("ClientHTTP"),
+	)
+
+	for _, method := range svc.methods {
+
+		// methods of custom handlers are called by contract of swagger, so client implements whole interface
+		if !method.isHTTP() {
+			continue
+		}
+		srcFile.Line().Add(svc.httpClientMethodFunc(ctx, method))
+	}
+	return srcFile.Save(path.Join(outDir, svc.lcName()+"-http.go"))
+}
+
+func (svc *service) httpClientMethodFunc(ctx context.Context, method *method) Code {
+
+	if svc.engine.isNetHTTP() {
+		return svc.httpClientMethodNetHTTPFunc(ctx, method)
+	}
+	return Func().Params(Id("cli").Op("*").Id(svc.clientHTTPName())).Id(method.Name).Params(funcDefinitionParams(ctx, method.Args)).Params(funcDefinitionParams(ctx, method.Results)).BlockFunc(func(bg *Group) {
+
+		bg.Line().Id("req").Op(":=").Qual(packageFastHttp, "AcquireRequest").Call()
+		bg.Id("resp").Op(":=").Qual(packageFastHttp, "AcquireResponse").Call()
+		bg.Defer().Qual(packageFastHttp, "ReleaseRequest").Call(Id("req"))
+		bg.Defer().Qual(packageFastHttp, "ReleaseResponse").Call(Id("resp"))
+
+		bg.Line().Id("span").Op(":=").Add(svc.tracer.extractSpan("cli", Id(_ctx_), Id("cli").Dot("name")))
+		bg.Add(svc.tracer.deferFinish("span"))
+
+		for _, argName := range sortedKeys(method.argPathMap()) {
+			if method.argByName(strings.Split(argName, ".")[0]) != nil {
+				bg.Var().Id("_" + strings.Replace(argName, ".", "", -1)).String()
+			}
+		}
+		bg.Add(method.argsToString(method.argPathMap(), func(srcName string, value Code) Code {
+			return Id("_" + strings.Replace(srcName, ".", "", -1)).Op("=").Add(value)
+		}))
+
+		bg.Id("req").Dot("Header").Dot("SetMethod").Call(Lit(method.httpMethod()))
+		bg.Id("req").Dot("SetRequestURI").Call(Id("cli").Dot("url").Op("+").Add(method.httpClientURI()))
+
+		bg.Add(method.argsToString(method.argParamMap(), func(srcName string, value Code) Code {
+			return Id("req").Dot("URI").Call().Dot("QueryArgs").Call().Dot("Set").Call(Lit(srcName), value)
+		}))
+		bg.Add(method.argsToString(method.varHeaderMap(), func(srcName string, value Code) Code {
+			return Id("req").Dot("Header").Dot("Set").Call(Lit(srcName), value)
+		}))
+		bg.Add(method.argsToString(method.argCookieMap(), func(srcName string, value Code) Code {
+			return Id("req").Dot("Header").Dot("SetCookie").Call(Lit(srcName), value)
+		}))
+
+		if len(method.uploadVarsMap()) != 0 {
+			bg.Add(method.httpClientUploads())
+		} else if args := method.arguments(); len(args) != 0 {
+			bg.Id("req").Dot("Header").Dot("SetContentType").Call(Lit(contentJSON))
+			bg.If(Err().Op("=").Qual(packageJson, "NewEncoder").Call(Id("req").Dot("BodyWriter").Call()).Dot("Encode").Call(
+				Id(method.requestStructName()).Values(DictFunc(func(d Dict) {
+					for _, arg := range args {
+						d[Id(utils.ToCamel(arg.Name))] = Id(arg.Name)
+					}
+				})),
+			).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			)
+		}
+
+		bg.Line().If(Err().Op("=").Id("cli").Dot("httpCall").Call(Id(_ctx_), Id("span"), Id("req"), Id("resp")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		)
+		bg.If(Id("resp").Dot("StatusCode").Call().Op("!=").Lit(method.tags.ValueInt(tagHttpSuccess, 200))).Block(
+			Err().Op("=").Id("cli").Dot("decodeError").Call(Id("resp")),
+			Return(),
+		)
+		bg.Add(method.httpClientResults(ctx))
+		bg.Return()
+	})
+}
+
+// httpClientMethodNetHTTPFunc renders method of client, request of net/http is created after its body.
+func (svc *service) httpClientMethodNetHTTPFunc(ctx context.Context, method *method) Code {
+
+	return Func().Params(Id("cli").Op("*").Id(svc.clientHTTPName())).Id(method.Name).Params(funcDefinitionParams(ctx, method.Args)).Params(funcDefinitionParams(ctx, method.Results)).BlockFunc(func(bg *Group) {
+
+		bg.Line().Id("span").Op(":=").Add(svc.tracer.extractSpan("cli", Id(_ctx_), Id("cli").Dot("name")))
+		bg.Add(svc.tracer.deferFinish("span"))
+
+		for _, argName := range sortedKeys(method.argPathMap()) {
+			if method.argByName(strings.Split(argName, ".")[0]) != nil {
+				bg.Var().Id("_" + strings.Replace(argName, ".", "", -1)).String()
+			}
+		}
+		bg.Add(method.argsToString(method.argPathMap(), func(srcName string, value Code) Code {
+			return Id("_" + strings.Replace(srcName, ".", "", -1)).Op("=").Add(value)
+		}))
+
+		var body, contentType Code = Nil(), nil
+		if len(method.uploadVarsMap()) != 0 {
+			bg.Add(method.httpClientUploads())
+			body, contentType = Id("body").Dot("Bytes").Call(), Id("writer").Dot("FormDataContentType").Call()
+		} else if args := method.arguments(); len(args) != 0 {
+			bg.Line().Id("body").Op(":=").Op("&").Qual(packageBytes, "Buffer").Values()
+			bg.If(Err().Op("=").Qual(packageJson, "NewEncoder").Call(Id("body")).Dot("Encode").Call(
+				Id(method.requestStructName()).Values(DictFunc(func(d Dict) {
+					for _, arg := range args {
+						d[Id(utils.ToCamel(arg.Name))] = Id(arg.Name)
+					}
+				})),
+			).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			)
+			body, contentType = Id("body").Dot("Bytes").Call(), Lit(contentJSON)
+		}
+
+		bg.Line().Var().Id("req").Op("*").Qual(packageHttp, "Request")
+		bg.If(List(Id("req"), Err()).Op("=").Qual(packageHttp, "NewRequestWithContext").Call(Id(_ctx_), Lit(method.httpMethod()), Id("cli").Dot("url").Op("+").Add(method.httpClientURI()), Nil()).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		)
+		if len(method.argParamMap()) != 0 {
+			bg.Id("queryArgs").Op(":=").Qual(packageURL, "Values").Values()
+			bg.Add(method.argsToString(method.argParamMap(), func(srcName string, value Code) Code {
+				return Id("queryArgs").Dot("Set").Call(Lit(srcName), value)
+			}))
+			bg.Id("req").Dot("URL").Dot("RawQuery").Op("=").Id("queryArgs").Dot("Encode").Call()
+		}
+		bg.Add(method.argsToString(method.varHeaderMap(), func(srcName string, value Code) Code {
+			return Id("req").Dot("Header").Dot("Set").Call(Lit(srcName), value)
+		}))
+		bg.Add(method.argsToString(method.argCookieMap(), func(srcName string, value Code) Code {
+			return Id("req").Dot("AddCookie").Call(Op("&").Qual(packageHttp, "Cookie").Values(Dict{Id("Name"): Lit(srcName), Id("Value"): value}))
+		}))
+		if contentType != nil {
+			bg.Id("req").Dot("Header").Dot("Set").Call(Lit("Content-Type"), contentType)
+		}
+
+		bg.Line().Var().Id("resp").Op("*").Id("httpResponse")
+		bg.If(List(Id("resp"), Err()).Op("=").Id("cli").Dot("httpCall").Call(Id(_ctx_), Id("span"), Id("req"), body).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		)
+		bg.If(Id("resp").Dot("StatusCode").Op("!=").Lit(method.tags.ValueInt(tagHttpSuccess, 200))).Block(
+			Err().Op("=").Id("cli").Dot("decodeError").Call(Id("resp")),
+			Return(),
+		)
+		bg.Add(method.httpClientResults(ctx))
+		bg.Return()
+	})
+}
+
+func (m *method) httpClientURI() *Statement {
+
+	var parts []Code
+	var static []string
+
+	for _, token := range strings.Split(m.httpPath(), "/") {
+
+		if strings.HasPrefix(token, "{") {
+
+			argName := strings.TrimSpace(strings.Replace(strings.TrimPrefix(token, "{"), "}", "", -1))
+
+			if m.argByName(strings.Split(argName, ".")[0]) != nil {
+				parts = append(parts, Lit(strings.Join(append(static, ""), "/")))
+				parts = append(parts, Qual(packageURL, "PathEscape").Call(Id("_"+strings.Replace(argName, ".", "", -1))))
+				static = []string{""}
+				continue
+			}
+		}
+		static = append(static, token)
+	}
+	if len(static) > 1 || len(parts) == 0 {
+		parts = append(parts, Lit(strings.Join(static, "/")))
+	}
+	uri := &Statement{}
+	for i, part := range parts {
+		if i > 0 {
+			uri.Op("+")
+		}
+		uri.Add(part)
+	}
+	return uri
+}
+
+func (m *method) httpClientUploads() *Statement {
+
+	block := Line().Id("body").Op(":=").Op("&").Qual(packageBytes, "Buffer").Values()
+	block.Line().Id("writer").Op(":=").Qual(packageMultipart, "NewWriter").Call(Id("body"))
+
+	for _, uploadVar := range sortedKeys(m.uploadVarsMap()) {
+
+		uploadKey := m.uploadVarsMap()[uploadVar]
+
+		if m.argByName(uploadVar) == nil {
+			continue
+		}
+		block.Line().Var().Id("part"+utils.ToCamel(uploadVar)).Qual(packageIO, "Writer")
+		block.Line().If(List(Id("part"+utils.ToCamel(uploadVar)), Err()).Op("=").Id("writer").Dot("CreateFormFile").Call(Lit(uploadKey), Lit(uploadKey)).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		)
+		block.Line().If(List(Id("_"), Err()).Op("=").Id("part" + utils.ToCamel(uploadVar)).Dot("Write").Call(Id(uploadVar)).Op(";").Err().Op("!=").Nil()).Block(
+			Return(),
+		)
+	}
+	block.Line().If(Err().Op("=").Id("writer").Dot("Close").Call().Op(";").Err().Op("!=").Nil()).Block(
+		Return(),
+	)
+	if m.svc.engine.isNetHTTP() {
+		// request of net/http is created after body
+		return block
+	}
+	block.Line().Id("req").Dot("Header").Dot("SetContentType").Call(Id("writer").Dot("FormDataContentType").Call())
+	block.Line().Id("req").Dot("SetBody").Call(Id("body").Dot("Bytes").Call())
+	return block
+}
+
+func (m *method) httpClientResults(ctx context.Context) *Statement {
+
+	block := Line()
+
+	errStatement := Line().If(Err().Op("!=").Nil()).Block(Return())
+
+	retFromString := func(retName string, strCode Code) {
+
+		ret := m.resultByName(retName)
+		if ret == nil || !isStringConvertible(ret.Type) {
+			return
+		}
+		retID := Id(retName)
+		retType := ret.Type
+		if t, ok := ret.Type.(types.TPointer); ok {
+			retType = t.NextType()
+		}
+		block.If(Id("_" + retName).Op(":=").Add(strCode).Op(";").Id("_" + retName).Op("!=").Lit("")).BlockFunc(func(g *Group) {
+			g.Var().Id("value").Add(fieldType(ctx, retType, false))
+			g.Add(m.argToTypeConverter(Id("_"+retName), retType, Id("value"), errStatement))
+			if retType != ret.Type {
+				g.Add(retID).Op("=").Op("&").Id("value")
+				return
+			}
+			g.Add(retID).Op("=").Id("value")
+		}).Line()
+	}
+
+	netHTTP := m.svc.engine.isNetHTTP()
+	header := func(name Code) Code {
+		if netHTTP {
+			return Id("resp").Dot("Header").Dot("Get").Call(name)
+		}
+		return Qual(packageGotils, "B2S").Call(Id("resp").Dot("Header").Dot("Peek").Call(name))
+	}
+	var body Code = Id("resp").Dot("Body").Call()
+	if netHTTP {
+		body = Id("resp").Dot("body")
+	}
+
+	for _, retName := range sortedKeys(m.varHeaderMap()) {
+		retFromString(retName, header(Lit(m.varHeaderMap()[retName])))
+	}
+	for _, retName := range sortedKeys(m.retCookieMap()) {
+		if netHTTP {
+			retFromString(retName, Id("resp").Dot("cookie").Call(Lit(m.retCookieMap()[retName])))
+			continue
+		}
+		retFromString(retName, String().Call(Id("resp").Dot("Header").Dot("PeekCookie").Call(Lit(m.retCookieMap()[retName]))))
+	}
+
+	for _, retName := range sortedKeys(m.downloadVarsMap()) {
+
+		if ret := m.resultByName(retName); ret != nil && ret.Type.String() == "[]byte" {
+			var contentType Code = Qual(packageGotils, "B2S").Call(Id("resp").Dot("Header").Dot("ContentType").Call())
+			if netHTTP {
+				contentType = header(Lit("Content-Type"))
+			}
+			block.If(Op("!").Qual(packageStrings, "HasPrefix").Call(contentType, Lit(contentJSON))).Block(
+				Id(retName).Op("=").Append(Op("[]").Byte().Call(Nil()), Add(body).Op("...")),
+				Return(),
+			).Line()
+			break
+		}
+	}
+
+	if results := m.results(); len(results) != 0 {
+		block.Var().Id("response").Id(m.responseStructName())
+		block.Line().If(Len(body).Op("!=").Lit(0)).Block(
+			If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(body, Op("&").Id("response")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			),
+		)
+		for _, ret := range m.resultsWithoutError() {
+			_, inHeader := m.varHeaderMap()[ret.Name]
+			_, inCookie := m.varCookieMap()[ret.Name]
+			_, inDownload := m.downloadVarsMap()[ret.Name]
+			if !inHeader && !inCookie && !inDownload {
+				block.Line().Id(ret.Name).Op("=").Id("response").Dot(utils.ToCamel(ret.Name))
+			}
+		}
+	}
+	return block
+}
@@ -15,26 +15,57 @@ func (svc *service) renderHTTP(outDir string) (err error) {
 	srcFile := newSrc(filepath.Base(outDir))
 	srcFile.PackageComment(doNotEdit)
 
-	srcFile.ImportName(packageCors, "cors")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
-	srcFile.ImportName(packageFastHttpRouter, "router")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+		srcFile.ImportName(packageFastHttpRouter, "router")
+	}
 	srcFile.ImportName(svc.pkgPath, filepath.Base(svc.pkgPath))
-
-	srcFile.Type().Id("http"+svc.Name).Struct(
-		Id("log").Qual(packageLogrus, "FieldLogger"),
-		Id("errorHandler").Id("ErrorHandler"),
-		Id("svc").Op("*").Id("server"+svc.Name),
-		Id("base").Qual(svc.pkgPath, svc.Name),
-	)
+	svc.engine.importHttp(srcFile)
+
+	srcFile.Type().Id("http" + svc.Name).StructFunc(func(g *Group) {
+		g.Id("log").Qual(packageLogrus, "FieldLogger")
+		g.Id("errorHandler").Id("ErrorHandler")
+		g.Id("svc").Op("*").Id("server" + svc.Name)
+		g.Id("base").Qual(svc.pkgPath, svc.Name)
+		g.Id("timeout").Qual(packageTime, "Duration")
+		if !svc.engine.isNetHTTP() {
+			g.Id("baseCtx").Qual(packageContext, "Context")
+		}
+		g.Id("panicHandler").Id("PanicHandler")
+		g.Id("compressMinSize").Int()
+		if svc.tracer.isOTel() {
+			g.Id("tracerProvider").Qual(packageOTelTrace, "TracerProvider")
+		}
+		if svc.tags.Contains(tagServerJsonRPC) {
+			g.Id("maxBatchSize").Int()
+			g.Id("maxParallelBatch").Int()
+		}
+		if svc.hasAuth() {
+			g.Id("authenticator").Id("Authenticator")
+		}
+		if svc.tags.Contains(tagMetrics) {
+			g.Id("metrics").Op("*").Id("Metrics")
+		}
+		if svc.hasCORS() {
+			g.Id("cors").Op("*").Id("CORSConfig")
+		}
+	})
 
 	srcFile.Line().Func().Id("New"+svc.Name).Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("svc"+svc.Name).Qual(svc.pkgPath, svc.Name)).Params(Id("srv").Op("*").Id("http"+svc.Name)).Block(
 
-		Line().Id("srv").Op("=").Op("&").Id("http"+svc.Name).Values(Dict{
-			Id("log"):  Id("log"),
-			Id("base"): Id("svc" + svc.Name),
-			Id("svc"):  Id("newServer" + svc.Name).Call(Id("svc" + svc.Name)),
-		}),
+		Line().Id("srv").Op("=").Op("&").Id("http"+svc.Name).Values(DictFunc(func(d Dict) {
+			d[Id("log")] = Id("log")
+			d[Id("base")] = Id("svc" + svc.Name)
+			d[Id("svc")] = Id("newServer" + svc.Name).Call(Id("svc" + svc.Name))
+			d[Id("compressMinSize")] = Id("defaultCompressMinSize")
+			if svc.tags.Contains(tagServerJsonRPC) {
+				d[Id("maxParallelBatch")] = Id("defaultMaxParallelBatch")
+			}
+			if svc.hasCORS() {
+				d[Id("cors")] = corsConfigCode(svc.cors)
+			}
+		})),
 		Return(),
 	)
 
@@ -42,23 +73,38 @@ func (svc *service) renderHTTP(outDir string) (err error) {
 		Return(Id("http").Dot("svc")),
 	)
 
-	srcFile.Line().Add(svc.withLogFunc())
-	srcFile.Line().Add(svc.withTraceFunc())
-	srcFile.Line().Add(svc.withMetricsFunc())
+	if svc.tags.Contains(tagLogger) {
+		srcFile.Line().Add(svc.withLogFunc())
+	}
+	if svc.tags.Contains(tagTrace) {
+		srcFile.Line().Add(svc.withTraceFunc())
+	}
+	if svc.tags.Contains(tagMetrics) {
+		srcFile.Line().Add(svc.withMetricsFunc())
+	}
 	srcFile.Line().Add(svc.withErrorHandler())
-
-	srcFile.Line().Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("SetRoutes").Params(Id("route").Op("*").Qual(packageFastHttpRouter, "Router")).BlockFunc(func(bg *Group) {
+	srcFile.Line().Add(svc.withTimeoutFunc())
+	if !svc.engine.isNetHTTP() {
+		srcFile.Line().Add(svc.detachFunc())
+	}
+	srcFile.Line().Add(svc.onPanicFunc())
+	srcFile.Line().Add(svc.compressFunc())
+	if svc.hasCORS() {
+		srcFile.Line().Add(svc.corsFuncs())
+	}
+
+	srcFile.Line().Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("SetRoutes").Params(Id("route").Add(svc.engine.routerType())).BlockFunc(func(bg *Group) {
 
 		if svc.tags.Contains(tagServerJsonRPC) {
 
-			bg.Line().Id("route").Dot("POST").Call(Lit(svc.batchPath()), Id("http").Dot("serveBatch"))
+			bg.Line().Add(svc.engine.route(Id("route"), "POST", svc.batchPath(), svc.corsRouteHandler(svc.compressRouteHandler(nil, Id("http").Dot("serveBatch")))))
 
 			for _, method := range svc.methods {
 
 				if !method.isJsonRPC() {
 					continue
 				}
-				bg.Id("route").Dot("POST").Call(Lit(method.jsonrpcPath()), Id("http").Dot("serve"+method.Name))
+				bg.Add(svc.engine.route(Id("route"), "POST", method.jsonrpcPath(), svc.corsRouteHandler(svc.compressRouteHandler(method, Id("http").Dot("serve"+method.Name)))))
 			}
 
 		}
@@ -73,14 +119,17 @@ func (svc *service) renderHTTP(outDir string) (err error) {
 					continue
 				}
 				if method.tags.Contains(tagHandler) {
-					bg.Id("route").Dot(method.httpMethod()).Call(Lit(method.httpPath()), Func().Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
-						Qual(method.handlerQual()).Call(Id(_ctx_), Id("http").Dot("base")),
-					))
+					bg.Add(svc.engine.route(Id("route"), method.httpMethod(), method.httpPath(), svc.corsRouteHandler(svc.compressRouteHandler(method, Func().Params(svc.engine.handlerParams()...).Block(
+						Qual(method.handlerQual()).Call(append(svc.engine.handlerArgs(), Id("http").Dot("base"))...),
+					)))))
 					continue
 				}
-				bg.Id("route").Dot(method.httpMethod()).Call(Lit(method.httpPath()), Id("http").Dot("serve"+method.Name))
+				bg.Add(svc.engine.route(Id("route"), method.httpMethod(), method.httpPath(), svc.corsRouteHandler(svc.compressRouteHandler(method, Id("http").Dot("serve"+method.Name)))))
 			}
 		}
+		if svc.hasCORS() {
+			svc.corsPreflightRoutes(bg)
+		}
 	})
 	return srcFile.Save(path.Join(outDir, svc.lcName()+"-http.go"))
 }
@@ -94,6 +143,105 @@ func (svc *service) withErrorHandler() Code {
 	})
 }
 
+// withTimeoutFunc renders method context constructor, method timeout has priority over server default.
+func (svc *service) withTimeoutFunc() Code {
+
+	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("withTimeout").
+		Params(Id(_ctx_).Qual(packageContext, "Context"), Id("timeout").Qual(packageTime, "Duration")).
+		Params(Qual(packageContext, "Context"), Qual(packageContext, "CancelFunc")).Block(
+
+		If(Id("timeout").Op("==").Lit(0)).Block(
+			Id("timeout").Op("=").Id("http").Dot("timeout"),
+		),
+		If(Id("timeout").Op("<=").Lit(0)).Block(
+			Return(Qual(packageContext, "WithCancel").Call(Id(_ctx_))),
+		),
+		Return(Qual(packageContext, "WithTimeout").Call(Id(_ctx_), Id("timeout"))),
+	)
+}
+
+// detachFunc renders constructor of request context, which is canceled by close of connection or server shutdown deadline instead of fasthttp.
+func (svc *service) detachFunc() Code {
+
+	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("detach").
+		Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Params(Qual(packageContext, "Context")).Block(
+
+		If(Id("http").Dot("baseCtx").Op("==").Nil()).Block(
+			Return(Id(_ctx_)),
+		),
+		Return(Id("detachedContext").Values(Dict{
+			Id("Context"): Id("requestContext").Call(Id(_ctx_), Id("http").Dot("baseCtx")),
+			Id("values"):  Id(_ctx_),
+		})),
+	)
+}
+
+// onPanicFunc renders handling of recovered panic, custom handler replaces logging of stack.
+func (svc *service) onPanicFunc() Code {
+
+	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("onPanic").
+		Params(append(svc.engine.handlerParams(), Id("method").String(), Id("recovered").Interface())...).BlockFunc(func(bg *Group) {
+
+		bg.Line().Id("stack").Op(":=").Qual(packageDebug, "Stack").Call()
+		if svc.tags.Contains(tagMetrics) {
+			bg.If(Id("http").Dot("metrics").Op("!=").Nil()).Block(
+				Id("http").Dot("metrics").Dot("panics").Dot("With").Call(Lit("method"), Id("method"), Lit("service"), Lit(svc.Name)).Dot("Add").Call(Lit(1)),
+			)
+		}
+		bg.If(Id("http").Dot("panicHandler").Op("!=").Nil()).Block(
+			Id("http").Dot("panicHandler").Call(append(svc.engine.handlerArgs(), Id("method"), Id("recovered"), Id("stack"))...),
+			Return(),
+		)
+		bg.Id("http").Dot("log").Dot("WithFields").Call(Qual(packageLogrus, "Fields").Values(Dict{
+			Lit("service"):   Lit(svc.Name),
+			Lit("method"):    Id("method"),
+			Lit("requestID"): svc.requestID(),
+			Lit("stack"):     String().Call(Id("stack")),
+		})).Dot("Errorf").Call(Lit("panic: %v"), Id("recovered"))
+	})
+}
+
+// requestID renders identifier of request, which is set by extractSpan.
+func (svc *service) requestID() Code {
+
+	if svc.engine.isNetHTTP() {
+		return Id("r").Dot("Header").Dot("Get").Call(Id("headerRequestID"))
+	}
+	return Id(_ctx_).Dot("UserValue").Call(Id("headerRequestID"))
+}
+
+// requestContext renders base context of method, context of net/http request is canceled on disconnect of client.
+func (svc *service) requestContext() Code {
+
+	if svc.engine.isNetHTTP() {
+		return Id("r").Dot("Context").Call()
+	}
+	return Id("http").Dot("detach").Call(Id(_ctx_))
+}
+
+// setCookie renders adding of cookie to response.
+func (svc *service) setCookie(cookie Code) Code {
+
+	if svc.engine.isNetHTTP() {
+		return Qual(packageHttp, "SetCookie").Call(Id("w"), cookie)
+	}
+	return Id(_ctx_).Dot("Response").Dot("Header").Dot("SetCookie").Call(cookie)
+}
+
+// deferRecover renders recovering of panic in handler, panic affects response of this call only.
+func (svc *service) deferRecover(method Code, span string, response ...Code) Code {
+
+	return Defer().Func().Params().Block(
+		If(Id("recovered").Op(":=").Recover().Op(";").Id("recovered").Op("!=").Nil()).BlockFunc(func(g *Group) {
+			g.Add(svc.tracer.setError(span, Qual(packageFmt, "Sprintf").Call(Lit("panic: %v"), Id("recovered"))))
+			g.Id("http").Dot("onPanic").Call(append(svc.engine.handlerArgs(), method, Id("recovered"))...)
+			for _, code := range response {
+				g.Add(code)
+			}
+		}),
+	).Call()
+}
+
 func (svc *service) withLogFunc() Code {
 
 	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("WithLog").Params(Id("log").Qual(packageLogrus, "FieldLogger")).Params(Op("*").Id("http" + svc.Name)).BlockFunc(func(bg *Group) {
@@ -114,9 +262,10 @@ func (svc *service) withTraceFunc() Code {
 
 func (svc *service) withMetricsFunc() Code {
 
-	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("WithMetrics").Params().Params(Op("*").Id("http" + svc.Name)).BlockFunc(func(bg *Group) {
+	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("WithMetrics").Params(Id("metrics").Op("*").Id("Metrics")).Params(Op("*").Id("http" + svc.Name)).BlockFunc(func(bg *Group) {
 
-		bg.Id("http").Dot("svc").Dot("WithMetrics").Call()
+		bg.Id("http").Dot("metrics").Op("=").Id("metrics")
+		bg.Id("http").Dot("svc").Dot("WithMetrics").Call(Id("metrics"))
 		bg.Return(Id("http"))
 	})
 }
@@ -0,0 +1,141 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (service-http_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const panicServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files/{id}
+	Get(ctx context.Context, id string) (name string, err error)
+}
+
+// @tg jsonRPC-server
+type Calc interface {
+	Div(ctx context.Context, a int, b int) (c int, err error)
+}
+`
+
+const panicCheck = `package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+
+	"gentest/transport"
+)
+
+type files struct{}
+
+func (files) Get(ctx context.Context, id string) (name string, err error) {
+	if id == "panic" {
+		panic("file panic")
+	}
+	return id, nil
+}
+
+type calc struct{}
+
+func (calc) Div(ctx context.Context, a int, b int) (c int, err error) {
+	return a / b, nil
+}
+
+type panics struct {
+	sync.Mutex
+	methods []string
+}
+
+func (p *panics) handle(ctx *fasthttp.RequestCtx, method string, recovered interface{}, stack []byte) {
+	p.Lock()
+	defer p.Unlock()
+	p.methods = append(p.methods, method)
+}
+
+func TestPanic(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	recovered := &panics{}
+	address := freeAddress(t)
+	srv := transport.New(log,
+		transport.OnPanic(recovered.handle),
+		transport.Files(transport.NewFiles(log, files{})),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+	url := "http://" + address
+
+	response, err := http.Get(url + "/files/panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("unexpected status of panic %d", response.StatusCode)
+	}
+	if response, err = http.Get(url + "/files/next"); err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("server does not serve after panic: %v", err)
+	}
+	_ = response.Body.Close()
+
+	batch := ` + "`" + `[{"jsonrpc":"2.0","id":1,"method":"calc.div","params":{"a":1,"b":0}},{"jsonrpc":"2.0","id":2,"method":"calc.div","params":{"a":4,"b":2}}]` + "`" + `
+	if response, err = http.Post(url+"/", "application/json", strings.NewReader(batch)); err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var responses []struct {
+		ID     int ` + "`json:\"id\"`" + `
+		Result *struct {
+			C int ` + "`json:\"c\"`" + `
+		} ` + "`json:\"result\"`" + `
+		Error *struct {
+			Code    int    ` + "`json:\"code\"`" + `
+			Message string ` + "`json:\"message\"`" + `
+		} ` + "`json:\"error\"`" + `
+	}
+	if err = json.NewDecoder(response.Body).Decode(&responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("unexpected responses %+v", responses)
+	}
+	if responses[0].Error == nil || responses[0].Error.Code != -32603 || responses[0].Error.Message != "internal error" {
+		t.Errorf("unexpected response of panic %+v", responses[0].Error)
+	}
+	if responses[1].Error != nil || responses[1].Result == nil || responses[1].Result.C != 2 {
+		t.Errorf("panic affects other request of batch %+v %+v", responses[1].Result, responses[1].Error)
+	}
+
+	recovered.Lock()
+	defer recovered.Unlock()
+	if strings.Join(recovered.methods, ",") != "get,div" {
+		t.Errorf("unexpected methods of panic handler %q", recovered.methods)
+	}
+}
+`
+
+// TestPanic checks, that panic of method is answered by internal error and does not affect other requests.
+func TestPanic(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": panicServices,
+		"panic_test.go":           panicCheck,
+	}, nil, WithTracer(TracerNone))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"path"
 	"path/filepath"
+	"strings"
 
 	. "github.com/dave/jennifer/jen"
 	"github.com/vetcher/go-astra/types"
@@ -23,7 +24,9 @@ func (svc *service) renderClientJsonRPC(outDir string) (err error) {
 
 	srcFile.ImportName(packageUUID, "uuid")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 
 	srcFile.Line().Type().Id("Client" + svc.Name).Struct(
 		Op("*").Id("ClientJsonRPC"),
@@ -44,6 +47,7 @@ func (svc *service) renderClientJsonRPC(outDir string) (err error) {
 		}
 		srcFile.Line().Add(svc.jsonrpcClientRequestFunc(ctx, method))
 		srcFile.Line().Add(svc.jsonrpcClientMethodFunc(ctx, method))
+		srcFile.Line().Add(svc.jsonrpcClientDecodeErrorFunc(method))
 	}
 
 	return srcFile.Save(path.Join(outDir, svc.lcName()+"-jsonrpc.go"))
@@ -69,7 +73,7 @@ func (svc *service) jsonrpcClientRequestFunc(ctx context.Context, method *method
 		Line().If(Id("ret").Op("!=").Nil()).Block(
 			Id("request").Dot("retHandler").Op("=").Func().Params(Id("jsonrpcResponse").Id("baseJsonRPC")).Block(
 				If(Id("jsonrpcResponse").Dot("Error").Op("!=").Nil()).Block(
-					Err().Op("=").Id("cli").Dot("errorDecoder").Call(Id("jsonrpcResponse").Dot("Error")),
+					Err().Op("=").Id("cli").Dot("decodeError"+method.Name).Call(Id("jsonrpcResponse").Dot("Error")),
 					Id("ret").CallFunc(func(cg *Group) {
 						for _, ret := range method.resultsWithoutError() {
 							cg.Id("response").Dot(utils.ToCamel(ret.Name))
@@ -124,3 +128,39 @@ func (svc *service) jsonrpcClientMethodFunc(ctx context.Context, method *method)
 		Return(),
 	)
 }
+
+// jsonrpcClientDecodeErrorFunc renders decoder of method errors, custom decoder of client options has priority.
+func (svc *service) jsonrpcClientDecodeErrorFunc(method *method) Code {
+
+	codes, errorTypes := svc.jsonrpcErrorTypes(method)
+
+	return Func().Params(Id("cli").Op("*").Id("Client" + svc.Name)).Id("decodeError" + method.Name).Params(Id("errData").Qual(packageJson, "RawMessage")).Params(Error()).BlockFunc(func(bg *Group) {
+
+		bg.Line().If(Id("cli").Dot("errorDecoder").Op("!=").Nil()).Block(
+			Return(Id("cli").Dot("errorDecoder").Call(Id("errData"))),
+		)
+		if len(codes) == 0 {
+			bg.Return(Id("decodeErrorJsonRPC").Call(Id("errData"), Nil()))
+			return
+		}
+		bg.Return(Id("decodeErrorJsonRPC").Call(Id("errData"), Func().Params(Id("code").Int(), Id("data").Qual(packageJson, "RawMessage")).Error().Block(
+			Switch(Id("code")).BlockFunc(func(sg *Group) {
+				for _, code := range codes {
+					tokens := strings.Split(errorTypes[code], ":")
+					sg.Case(Lit(code)).Block(
+						Var().Id("errType").Qual(tokens[0], tokens[1]),
+						If(Qual(packageJson, "Unmarshal").Call(Id("data"), Op("&").Id("errType")).Op("==").Nil()).Block(
+							If(List(Id("typed"), Id("ok")).Op(":=").Interface().Call(Id("errType")).Op(".").Call(Error()).Op(";").Id("ok")).Block(
+								Return(Id("typed")),
+							),
+							If(List(Id("typed"), Id("ok")).Op(":=").Interface().Call(Op("&").Id("errType")).Op(".").Call(Error()).Op(";").Id("ok")).Block(
+								Return(Id("typed")),
+							),
+						),
+					)
+				}
+			}),
+			Return(Nil()),
+		)))
+	})
+}
@@ -21,7 +21,10 @@ func (svc *service) renderJsonRPC(outDir string) (err error) {
 
 	srcFile.ImportName(packageGotils, "gotils")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+	svc.engine.importHttp(srcFile)
 	srcFile.ImportName(packageOpentracingExt, "ext")
 	srcFile.ImportName(svc.pkgPath, filepath.Base(svc.pkgPath))
 	srcFile.ImportName(packageOpentracing, "opentracing")
@@ -32,13 +35,14 @@ func (svc *service) renderJsonRPC(outDir string) (err error) {
 			continue
 		}
 
-		srcFile.Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("serve" + method.Name).Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
-			Id("http").Dot("serveMethod").Call(Id(_ctx_), Lit(method.lcName()), Id("http").Dot(method.lccName())),
+		srcFile.Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("serve" + method.Name).Params(svc.engine.handlerParams()...).Block(
+			Id("http").Dot("serveMethod").Call(append(svc.engine.handlerArgs(), Lit(method.lcName()), Id("http").Dot(method.lccName()))...),
 		)
 		srcFile.Add(svc.rpcMethodFunc(method))
 	}
 
 	srcFile.Line().Add(svc.serveServiceBatchFunc())
+	srcFile.Line().Add(svc.batchCallFunc())
 	srcFile.Line().Add(svc.serveMethodFunc())
 
 	return srcFile.Save(path.Join(outDir, svc.lcName()+"-jsonrpc.go"))
@@ -46,110 +50,107 @@ func (svc *service) renderJsonRPC(outDir string) (err error) {
 
 func (svc *service) serveServiceBatchFunc() Code {
 
-	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("serveBatch").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
+	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("serveBatch").Params(svc.engine.handlerParams()...).Block(
 
-		Line().Id("batchSpan").Op(":=").Id("extractSpan").Call(Id("http").Dot("log"), Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("URI").Call().Dot("Path").Call())), Id(_ctx_)),
-		Defer().Id("injectSpan").Call(Id("http").Dot("log"), Id("batchSpan"), Id(_ctx_)),
-		Defer().Id("batchSpan").Dot("Finish").Call(),
+		Line().Id("batchSpan").Op(":=").Add(svc.tracer.extractSpan("http", Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), svc.engine.requestPath()), svc.engine.request())),
+		svc.tracer.deferSpan(Id("http").Dot("log"), "batchSpan", svc.engine.handlerArgs()...),
+		svc.deferObserveHTTP(Lit("batch")),
 
-		Id("methodHTTP").Op(":=").Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Method").Call()),
+		Id("methodHTTP").Op(":=").Add(svc.engine.requestMethod()),
 
-		Line().If(Id("methodHTTP").Op("!=").Qual(packageFastHttp, "MethodPost")).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("batchSpan"), True()),
-			Id("batchSpan").Dot("SetTag").Call(Lit("msg"), Lit("only POST method supported")),
-			Id(_ctx_).Dot("Error").Call(Lit("only POST method supported"), Qual(packageFastHttp, "StatusMethodNotAllowed")),
+		Line().If(Id("methodHTTP").Op("!=").Add(svc.engine.status("MethodPost"))).Block(
+			svc.tracer.setError("batchSpan", Lit("only POST method supported")),
+			svc.engine.replyError(Lit("only POST method supported"), svc.engine.status("StatusMethodNotAllowed")),
 			Return(),
 		),
 
-		Line().If(Id("value").Op(":=").Id(_ctx_).Dot("Value").Call(Id("CtxCancelRequest")).Op(";").Id("value").Op("!=").Nil()).Block(
+		Line().If(Id("value").Op(":=").Add(svc.engine.cancelRequest()).Op(";").Id("value").Op("!=").Nil()).Block(
 			Return(),
 		),
 
-		Line().Var().Err().Error(),
-		Var().Id("requests").Op("[]").Id("baseJsonRPC"),
-
-		Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("PostBody").Call(), Op("&").Id("requests")).Op(";").Err().Op("!=").Nil()).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("batchSpan"), True()),
-			Id("batchSpan").Dot("SetTag").Call(Lit("msg"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-			Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
+		Line().Id("requests").Op(",").Id("errResponse").Op(":=").Id("decodeBatch").Call(append(svc.engine.requestBody(), Id("http").Dot("maxBatchSize"))...),
+		If(Id("errResponse").Op("!=").Nil()).Block(
+			svc.tracer.setError("batchSpan", Id("errResponse").Dot("Error").Dot("Message")),
+			svc.engine.sendResponse(Id("http").Dot("log"), Id("errResponse")),
 			Return(),
 		),
 
-		Line().Id("responses").Op(":=").Make(Id("jsonrpcResponses"), Lit(0), Len(Id("requests"))),
-
-		Line().Var().Id("wg").Qual(packageSync, "WaitGroup"),
-
-		Line().For(List(Id("_"), Id("request")).Op(":=").Range().Id("requests")).Block(
-
-			Line().Id("methodNameOrigin").Op(":=").Id("request").Dot("Method"),
-			Id("method").Op(":=").Qual(packageStrings, "ToLower").Call(Id("request").Dot("Method")),
+		Line().Id("responses").Op(":=").Id("callBatch").Call(Id("requests"), Id("http").Dot("maxParallelBatch"), Func().Params(Id("request").Id("baseJsonRPC")).Params(Op("*").Id("baseJsonRPC")).Block(
+			Return(Id("http").Dot("batchCall").Call(append(append([]Code{Id("batchSpan")}, svc.engine.handlerArgs()...), Id("request"))...)),
+		)),
+		svc.engine.sendResponse(Id("http").Dot("log"), Id("responses")),
+	)
+}
 
-			Line().Id("span").Op(":=").Qual(packageOpentracing, "StartSpan").Call(Id("request").Dot("Method"), Qual(packageOpentracing, "ChildOf").Call(Id("batchSpan").Dot("Context").Call())),
-			Id("span").Dot("SetTag").Call(Lit("batch"), True()),
+func (svc *service) batchCallFunc() Code {
 
-			Line().Switch(Id("method")).BlockFunc(func(bg *Group) {
+	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("batchCall").
+		Params(append(append([]Code{Id("batchSpan").Add(svc.tracer.spanType())}, svc.engine.handlerParams()...), Id("request").Id("baseJsonRPC"))...).
+		Params(Id("response").Op("*").Id("baseJsonRPC")).Block(
 
-				for _, method := range svc.methods {
+		Line().Id("methodNameOrigin").Op(":=").Id("request").Dot("Method"),
+		Id("method").Op(":=").Qual(packageStrings, "ToLower").Call(Id("request").Dot("Method")),
 
-					if !method.isJsonRPC() {
-						continue
-					}
+		Line().Id("span").Op(":=").Add(svc.tracer.startChild("batchSpan", Id("request").Dot("Method"))),
+		svc.tracer.setTag("span", Lit("batch"), True()),
+		svc.tracer.deferFinish("span"),
 
-					bg.Line().Case(Lit(method.lcName())).Block(
+		Line().Switch(Id("method")).BlockFunc(func(bg *Group) {
 
-						Line().Id("wg").Dot("Add").Call(Lit(1)),
+			for _, method := range svc.methods {
 
-						Func().Params(Id("request").Id("baseJsonRPC")).Block(
-							Id("responses").Dot("append").Call(Id("http").Dot(method.lccName()).Call(Id("span"), Id(_ctx_), Id("request"))),
-							Id("wg").Dot("Done").Call(),
-						).Call(Id("request")),
-					)
+				if !method.isJsonRPC() {
+					continue
 				}
-				bg.Line().Default().Block(
-					Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-					Id("span").Dot("SetTag").Call(Lit("msg"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'")),
-					Id("responses").Dot("append").Call(Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'"), Nil())),
+				bg.Case(Lit(method.lcName())).Block(
+					Return(Id("http").Dot(method.lccName()).Call(append(append([]Code{Id("span")}, svc.engine.handlerArgs()...), Id("request"))...)),
 				)
-			}),
-			Id("span").Dot("Finish").Call(),
-		),
-		Id("wg").Dot("Wait").Call(),
-		Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("responses")),
+			}
+			bg.Default().Block(
+				svc.tracer.setError("span", Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'")),
+				Return(Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'"), Nil())),
+			)
+		}),
 	)
 }
 
 func (svc *service) rpcMethodFunc(method *method) Code {
 
 	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id(method.lccName()).
-		Params(Id("span").Qual(packageOpentracing, "Span"), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("requestBase").Id("baseJsonRPC")).
+		Params(append(append([]Code{Id("span").Add(svc.tracer.spanType())}, svc.engine.handlerParams()...), Id("requestBase").Id("baseJsonRPC"))...).
 		Params(Id("responseBase").Op("*").Id("baseJsonRPC")).Block(
 
+		svc.deferObserveJsonRPC(method),
+		svc.deferRecover(Lit(method.lccName()), "span",
+			Id("responseBase").Op("=").Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("internalError"), Lit("internal error"), Nil()),
+		),
+
 		Line().Var().Err().Error(),
 		Var().Id("request").Id(method.requestStructName()),
 
 		Line().If(Id("requestBase").Dot("Params").Op("!=").Nil()).Block(
 			If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id("requestBase").Dot("Params"), Op("&").Id("request")).Op(";").Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
 			),
 		),
 
 		Line().If(Id("requestBase").Dot("Version").Op("!=").Id("Version")).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-			Id("span").Dot("SetTag").Call(Lit("msg"), Lit("incorrect protocol version: ").Op("+").Id("requestBase").Dot("Version")),
+			svc.tracer.setError("span", Lit("incorrect protocol version: ").Op("+").Id("requestBase").Dot("Version")),
 			Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("parseError"), Lit("incorrect protocol version: ").Op("+").Id("requestBase").Dot("Version"), Nil())),
 		),
 
-		Line().Id("methodContext").Op(":=").Qual(packageOpentracing, "ContextWithSpan").Call(Id(_ctx_), Id("span")),
+		Line().List(Id("methodContext"), Id("cancel")).Op(":=").Id("http").Dot("withTimeout").Call(svc.tracer.contextWithSpan(svc.requestContext(), "span"), method.timeoutCode()),
+		Defer().Id("cancel").Call(),
+		svc.authJsonRPC(method),
+		method.limitContext(),
 
 		method.httpArgHeaders(func(arg, header string) *Statement {
 
 			return Line().Id("methodContext").Op("=").Qual(packageContext, "WithValue").Call(Id("methodContext"), Lit(header), Id("_"+arg)).Line().
-				Id("span").Dot("SetTag").Call(Lit(header), Id("_"+arg)).Line().
+				Add(svc.tracer.setTag("span", Lit(header), Id("_"+arg))).Line().
 				Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call()),
 				Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("parseError"), Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call(), Nil())),
 			)
 		}),
@@ -157,14 +158,18 @@ func (svc *service) rpcMethodFunc(method *method) Code {
 		method.httpCookies(func(arg, header string) *Statement {
 
 			return Line().Id("methodContext").Op("=").Qual(packageContext, "WithValue").Call(Id("methodContext"), Lit(header), Id("_"+arg)).Line().
-				Id("span").Dot("SetTag").Call(Lit(header), Id("_"+arg)).Line().
+				Add(svc.tracer.setTag("span", Lit(header), Id("_"+arg))).Line().
 				Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call()),
 				Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("parseError"), Lit(fmt.Sprintf("http header '%s' could not be decoded: ", header)).Op("+").Err().Dot("Error").Call(), Nil())),
 			)
 		}),
 
+		method.validateRequest(
+			svc.tracer.setError("span", Lit("invalid params: ").Op("+").Err().Dot("Error").Call()),
+			Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("invalidParamsError"), Lit("invalid params: ").Op("+").Err().Dot("Error").Call(), Err())),
+		),
+
 		Line().Var().Id("response").Id(method.responseStructName()),
 
 		Line().ListFunc(func(lg *Group) {
@@ -192,10 +197,11 @@ func (svc *service) rpcMethodFunc(method *method) Code {
 			If(Id("http").Dot("errorHandler").Op("!=").Nil()).Block(
 				Err().Op("=").Id("http").Dot("errorHandler").Call(Err()),
 			),
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-			Id("span").Dot("SetTag").Call(Lit("msg"), Err()),
-			Id("span").Dot("SetTag").Call(Lit("errData"), Id("toString").Call(Err())),
-			Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("internalError"), Err().Dot("Error").Call(), Err())),
+			svc.tracer.setError("span", Err().Dot("Error").Call()),
+			svc.tracer.setTag("span", Lit("errData"), Id("toString").Call(Err())),
+			Id("code").Op(":=").Id("internalError"),
+			svc.errorCodeJsonRPC(method),
+			Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("code"), Err().Dot("Error").Call(), Err())),
 		),
 
 		Line().Id("responseBase").Op("=").Op("&").Id("baseJsonRPC").Values(Dict{
@@ -204,8 +210,7 @@ func (svc *service) rpcMethodFunc(method *method) Code {
 		}),
 
 		Line().If(List(Id("responseBase").Dot("Result"), Err()).Op("=").Qual(packageJson, "Marshal").Call(Id("response")).Op(";").Err().Op("!=").Nil()).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-			Id("span").Dot("SetTag").Call(Lit("msg"), Lit("response body could not be encoded: ").Op("+").Err().Dot("Error").Call()),
+			svc.tracer.setError("span", Lit("response body could not be encoded: ").Op("+").Err().Dot("Error").Call()),
 			Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("parseError"), Lit("response body could not be encoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
 		),
 		Return(),
@@ -214,29 +219,30 @@ func (svc *service) rpcMethodFunc(method *method) Code {
 
 func (svc *service) serveMethodFunc() Code {
 
-	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("serveMethod").
-		Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("methodName").String(), Id("methodHandler").Id("methodJsonRPC")).
+	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("serveMethod").
+		Params(append(svc.engine.handlerParams(), Id("methodName").String(), Id("methodHandler").Id("methodJsonRPC"))...).
 		BlockFunc(func(bg *Group) {
 
-			bg.Line().Id("span").Op(":=").Id("extractSpan").Call(
-				Id("http").Dot("log"),
-				Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("URI").Call().Dot("Path").Call())),
-				Id(_ctx_),
-			)
-			bg.Defer().Id("injectSpan").Call(Id("http").Dot("log"), Id("span"), Id(_ctx_))
-			bg.Defer().Id("span").Dot("Finish").Call()
-
-			bg.Line().Id("methodHTTP").Op(":=").Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Method").Call())
-
-			bg.Line().If(Id("methodHTTP").Op("!=").Qual(packageFastHttp, "MethodPost")).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("only POST method supported")),
-				Id(_ctx_).Dot("Error").Call(Lit("only POST method supported"), Qual(packageFastHttp, "StatusMethodNotAllowed")),
-			)
+			bg.Line().Id("span").Op(":=").Add(svc.tracer.extractSpan(
+				"http",
+				Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), svc.engine.requestPath()),
+				svc.engine.request(),
+			))
+			bg.Add(svc.tracer.deferSpan(Id("http").Dot("log"), "span", svc.engine.handlerArgs()...))
+			bg.Add(svc.deferObserveHTTP(Id("methodName")))
+
+			bg.Line().Id("methodHTTP").Op(":=").Add(svc.engine.requestMethod())
+
+			bg.Line().If(Id("methodHTTP").Op("!=").Add(svc.engine.status("MethodPost"))).BlockFunc(func(ig *Group) {
+				ig.Add(svc.tracer.setError("span", Lit("only POST method supported")))
+				ig.Add(svc.engine.replyError(Lit("only POST method supported"), svc.engine.status("StatusMethodNotAllowed")))
+				if svc.engine.isNetHTTP() {
+					ig.Return()
+				}
+			})
 
-			bg.Line().If(Id("value").Op(":=").Id(_ctx_).Dot("Value").Call(Id("CtxCancelRequest")).Op(";").Id("value").Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("request canceled")),
+			bg.Line().If(Id("value").Op(":=").Add(svc.engine.cancelRequest()).Op(";").Id("value").Op("!=").Nil()).Block(
+				svc.tracer.setError("span", Lit("request canceled")),
 				Return(),
 			)
 
@@ -244,10 +250,10 @@ func (svc *service) serveMethodFunc() Code {
 			bg.Var().Id("request").Id("baseJsonRPC")
 			bg.Var().Id("response").Op("*").Id("baseJsonRPC")
 
-			bg.Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("PostBody").Call(), Op("&").Id("request")).Op(";").Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
+			bg.Line().If(Err().Op("=").Add(svc.engine.decodeBody(Op("&").Id("request"))).Op(";").Err().Op("!=").Nil()).Block(
+				svc.tracer.setError("span", Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.bodyTooLarge(),
+				svc.engine.sendResponse(Id("http").Dot("log"), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
 				Return(),
 			)
 
@@ -255,15 +261,31 @@ func (svc *service) serveMethodFunc() Code {
 			bg.Id("method").Op(":=").Qual(packageStrings, "ToLower").Call(Id("request").Dot("Method"))
 
 			bg.Line().If(Id("method").Op("!=").Lit("").Op("&&").Id("method").Op("!=").Id("methodName")).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("invalid method ").Op("+").Id("methodNameOrigin")),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method ").Op("+").Id("methodNameOrigin"), Nil())),
+				svc.tracer.setError("span", Lit("invalid method ").Op("+").Id("methodNameOrigin")),
+				svc.engine.sendResponse(Id("http").Dot("log"), Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method ").Op("+").Id("methodNameOrigin"), Nil())),
 				Return(),
 			)
 
-			bg.Line().Id("response").Op("=").Id("methodHandler").Call(Id("span"), Id(_ctx_), Id("request"))
+			bg.Line().Id("response").Op("=").Id("methodHandler").Call(append(append([]Code{Id("span")}, svc.engine.handlerArgs()...), Id("request"))...)
 			bg.Line().If(Id("response").Op("!=").Nil()).Block(
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("response")),
+				svc.engine.sendResponse(Id("http").Dot("log"), Id("response")),
 			)
 		})
 }
+
+// errorCodeJsonRPC renders choice of error code, rejection by limits of method is checked first.
+func (svc *service) errorCodeJsonRPC(method *method) Code {
+
+	errorCode := If(List(Id("errCoder"), Id("ok")).Op(":=").Err().Op(".").Call(Id("withErrorCodeJsonRPC")).Op(";").Id("ok")).Block(
+		Id("code").Op("=").Id("errCoder").Dot("JsonRPCCode").Call(),
+	).Else().If(Qual(packageErrors, "Is").Call(Err(), Qual(packageContext, "DeadlineExceeded"))).Block(
+		Id("code").Op("=").Id("timeoutError"),
+	)
+	if !method.hasLimits() {
+		return errorCode
+	}
+	return If(Qual(packageErrors, "Is").Call(Err(), Id("ErrLimitExceeded"))).Block(
+		Id("code").Op("=").Id("limitExceededError"),
+		svc.limitRejection(method),
+	).Else().Add(errorCode)
+}
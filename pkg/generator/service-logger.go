@@ -12,7 +12,6 @@ import (
 
 	. "github.com/dave/jennifer/jen"
 
-	"github.com/seniorGolang/tg/pkg/tags"
 	"github.com/seniorGolang/tg/pkg/utils"
 )
 
@@ -68,23 +67,22 @@ func (svc *service) loggerFuncBody(method *method) func(g *Group) {
 				d[Lit("service")] = Lit(svc.Name)
 				d[Lit("method")] = Lit(method.lccName())
 
-				skipFields := strings.Split(tags.ParseTags(method.Docs).Value("log-skip"), ",")
-				params := method.argsWithoutContext()
-				params = removeSkippedFields(params, skipFields)
+				skipFields := svc.logSkipFields(method)
+				params := removeSkippedFields(method.argsWithoutContext(), skipFields)
 
-				d[Lit("request")] = Qual(packageViewer, "Sprintf").Call(Lit("%+v"), Id(method.requestStructName()).Values(utils.DictByNormalVariables(params, params)))
+				d[Lit("request")] = Qual(packageViewer, "Sprintf").Call(Lit("%+v"), Id("redactLog").Call(Id(method.requestStructName()).Values(utils.DictByNormalVariables(params, params))))
 
 				printResult := true
 				for _, field := range skipFields {
-					if strings.TrimSpace(field) == "response" {
+					if field == "response" {
 						printResult = false
 						break
 					}
 				}
-				returns := method.resultsWithoutError()
+				returns := removeSkippedFields(method.resultsWithoutError(), skipFields)
 
 				if printResult {
-					d[Lit("response")] = Qual(packageViewer, "Sprintf").Call(Lit("%+v"), Id(method.responseStructName()).Values(utils.DictByNormalVariables(returns, returns)))
+					d[Lit("response")] = Qual(packageViewer, "Sprintf").Call(Lit("%+v"), Id("redactLog").Call(Id(method.responseStructName()).Values(utils.DictByNormalVariables(returns, returns))))
 				}
 
 				d[Lit("took")] = Qual(packageTime, "Since").Call(Id("begin"))
@@ -96,13 +94,35 @@ func (svc *service) loggerFuncBody(method *method) func(g *Group) {
 
 			g.If(Id("err").Op("!=").Id("nil")).BlockFunc(func(g *Group) {
 
-				g.Id("m").Dot("log").Dot("WithError").Call(Err()).Dot("WithFields").Call(Id("fields")).Dot("Info").Call(Lit(fmt.Sprintf("call %s", method.lccName())))
+				g.Id("m").Dot("log").Dot("WithError").Call(Err()).Dot("WithFields").Call(Id("fields")).Dot(svc.logLevel(method, tagLogErrorLevel)).Call(Lit(fmt.Sprintf("call %s", method.lccName())))
 				g.Return()
 			})
 
-			g.Id("m").Dot("log").Dot("WithFields").Call(Id("fields")).Dot("Info").Call(Lit(fmt.Sprintf("call %s", method.lccName())))
+			g.Id("m").Dot("log").Dot("WithFields").Call(Id("fields")).Dot(svc.logLevel(method, tagLogLevel)).Call(Lit(fmt.Sprintf("call %s", method.lccName())))
 
 		}).Call(Qual(packageTime, "Now").Call())
 		g.Return().Id("m").Dot(_next_).Dot(method.Name).Call(paramNames(method.Args))
 	}
 }
+
+// logSkipFields returns names of variables, which are not logged by annotations of interface and method.
+func (svc *service) logSkipFields(method *method) (skipFields []string) {
+
+	for _, value := range []string{svc.tags.Value(tagLogSkip), method.tags.Value(tagLogSkip)} {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				skipFields = append(skipFields, field)
+			}
+		}
+	}
+	return
+}
+
+// logLevel returns name of logger method for level annotation, method annotation has priority over interface.
+func (svc *service) logLevel(method *method, tagName string) string {
+
+	if level, found := logLevels[strings.ToLower(method.tags.Value(tagName, svc.tags.Value(tagName, "info")))]; found {
+		return level
+	}
+	return "Info"
+}
@@ -19,13 +19,18 @@ func (svc *service) renderMetrics(outDir string) (err error) {
 	ctx := context.WithValue(context.Background(), "code", srcFile)
 
 	srcFile.ImportName(packageGoKitMetrics, "metrics")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 	srcFile.ImportName(svc.pkgPath, filepath.Base(svc.pkgPath))
+	svc.engine.importHttp(srcFile)
 
 	srcFile.Type().Id("metrics"+svc.Name).Struct(
 		Id(_next_).Qual(svc.pkgPath, svc.Name),
 		Id("requestCount").Qual(packageGoKitMetrics, "Counter"),
 		Id("requestCountAll").Qual(packageGoKitMetrics, "Counter"),
 		Id("requestLatency").Qual(packageGoKitMetrics, "Histogram"),
+		Id("requestsInFlight").Qual(packageGoKitMetrics, "Gauge"),
 	)
 
 	srcFile.Line().Add(svc.metricsMiddleware())
@@ -33,22 +38,107 @@ func (svc *service) renderMetrics(outDir string) (err error) {
 	for _, method := range svc.methods {
 		srcFile.Line().Func().Params(Id("m").Id("metrics" + svc.Name)).Id(method.Name).Params(funcDefinitionParams(ctx, method.Args)).Params(funcDefinitionParams(ctx, method.Results)).BlockFunc(svc.metricFuncBody(method))
 	}
+
+	if svc.tags.Contains(tagServerHTTP) || svc.tags.Contains(tagServerJsonRPC) {
+		srcFile.Line().Add(svc.observeHTTPFunc())
+	}
+	if svc.tags.Contains(tagServerJsonRPC) {
+		srcFile.Line().Add(svc.observeJsonRPCFunc())
+	}
 	return srcFile.Save(path.Join(outDir, svc.lcName()+"-metrics.go"))
 }
 
 func (svc *service) metricsMiddleware() Code {
 
-	return Func().Id("metricsMiddleware" + svc.Name).Params(Id(_next_).Qual(svc.pkgPath, svc.Name)).Params(Qual(svc.pkgPath, svc.Name)).
-		BlockFunc(func(g *Group) {
-			g.Return(Op("&").Id("metrics" + svc.Name).Values(
-				Dict{
-					Id(_next_):            Id(_next_),
-					Id("requestCount"):    Id("RequestCount").Op(".").Id("With").Call(Lit("service"), Lit(svc.Name)),
-					Id("requestCountAll"): Id("RequestCountAll").Op(".").Id("With").Call(Lit("service"), Lit(svc.Name)),
-					Id("requestLatency"):  Id("RequestLatency").Op(".").Id("With").Call(Lit("service"), Lit(svc.Name)),
-				},
-			))
-		})
+	return Func().Id("metricsMiddleware" + svc.Name).Params(Id("metrics").Op("*").Id("Metrics")).Params(Id("Middleware" + svc.Name)).Block(
+		Return(Func().Params(Id(_next_).Qual(svc.pkgPath, svc.Name)).Params(Qual(svc.pkgPath, svc.Name)).Block(
+			Return(Op("&").Id("metrics" + svc.Name).Values(Dict{
+				Id(_next_):             Id(_next_),
+				Id("requestCount"):     Id("metrics").Dot("requestCount").Dot("With").Call(Lit("service"), Lit(svc.Name)),
+				Id("requestCountAll"):  Id("metrics").Dot("requestCountAll").Dot("With").Call(Lit("service"), Lit(svc.Name)),
+				Id("requestLatency"):   Id("metrics").Dot("requestLatency").Dot("With").Call(Lit("service"), Lit(svc.Name)),
+				Id("requestsInFlight"): Id("metrics").Dot("requestsInFlight").Dot("With").Call(Lit("service"), Lit(svc.Name)),
+			})),
+		)),
+	)
+}
+
+// observeHTTPFunc renders collecting of body sizes and status code, it is deferred by HTTP handlers of methods.
+func (svc *service) observeHTTPFunc() Code {
+
+	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("observeHTTP").Params(append([]Code{Id("method").String()}, svc.engine.handlerParams()...)...).Block(
+		observeHTTPCode(svc.engine, Id("http").Dot("metrics"), Id("method"), Lit(svc.Name))...,
+	)
+}
+
+// observeHTTPCode renders observing of response by metrics, size of net/http request is known by Content-Length only,
+// because body of request is streamed to handler.
+func observeHTTPCode(engine httpEngine, metrics *Statement, method, service Code) []Code {
+
+	if engine.isNetHTTP() {
+		return []Code{
+			Line().List(Id("response"), Id("ok")).Op(":=").Id("w").Op(".").Call(Op("*").Id("responseWriter")),
+			If(Add(metrics).Op("==").Nil().Op("||").Op("!").Id("ok")).Block(
+				Return(),
+			),
+			If(Id("r").Dot("ContentLength").Op(">=").Lit(0)).Block(
+				Add(metrics).Dot("requestSize").Dot("With").Call(Lit("method"), method, Lit("service"), service).
+					Dot("Observe").Call(Float64().Call(Id("r").Dot("ContentLength"))),
+			),
+			Add(metrics).Dot("responseSize").Dot("With").Call(Lit("method"), method, Lit("service"), service).
+				Dot("Observe").Call(Float64().Call(Id("response").Dot("body").Dot("Len").Call())),
+			Add(metrics).Dot("httpResponses").Dot("With").Call(Lit("method"), method, Lit("service"), service, Lit("code"), Qual(packageStrconv, "Itoa").Call(Id("response").Dot("status").Call())).
+				Dot("Add").Call(Lit(1)),
+		}
+	}
+	return []Code{
+		Line().If(Add(metrics).Op("==").Nil()).Block(
+			Return(),
+		),
+		Add(metrics).Dot("requestSize").Dot("With").Call(Lit("method"), method, Lit("service"), service).
+			Dot("Observe").Call(Float64().Call(Len(Id(_ctx_).Dot("Request").Dot("Body").Call()))),
+		Add(metrics).Dot("responseSize").Dot("With").Call(Lit("method"), method, Lit("service"), service).
+			Dot("Observe").Call(Float64().Call(Len(Id(_ctx_).Dot("Response").Dot("Body").Call()))),
+		Add(metrics).Dot("httpResponses").Dot("With").Call(Lit("method"), method, Lit("service"), service, Lit("code"), Qual(packageStrconv, "Itoa").Call(Id(_ctx_).Dot("Response").Dot("StatusCode").Call())).
+			Dot("Add").Call(Lit(1)),
+	}
+}
+
+// observeJsonRPCFunc renders counting of jsonRPC responses by error code, successful responses have code 0.
+func (svc *service) observeJsonRPCFunc() Code {
+
+	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id("observeJsonRPC").Params(Id("method").String(), Id("response").Op("*").Id("baseJsonRPC")).Block(
+
+		Line().If(Id("http").Dot("metrics").Op("==").Nil().Op("||").Id("response").Op("==").Nil()).Block(
+			Return(),
+		),
+		Id("code").Op(":=").Lit(0),
+		If(Id("response").Dot("Error").Op("!=").Nil()).Block(
+			Id("code").Op("=").Id("response").Dot("Error").Dot("Code"),
+		),
+		Id("http").Dot("metrics").Dot("jsonRPCResponses").Dot("With").Call(Lit("method"), Id("method"), Lit("service"), Lit(svc.Name), Lit("code"), Qual(packageStrconv, "Itoa").Call(Id("code"))).
+			Dot("Add").Call(Lit(1)),
+	)
+}
+
+// deferObserveHTTP renders collecting of HTTP metrics for services with metrics.
+func (svc *service) deferObserveHTTP(method Code) Code {
+
+	if !svc.tags.Contains(tagMetrics) {
+		return Null()
+	}
+	return Defer().Id("http").Dot("observeHTTP").Call(append([]Code{method}, svc.engine.handlerArgs()...)...)
+}
+
+// deferObserveJsonRPC renders counting of method response, closure is used to observe named result on return.
+func (svc *service) deferObserveJsonRPC(method *method) Code {
+
+	if !svc.tags.Contains(tagMetrics) {
+		return Null()
+	}
+	return Defer().Func().Params().Block(
+		Id("http").Dot("observeJsonRPC").Call(Lit(method.lccName()), Id("responseBase")),
+	).Call()
 }
 
 func (svc *service) metricFuncBody(method *method) func(g *Group) {
@@ -67,6 +157,9 @@ func (svc *service) metricFuncBody(method *method) func(g *Group) {
 			Lit("success"), Qual(packageFmt, "Sprint").Call(Err().Op("==").Nil())).
 			Dot("Add").Call(Lit(1))
 
+		g.Line().Id("m").Dot("requestsInFlight").Dot("With").Call(Lit("method"), Lit(method.lccName())).Dot("Add").Call(Lit(1))
+		g.Defer().Id("m").Dot("requestsInFlight").Dot("With").Call(Lit("method"), Lit(method.lccName())).Dot("Add").Call(Lit(-1))
+
 		g.Line().Id("m").Dot("requestCountAll").Dot("With").Call(
 			Lit("method"), Lit(method.lccName())).
 			Dot("Add").Call(Lit(1))
@@ -29,5 +29,11 @@ func (svc *service) renderMiddleware(outDir string) (err error) {
 	for _, method := range svc.methods {
 		srcFile.Type().Id("Middleware" + svc.Name + method.Name).Func().Params(Id("next").Id(svc.Name + method.Name)).Params(Id(svc.Name + method.Name))
 	}
+
+	for _, method := range svc.methods {
+		if method.hasLimits() {
+			srcFile.Line().Add(svc.limitMiddlewareFunc(ctx, method))
+		}
+	}
 	return srcFile.Save(path.Join(outDir, svc.lcName()+"-middleware.go"))
 }
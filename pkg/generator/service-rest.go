@@ -21,7 +21,10 @@ func (svc *service) renderREST(outDir string) (err error) {
 
 	srcFile.ImportName(packageGotils, "gotils")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !svc.engine.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+	svc.engine.importHttp(srcFile)
 	srcFile.ImportName(packageOpentracingExt, "ext")
 	srcFile.ImportName(svc.pkgPath, filepath.Base(svc.pkgPath))
 	srcFile.ImportName(packageOpentracing, "opentracing")
@@ -42,7 +45,7 @@ func (svc *service) httpMethodFunc(method *method) Code {
 
 	return Func().Params(Id("http").Op("*").Id("http"+svc.Name)).Id(method.lccName()).Params(Id(_ctx_).Qual(packageContext, "Context"), Id("request").Id(method.requestStructName())).Params(Id("response").Id(method.responseStructName()), Err().Error()).Block(
 
-		Line().Id("span").Op(":=").Qual(packageOpentracing, "SpanFromContext").Call(Id(_ctx_)),
+		Line().Add(svc.tracer.spanFromContext("span", Id(_ctx_))),
 
 		ListFunc(func(lg *Group) {
 
@@ -66,110 +69,126 @@ func (svc *service) httpMethodFunc(method *method) Code {
 			}
 		}),
 
-		Line().If(Err().Op("!=").Nil()).Block(
-			If(Id("http").Dot("errorHandler").Op("!=").Nil()).Block(
+		Line().If(Err().Op("!=").Nil()).BlockFunc(func(bg *Group) {
+			bg.If(Id("http").Dot("errorHandler").Op("!=").Nil()).Block(
 				Err().Op("=").Id("http").Dot("errorHandler").Call(Err()),
-			),
-			Id("errData").Op(":=").Id("toString").Call(Err()),
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-			Id("span").Dot("SetTag").Call(Lit("msg"), Err().Dot("Error").Call()),
-
-			Line().If(Id("errData").Op("!=").Lit("{}")).Block(
-				Id("span").Dot("SetTag").Call(Lit("errData"), Id("errData")),
-			),
-		),
+			)
+			if svc.tracer.isNone() {
+				return
+			}
+			bg.Id("errData").Op(":=").Id("toString").Call(Err())
+			bg.Add(svc.tracer.setError("span", Err().Dot("Error").Call()))
+
+			bg.Line().If(Id("errData").Op("!=").Lit("{}")).Block(
+				svc.tracer.setTag("span", Lit("errData"), Id("errData")),
+			)
+		}),
 		Return(),
 	)
 }
 
 func (svc *service) httpServeMethodFunc(method *method) Code {
 
-	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("serve" + method.Name).Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).BlockFunc(func(bg *Group) {
-
-		bg.Line().Id("span").Op(":=").Id("extractSpan").Call(
-			Id("http").Dot("log"),
-			Qual(packageFmt, "Sprintf").Call(Lit("request:%s"), Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("URI").Call().Dot("Path").Call())),
-			Id(_ctx_),
-		)
-		bg.Defer().Id("injectSpan").Call(Id("http").Dot("log"), Id("span"), Id(_ctx_))
-		bg.Defer().Id("span").Dot("Finish").Call()
-
-		bg.Line().If(Id("value").Op(":=").Id(_ctx_).Dot("Value").Call(Id("CtxCancelRequest")).Op(";").Id("value").Op("!=").Nil()).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-			Id("span").Dot("SetTag").Call(Lit("msg"), Lit("request canceled")),
+	return Func().Params(Id("http").Op("*").Id("http" + svc.Name)).Id("serve" + method.Name).Params(svc.engine.handlerParams()...).BlockFunc(func(bg *Group) {
+
+		bg.Line().Id("span").Op(":=").Add(svc.tracer.extractSpan(
+			"http",
+			Qual(packageFmt, "Sprintf").Call(Lit("request:%s"), svc.engine.requestPath()),
+			svc.engine.request(),
+		))
+		bg.Add(svc.tracer.deferSpan(Id("http").Dot("log"), "span", svc.engine.handlerArgs()...))
+		bg.Add(svc.deferObserveHTTP(Lit(method.lccName())))
+		bg.Add(svc.deferRecover(Lit(method.lccName()), "span",
+			svc.engine.replyError(svc.engine.statusText(svc.engine.status("StatusInternalServerError")), svc.engine.status("StatusInternalServerError")),
+		))
+
+		bg.Line().If(Id("value").Op(":=").Add(svc.engine.cancelRequest()).Op(";").Id("value").Op("!=").Nil()).Block(
+			svc.tracer.setError("span", Lit("request canceled")),
 			Return(),
 		)
+		bg.Add(svc.authHTTP(method))
 
 		bg.Line().Var().Err().Error()
 		bg.Var().Id("request").Id(method.requestStructName())
 		if successCode := method.tags.ValueInt(tagHttpSuccess, 0); successCode != 0 {
-			bg.Id(_ctx_).Dot("SetStatusCode").Call(Lit(successCode))
+			bg.Add(svc.engine.setStatus(Lit(successCode)))
 		}
 
 		if len(method.arguments()) != 0 {
-			bg.Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("Request").Dot("Body").Call(), Op("&").Id("request")).Op(";").Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id(_ctx_).Dot("Response").Dot("SetStatusCode").Call(Qual(packageFastHttp, "StatusBadRequest")),
-				Id(_ctx_).Dot("WriteString").Call(Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+			var decode Code = Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("Request").Dot("Body").Call(), Op("&").Id("request"))
+			var setStatus Code = Id(_ctx_).Dot("Response").Dot("SetStatusCode").Call(Qual(packageFastHttp, "StatusBadRequest"))
+			if svc.engine.isNetHTTP() {
+				decode = svc.engine.decodeBody(Op("&").Id("request"))
+				setStatus = svc.engine.setStatus(svc.engine.bodyErrorStatus())
+			}
+			bg.Line().If(Err().Op("=").Add(decode).Op(";").Err().Op("!=").Nil()).Block(
+				svc.tracer.setError("span", Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				setStatus,
+				svc.engine.writeString(Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}
 
 		bg.Add(method.urlArgs(func(arg, header string) *Statement {
 			return Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("path arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Lit("path arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit("path arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.sendResponse(Id("http").Dot("log"), Lit("path arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}))
 
 		bg.Add(method.urlParams(func(arg, header string) *Statement {
 			return Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("url arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Lit("url arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit("url arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.sendResponse(Id("http").Dot("log"), Lit("url arguments could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}))
 
 		bg.Add(method.httpArgHeaders(func(arg, header string) *Statement {
 			return Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.sendResponse(Id("http").Dot("log"), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}))
 
 		bg.Add(method.httpCookies(func(arg, header string) *Statement {
 			return Line().If(Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.tracer.setError("span", Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.sendResponse(Id("http").Dot("log"), Lit("http header could not be decoded: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}))
 
 		for uploadVar, uploadKey := range method.uploadVarsMap() {
 
-			bg.Line().If(List(Id("request").Dot(utils.ToCamel(uploadVar)), Err()).Op("=").Id("uploadFile").Call(Id(_ctx_), Lit(uploadKey)).Op(";").Err().Op("!=").Nil()).Block(
-				Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-				Id("span").Dot("SetTag").Call(Lit("msg"), Lit("upload file '"+uploadVar+"' error: ").Op("+").Err().Dot("Error").Call()),
-				Id(_ctx_).Dot("SetStatusCode").Call(Qual(packageFastHttp, "StatusBadRequest")),
-				Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Lit("upload file '"+uploadVar+"' error: ").Op("+").Err().Dot("Error").Call()),
+			bg.Line().If(List(Id("request").Dot(utils.ToCamel(uploadVar)), Err()).Op("=").Id("uploadFile").Call(svc.engine.request(), Lit(uploadKey)).Op(";").Err().Op("!=").Nil()).Block(
+				svc.tracer.setError("span", Lit("upload file '"+uploadVar+"' error: ").Op("+").Err().Dot("Error").Call()),
+				svc.engine.setStatus(svc.engine.bodyErrorStatus()),
+				svc.engine.sendResponse(Id("http").Dot("log"), Lit("upload file '"+uploadVar+"' error: ").Op("+").Err().Dot("Error").Call()),
 				Return(),
 			)
 		}
 
+		bg.Add(method.validateRequest(
+			svc.tracer.setError("span", Lit("invalid params: ").Op("+").Err().Dot("Error").Call()),
+			svc.engine.setStatus(svc.engine.status("StatusBadRequest")),
+			svc.engine.sendResponse(Id("http").Dot("log"), Err()),
+			Return(),
+		))
+
 		if responseMethod := method.tags.Value(tagHttpResponse, ""); responseMethod != "" {
-			bg.Add(toID(responseMethod).Call(Id(_ctx_), Id("http").Dot("base"), Err(), callParamNames("request", method.argsWithoutContext())))
+			bg.Add(toID(responseMethod).Call(append(svc.engine.handlerArgs(), Id("http").Dot("base"), Err(), callParamNames("request", method.argsWithoutContext()))...))
 		} else {
 
 			bg.Var().Id("result").Interface()
 			bg.Line().Var().Id("response").Id(method.responseStructName())
-			bg.List(Id("response"), Err()).Op("=").Id("http").Dot(method.lccName()).Call(Qual(packageOpentracing, "ContextWithSpan").Call(Id(_ctx_), Id("span")), Id("request"))
+			bg.Line().List(Id("methodContext"), Id("cancel")).Op(":=").Id("http").Dot("withTimeout").Call(svc.tracer.contextWithSpan(svc.requestContext(), "span"), method.timeoutCode())
+			bg.Defer().Id("cancel").Call()
+			bg.Add(method.authContext())
+			bg.Add(method.limitContext())
+			bg.List(Id("response"), Err()).Op("=").Id("http").Dot(method.lccName()).Call(Id("methodContext"), Id("request"))
 			bg.Id("result").Op("=").Id("response")
 
 			ex := Line()
@@ -179,7 +198,7 @@ func (svc *service) httpServeMethodFunc(method *method) Code {
 						ex.If(List(Id("rCookie"), Id("ok")).Op(":=").
 							Qual(packageReflect, "ValueOf").Call(Id("response").Dot(utils.ToCamel(retName))).Dot("Interface").Call().
 							Op(".").Call(Id("cookieType"))).Op(";").Id("ok").Op("&&").Id("response").Dot(utils.ToCamel(retName)).Op("!=").Nil().Block(
-							Id(_ctx_).Dot("Response").Dot("Header").Dot("SetCookie").Call(Id("rCookie").Dot("Cookie").Call()),
+							svc.setCookie(Id("rCookie").Dot("Cookie").Call()),
 						)
 					}
 				}
@@ -188,15 +207,24 @@ func (svc *service) httpServeMethodFunc(method *method) Code {
 			if len(*ex) > 1 {
 				bg.Line().If(Err().Op("==").Nil()).Block(ex)
 			}
+			statusCode := If(List(Id("errCoder"), Id("ok")).Op(":=").Err().Op(".").Call(Id("withErrorCode")).Op(";").Id("ok")).Block(
+				svc.engine.setStatus(Id("errCoder").Dot("Code").Call()),
+			).Else().If(Qual(packageErrors, "Is").Call(Err(), Qual(packageContext, "DeadlineExceeded"))).Block(
+				svc.engine.setStatus(svc.engine.status("StatusGatewayTimeout")),
+			).Else().Block(
+				svc.engine.setStatus(svc.engine.status("StatusInternalServerError")),
+			)
+			if method.hasLimits() {
+				statusCode = If(Qual(packageErrors, "Is").Call(Err(), Id("ErrLimitExceeded"))).Block(
+					svc.engine.setStatus(svc.engine.status("StatusTooManyRequests")),
+					svc.limitRejection(method),
+				).Else().Add(statusCode)
+			}
 			bg.Line().If(Err().Op("!=").Nil()).Block(
 				Id("result").Op("=").Err(),
-				If(List(Id("errCoder"), Id("ok")).Op(":=").Err().Op(".").Call(Id("withErrorCode")).Op(";").Id("ok")).Block(
-					Id(_ctx_).Dot("SetStatusCode").Call(Id("errCoder").Dot("Code").Call()),
-				).Else().Block(
-					Id(_ctx_).Dot("SetStatusCode").Call(Qual(packageFastHttp, "StatusInternalServerError")),
-				),
+				statusCode,
 			)
-			bg.Id("sendResponse").Call(Id("http").Dot("log"), Id(_ctx_), Id("result"))
+			bg.Add(svc.engine.sendResponse(Id("http").Dot("log"), Id("result")))
 		}
 	})
 }
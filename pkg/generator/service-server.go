@@ -56,8 +56,8 @@ func (svc *service) renderServer(outDir string) (err error) {
 	}
 
 	if svc.tags.Contains(tagMetrics) {
-		srcFile.Line().Func().Params(Id("srv").Op("*").Id("server" + svc.Name)).Id("WithMetrics").Params().Block(
-			Id("srv").Dot("Wrap").Call(Id("metricsMiddleware" + svc.Name)),
+		srcFile.Line().Func().Params(Id("srv").Op("*").Id("server" + svc.Name)).Id("WithMetrics").Params(Id("metrics").Op("*").Id("Metrics")).Block(
+			Id("srv").Dot("Wrap").Call(Id("metricsMiddleware" + svc.Name).Call(Id("metrics"))),
 		)
 	}
 
@@ -77,6 +77,10 @@ func (svc *service) wrapFunc() Code {
 		bg.Id("srv").Dot("svc").Op("=").Id("m").Call(Id("srv").Dot("svc"))
 
 		for _, method := range svc.methods {
+			if method.hasLimits() {
+				bg.Id("srv").Dot(method.lccName()).Op("=").Id("srv").Dot("limit" + method.Name).Call(Id("srv").Dot("svc").Dot(method.Name))
+				continue
+			}
 			bg.Id("srv").Dot(method.lccName()).Op("=").Id("srv").Dot("svc").Dot(method.Name)
 		}
 	})
@@ -84,6 +88,9 @@ func (svc *service) wrapFunc() Code {
 
 func (svc *service) newServerFunc() Code {
 
+	if svc.hasLimits() {
+		return svc.newLimitedServerFunc()
+	}
 	return Func().Id("newServer" + svc.Name).Params(Id("svc").Qual(svc.pkgPath, svc.Name)).Params(Op("*").Id("server" + svc.Name)).Block(
 		Return(Op("&").Id("server" + svc.Name).Values(DictFunc(func(dict Dict) {
 
@@ -96,6 +103,32 @@ func (svc *service) newServerFunc() Code {
 	)
 }
 
+// newLimitedServerFunc renders constructor, which keeps limiter middlewares, so Wrap applies them again.
+func (svc *service) newLimitedServerFunc() Code {
+
+	return Func().Id("newServer" + svc.Name).Params(Id("svc").Qual(svc.pkgPath, svc.Name)).Params(Id("srv").Op("*").Id("server" + svc.Name)).BlockFunc(func(bg *Group) {
+
+		bg.Line().Id("srv").Op("=").Op("&").Id("server" + svc.Name).Values(DictFunc(func(dict Dict) {
+
+			dict[Id("svc")] = Id("svc")
+
+			for _, method := range svc.methods {
+				if method.hasLimits() {
+					dict[Id("limit"+method.Name)] = method.limitersCode()
+					continue
+				}
+				dict[Id(method.lccName())] = Id("svc").Dot(method.Name)
+			}
+		}))
+		for _, method := range svc.methods {
+			if method.hasLimits() {
+				bg.Id("srv").Dot(method.lccName()).Op("=").Id("srv").Dot("limit" + method.Name).Call(Id("svc").Dot(method.Name))
+			}
+		}
+		bg.Return()
+	})
+}
+
 func (svc *service) serverType() Code {
 
 	return Type().Id("server" + svc.Name).StructFunc(func(sg *Group) {
@@ -105,6 +138,11 @@ func (svc *service) serverType() Code {
 		for _, method := range svc.methods {
 			sg.Id(method.lccName()).Id(svc.Name + method.Name)
 		}
+		for _, method := range svc.methods {
+			if method.hasLimits() {
+				sg.Id("limit" + method.Name).Id("Middleware" + svc.Name + method.Name)
+			}
+		}
 	})
 }
 
@@ -118,8 +156,14 @@ func (svc *service) middlewareSetType() Code {
 			ig.Id("Wrap" + method.Name).Params(Id("m").Id("Middleware" + svc.Name + method.Name))
 		}
 		ig.Line()
-		ig.Id("WithTrace").Params()
-		ig.Id("WithMetrics").Params()
-		ig.Id("WithLog").Params(Id("log").Qual(packageLogrus, "FieldLogger"))
+		if svc.tags.Contains(tagTrace) {
+			ig.Id("WithTrace").Params()
+		}
+		if svc.tags.Contains(tagMetrics) {
+			ig.Id("WithMetrics").Params(Id("metrics").Op("*").Id("Metrics"))
+		}
+		if svc.tags.Contains(tagLogger) {
+			ig.Id("WithLog").Params(Id("log").Qual(packageLogrus, "FieldLogger"))
+		}
 	})
 }
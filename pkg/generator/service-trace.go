@@ -35,8 +35,8 @@ func (svc *service) renderTrace(outDir string) (err error) {
 	for _, method := range svc.methods {
 		srcFile.Line().Func().Params(Id("svc").Id("trace"+svc.Name)).Id(method.Name).Params(funcDefinitionParams(ctx, method.Args)).Params(funcDefinitionParams(ctx, method.Results)).Block(
 
-			Id("span").Op(":=").Qual(packageOpentracing, "SpanFromContext").Call(Id(_ctx_)),
-			Id("span").Dot("SetTag").Call(Lit("method"), Lit(method.Name)),
+			svc.tracer.spanFromContext("span", Id(_ctx_)),
+			svc.tracer.setTag("span", Lit("method"), Lit(method.Name)),
 
 			Return(Id("svc").Dot("next").Dot(method.Name).CallFunc(func(cg *Group) {
 				for _, arg := range method.Args {
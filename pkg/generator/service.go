@@ -23,9 +23,15 @@ type service struct {
 	pkgPath string
 	methods []*method
 	tags    tags.DocTags
+	cors    tags.DocTags
+	tracer  tracing
 
 	testsPath      string
 	implementsPath string
+
+	openAPI     string
+	swaggerJSON bool
+	engine      httpEngine
 }
 
 func newService(log logrus.FieldLogger, filePath string, iface types.Interface, options ...Option) (svc *service) {
@@ -61,42 +67,57 @@ func (svc service) lccName() string {
 
 func (svc *service) renderClient(outDir string) (err error) {
 
-	err = svc.renderExchange(outDir)
+	var errs renderErrors
+
+	errs.add(svc.log, svc.renderExchange(outDir), "renderExchange")
 
 	if svc.tags.Contains(tagServerJsonRPC) {
-		err = svc.renderClientJsonRPC(outDir)
+		errs.add(svc.log, svc.renderClientJsonRPC(outDir), "renderClientJsonRPC")
 	}
-	return
+	if svc.tags.Contains(tagServerHTTP) {
+		errs.add(svc.log, svc.renderClientHTTP(outDir), "renderClientHTTP")
+	}
+	return errs.err()
 }
 
 func (svc *service) render(outDir string) (err error) {
 
-	showError(svc.log, svc.renderHTTP(outDir), "renderHTTP")
-	showError(svc.log, svc.renderServer(outDir), "renderServer")
-	showError(svc.log, svc.renderExchange(outDir), "renderExchange")
-	showError(svc.log, svc.renderMiddleware(outDir), "renderMiddleware")
-	// showError(svc.log, svc.renderImplement(svc.implementsPath), "renderImplement")
+	var errs renderErrors
+
+	errs.add(svc.log, svc.renderHTTP(outDir), "renderHTTP")
+	errs.add(svc.log, svc.renderServer(outDir), "renderServer")
+	errs.add(svc.log, svc.renderExchange(outDir), "renderExchange")
+	errs.add(svc.log, svc.renderMiddleware(outDir), "renderMiddleware")
+	// errs.add(svc.log, svc.renderImplement(svc.implementsPath), "renderImplement")
 
 	if svc.tags.Contains(tagTests) {
-		showError(svc.log, svc.renderTest(svc.testsPath), "renderTest")
+		errs.add(svc.log, svc.renderTest(svc.testsPath), "renderTest")
 	}
 
 	if svc.tags.Contains(tagTrace) {
-		showError(svc.log, svc.renderTrace(outDir), "renderTrace")
+		errs.add(svc.log, svc.renderTrace(outDir), "renderTrace")
 	}
 	if svc.tags.Contains(tagMetrics) {
-		showError(svc.log, svc.renderMetrics(outDir), "renderMetrics")
+		errs.add(svc.log, svc.renderMetrics(outDir), "renderMetrics")
 	}
 	if svc.tags.Contains(tagLogger) {
-		showError(svc.log, svc.renderLogger(outDir), "renderLogger")
+		errs.add(svc.log, svc.renderLogger(outDir), "renderLogger")
 	}
 	if svc.tags.Contains(tagServerJsonRPC) {
-		showError(svc.log, svc.renderJsonRPC(outDir), "renderJsonRPC")
+		errs.add(svc.log, svc.renderJsonRPC(outDir), "renderJsonRPC")
 	}
 	if svc.tags.Contains(tagServerHTTP) {
-		showError(svc.log, svc.renderREST(outDir), "renderREST")
+		errs.add(svc.log, svc.renderREST(outDir), "renderREST")
 	}
-	return
+	return errs.err()
+}
+
+func (svc service) clientHTTPName() string {
+
+	if svc.tags.Contains(tagServerJsonRPC) {
+		return "Client" + svc.Name + "HTTP"
+	}
+	return "Client" + svc.Name
 }
 
 func (svc service) batchPath() string {
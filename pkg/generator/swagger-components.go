@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -105,7 +106,10 @@ func (doc *swagger) walkVariable(typeName, pkgPath string, varType types.Type, v
 	case types.TArray:
 
 		schema.Type = "array"
-		schema.Maximum = vType.ArrayLen
+		if vType.ArrayLen > 0 {
+			maximum := float64(vType.ArrayLen)
+			schema.Maximum = &maximum
+		}
 		schema.Nullable = vType.IsSlice
 		itemSchema := doc.walkVariable(typeName, pkgPath, vType.Next, nil)
 		schema.Items = &itemSchema
@@ -117,7 +121,16 @@ func (doc *swagger) walkVariable(typeName, pkgPath string, varType types.Type, v
 
 		for _, field := range vType.Fields {
 			if fieldName := jsonName(field); fieldName != "-" {
-				schema.Properties[fieldName] = doc.walkVariable(field.Name, pkgPath, field.Type, tags.ParseTags(field.Docs))
+
+				fieldTags := tags.ParseTags(field.Docs)
+				fieldSchema := doc.walkVariable(field.Name, pkgPath, field.Type, fieldTags)
+
+				c := varConstraints(fieldTags, field.Tags)
+				if c.required {
+					schema.Required = append(schema.Required, fieldName)
+				}
+				applyConstraints(&fieldSchema, c)
+				schema.Properties[fieldName] = fieldSchema
 			}
 		}
 
@@ -155,6 +168,66 @@ func (doc *swagger) walkVariable(typeName, pkgPath string, varType types.Type, v
 	return
 }
 
+// applyConstraints reflects validation constraints in schema of property.
+func applyConstraints(schema *swSchema, c constraints) {
+
+	if schema.Ref != "" {
+		return
+	}
+
+	lengthLimit := func(value string) *int {
+		if limit, err := strconv.Atoi(value); err == nil && limit >= 0 {
+			return &limit
+		}
+		return nil
+	}
+	numberLimit := func(value string) *float64 {
+		if limit, err := strconv.ParseFloat(value, 64); err == nil {
+			return &limit
+		}
+		return nil
+	}
+
+	switch schema.Type {
+
+	case "string":
+
+		if c.min != "" {
+			schema.MinLength = lengthLimit(c.min)
+		}
+		if c.max != "" {
+			schema.MaxLength = lengthLimit(c.max)
+		}
+		schema.Pattern = c.pattern
+		for _, item := range c.enum {
+			schema.Enum = append(schema.Enum, item)
+		}
+
+	case "array":
+
+		if c.min != "" {
+			schema.MinItems = lengthLimit(c.min)
+		}
+		if c.max != "" {
+			schema.MaxItems = lengthLimit(c.max)
+		}
+
+	case "number", "integer":
+
+		if c.min != "" {
+			schema.Minimum = numberLimit(c.min)
+		}
+		if c.max != "" {
+			schema.Maximum = numberLimit(c.max)
+		}
+		for _, item := range c.enum {
+			if value := numberLimit(item); value != nil {
+				schema.Enum = append(schema.Enum, *value)
+			}
+		}
+	}
+}
+
 func (doc *swagger) searchType(pkg, name string) (retType types.Type) {
 
 	if retType = doc.parseType(pkg, name); retType == nil {
@@ -225,6 +298,9 @@ func (doc *swagger) parseType(relPath, name string) (retType types.Type) {
 		}
 		return
 	})
+	if retType != nil {
+		doc.typeDirs[pkgPath] = struct{}{}
+	}
 	return
 }
 
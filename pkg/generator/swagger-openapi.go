@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (swagger-openapi.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+// upgradeDoc converts schemas of document to JSON Schema 2020-12 dialect of OpenAPI 3.1.
+func upgradeDoc(swaggerDoc *swObject) {
+
+	for name, schema := range swaggerDoc.Components.Schemas {
+		swaggerDoc.Components.Schemas[name] = upgradeSchema(schema)
+	}
+	for _, pathValue := range swaggerDoc.Paths {
+		for _, operation := range []*swOperation{pathValue.Get, pathValue.Post, pathValue.Patch, pathValue.Put, pathValue.Delete} {
+			if operation == nil {
+				continue
+			}
+			for i := range operation.Parameters {
+				operation.Parameters[i].Schema = upgradeSchema(operation.Parameters[i].Schema)
+			}
+			if operation.RequestBody != nil {
+				upgradeContent(operation.RequestBody.Content)
+			}
+			for _, response := range operation.Responses {
+				upgradeContent(response.Content)
+				for key, header := range response.Headers {
+					header.Schema = upgradeSchema(header.Schema)
+					response.Headers[key] = header
+				}
+			}
+		}
+	}
+}
+
+func upgradeContent(content swContent) {
+
+	for mime, media := range content {
+		media.Schema = upgradeSchema(media.Schema)
+		content[mime] = media
+	}
+}
+
+// upgradeSchema replaces 'nullable' by type 'null', 'example' by 'examples' and enum of single value by 'const'.
+func upgradeSchema(schema swSchema) swSchema {
+
+	if schema.Nullable {
+		schema.Nullable = false
+		switch {
+		case schema.Type != nil:
+			schema.Type = []interface{}{schema.Type, "null"}
+		case schema.Ref != "":
+			schema.OneOf = []swSchema{{Ref: schema.Ref}, {Type: "null"}}
+			schema.Ref = ""
+		case len(schema.OneOf) != 0:
+			schema.OneOf = append(schema.OneOf, swSchema{Type: "null"})
+		}
+	}
+	if schema.Example != nil {
+		schema.Examples = []interface{}{schema.Example}
+		schema.Example = nil
+	}
+	if len(schema.Enum) == 1 {
+		schema.Const = schema.Enum[0]
+		schema.Enum = nil
+	}
+	if schema.Items != nil {
+		items := upgradeSchema(*schema.Items)
+		schema.Items = &items
+	}
+	if additional, ok := schema.AdditionalProperties.(swSchema); ok {
+		schema.AdditionalProperties = upgradeSchema(additional)
+	}
+	for name, property := range schema.Properties {
+		schema.Properties[name] = upgradeSchema(property)
+	}
+	for i := range schema.OneOf {
+		schema.OneOf[i] = upgradeSchema(schema.OneOf[i])
+	}
+	return schema
+}
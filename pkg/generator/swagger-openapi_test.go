@@ -0,0 +1,104 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (swagger-openapi_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestOpenAPI checks JSON and YAML formats of document and schemas of OpenAPI 3.0 and 3.1.
+func TestOpenAPI(t *testing.T) {
+
+	tests := []struct {
+		version string
+		openAPI string
+		files   map[string]interface{}
+		kind    map[string]interface{}
+		jsonrpc map[string]interface{}
+	}{
+		{
+			version: "3.0",
+			openAPI: "3.0.0",
+			files:   map[string]interface{}{"type": "array", "nullable": true},
+			kind:    map[string]interface{}{"type": "string", "enum": []interface{}{"file"}},
+			jsonrpc: map[string]interface{}{"type": "string", "example": "2.0"},
+		},
+		{
+			version: "3.1",
+			openAPI: "3.1.0",
+			files:   map[string]interface{}{"type": []interface{}{"array", "null"}},
+			kind:    map[string]interface{}{"type": "string", "const": "file"},
+			jsonrpc: map[string]interface{}{"type": "string", "examples": []interface{}{"2.0"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+
+			tr, dir := testTransport(t, map[string]string{
+				"interfaces/interface.go": strings.Replace(swaggerServices, "// @tg version=1.0.0", "// @tg version=1.0.0\n// @tg openapi="+test.version, 1),
+				"types/types.go":          swaggerTypes,
+			})
+
+			yamlFile := filepath.Join(dir, "swagger.yaml")
+			if err := tr.RenderSwagger(yamlFile); err != nil {
+				t.Fatal(err)
+			}
+			data, err := ioutil.ReadFile(yamlFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), "openapi: "+test.openAPI+"\n") {
+				t.Errorf("unexpected YAML document:\n%.200s", data)
+			}
+
+			jsonFile := filepath.Join(dir, "swagger.json")
+			if err = tr.RenderSwagger(jsonFile); err != nil {
+				t.Fatal(err)
+			}
+			if data, err = ioutil.ReadFile(jsonFile); err != nil {
+				t.Fatal(err)
+			}
+			var doc struct {
+				OpenAPI    string `json:"openapi"`
+				Components struct {
+					Schemas map[string]struct {
+						Properties map[string]map[string]interface{} `json:"properties"`
+					} `json:"schemas"`
+				} `json:"components"`
+				Paths map[string]map[string]struct {
+					RequestBody struct {
+						Content map[string]struct {
+							Schema struct {
+								Properties map[string]map[string]interface{} `json:"properties"`
+							} `json:"schema"`
+						} `json:"content"`
+					} `json:"requestBody"`
+				} `json:"paths"`
+			}
+			if err = json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("document is not JSON: %s", err)
+			}
+			if doc.OpenAPI != test.openAPI {
+				t.Errorf("unexpected version %s", doc.OpenAPI)
+			}
+			files := doc.Components.Schemas["responseFilesList"].Properties["files"]
+			delete(files, "items")
+			if !reflect.DeepEqual(files, test.files) {
+				t.Errorf("unexpected schema of slice %v, want %v", files, test.files)
+			}
+			if kind := doc.Components.Schemas["File"].Properties["kind"]; !reflect.DeepEqual(kind, test.kind) {
+				t.Errorf("unexpected schema of enum %v, want %v", kind, test.kind)
+			}
+			jsonrpc := doc.Paths["/admin/stats"]["post"].RequestBody.Content["application/json"].Schema.Properties["jsonrpc"]
+			if !reflect.DeepEqual(jsonrpc, test.jsonrpc) {
+				t.Errorf("unexpected schema of example %v, want %v", jsonrpc, test.jsonrpc)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ type swOperation struct {
 	Responses   swResponses    `json:"responses,omitempty" yaml:"responses,omitempty"`
 	Deprecated  bool           `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 	Servers     []swServer     `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security    []swSecurity   `json:"security,omitempty" yaml:"security,omitempty"`
 	CodeSamples []swCodeSample `json:"x-code-samples,omitempty" yaml:"x-code-samples,omitempty"`
 }
 
@@ -86,21 +87,39 @@ type swSchemas map[string]swSchema
 type swProperties map[string]swSchema
 
 type swComponents struct {
-	Schemas swSchemas `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Schemas         swSchemas                   `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]swSecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+type swSecurity map[string][]string
+
+type swSecurityScheme struct {
+	Type   string `json:"type" yaml:"type"`
+	Scheme string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	In     string `json:"in,omitempty" yaml:"in,omitempty"`
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
 }
 
 type swSchema struct {
-	Ref         string       `json:"$ref,omitempty" yaml:"$ref,omitempty"`
-	Type        string       `json:"type,omitempty" yaml:"type,omitempty"`
-	Format      string       `json:"format,omitempty" yaml:"format,omitempty"`
-	Minimum     int          `json:"minimum,omitempty" yaml:"minimum,omitempty"`
-	Maximum     int          `json:"maximum,omitempty" yaml:"maximum,omitempty"`
-	Properties  swProperties `json:"properties,omitempty" yaml:"properties,omitempty"`
-	Items       *swSchema    `json:"items,omitempty" yaml:"items,omitempty"`
-	Enum        []string     `json:"enum,omitempty" yaml:"enum,omitempty"`
-	Nullable    bool         `json:"nullable,omitempty" yaml:"nullable,omitempty"`
-	Example     interface{}  `json:"example,omitempty" yaml:"example,omitempty"`
-	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Ref         string        `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type        interface{}   `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string        `json:"format,omitempty" yaml:"format,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength   *int          `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength   *int          `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems    *int          `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems    *int          `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Pattern     string        `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Properties  swProperties  `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required    []string      `json:"required,omitempty" yaml:"required,omitempty"`
+	Items       *swSchema     `json:"items,omitempty" yaml:"items,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Nullable    bool          `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Example     interface{}   `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Const       interface{}   `json:"const,omitempty" yaml:"const,omitempty"`
+	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
 
 	OneOf []swSchema `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
 
@@ -25,11 +25,25 @@ const (
 	contentMultipart = "multipart/form-data"
 )
 
+const (
+	OpenAPI30 = "3.0"
+	OpenAPI31 = "3.1"
+)
+
+var openAPIVersions = map[string]string{
+	OpenAPI30: "3.0.0",
+	OpenAPI31: "3.1.0",
+}
+
 type swagger struct {
 	*Transport
 
 	schemas    swSchemas
 	knownTypes map[string]int
+	typeDirs   map[string]struct{}
+
+	audience string
+	ifaces   map[string]struct{}
 }
 
 func newSwagger(tr *Transport) (doc *swagger) {
@@ -38,6 +52,8 @@ func newSwagger(tr *Transport) (doc *swagger) {
 		Transport:  tr,
 		schemas:    make(swSchemas),
 		knownTypes: make(map[string]int),
+		typeDirs:   make(map[string]struct{}),
+		ifaces:     make(map[string]struct{}),
 	}
 	return
 }
@@ -50,7 +66,7 @@ func (doc *swagger) render(outFilePath string) (err error) {
 
 	var swaggerDoc swObject
 
-	swaggerDoc.OpenAPI = "3.0.0"
+	swaggerDoc.OpenAPI = openAPIVersions[doc.openAPI]
 	swaggerDoc.Info.Title = doc.tags.Value("title")
 	swaggerDoc.Info.Version = doc.tags.Value("version")
 	swaggerDoc.Info.Description = doc.tags.Value("description")
@@ -71,6 +87,10 @@ func (doc *swagger) render(outFilePath string) (err error) {
 
 	for _, serviceName := range doc.serviceKeys() {
 
+		if _, found := doc.ifaces[serviceName]; len(doc.ifaces) != 0 && !found {
+			continue
+		}
+
 		service := doc.services[serviceName]
 
 		serviceTags := []string{service.Name}
@@ -80,6 +100,10 @@ func (doc *swagger) render(outFilePath string) (err error) {
 
 		for _, method := range service.methods {
 
+			if !doc.forAudience(service, method) {
+				continue
+			}
+
 			if method.tags.Contains(tagSwaggerTags) {
 				serviceTags = strings.Split(method.tags.Value(tagSwaggerTags), ",")
 			}
@@ -192,6 +216,7 @@ func (doc *swagger) render(outFilePath string) (err error) {
 					},
 				}
 
+				doc.fillSecurity(&swaggerDoc, postMethod, method, false)
 				swaggerDoc.Paths[method.jsonrpcPath()] = swPath{Post: postMethod}
 
 			} else if service.tags.Contains(tagServerHTTP) && method.tags.Contains(tagMethodHTTP) {
@@ -235,6 +260,7 @@ func (doc *swagger) render(outFilePath string) (err error) {
 
 				var methodTags tags.DocTags
 				doc.fillErrors(httpMethod.Responses, methodTags.Merge(service.tags).Merge(method.tags))
+				doc.fillSecurity(&swaggerDoc, httpMethod, method, true)
 
 				if httpMethod.RequestBody.Content == nil {
 					httpMethod.RequestBody = nil
@@ -248,9 +274,13 @@ func (doc *swagger) render(outFilePath string) (err error) {
 
 	swaggerDoc.Components.Schemas = doc.schemas
 
+	if doc.openAPI == OpenAPI31 {
+		upgradeDoc(&swaggerDoc)
+	}
+
 	var docData []byte
 
-	if strings.ToLower(filepath.Ext(outFilePath)) == ".json" {
+	if doc.json || strings.ToLower(filepath.Ext(outFilePath)) == ".json" {
 		if docData, err = json.MarshalIndent(swaggerDoc, " ", "    "); err != nil {
 			return
 		}
@@ -265,6 +295,31 @@ func (doc *swagger) render(outFilePath string) (err error) {
 	return ioutil.WriteFile(outFilePath, docData, 0600)
 }
 
+// forAudience reports whether method is documented for audience, methods without audience are documented for all.
+func (doc *swagger) forAudience(svc *service, method *method) bool {
+
+	audiences := swaggerAudiences(method.tags.Value(tagAudience, svc.tags.Value(tagAudience)))
+	if doc.audience == "" || len(audiences) == 0 {
+		return true
+	}
+	for _, audience := range audiences {
+		if audience == doc.audience {
+			return true
+		}
+	}
+	return false
+}
+
+func swaggerAudiences(value string) (audiences []string) {
+
+	for _, audience := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '|' }) {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	return
+}
+
 func (doc *swagger) fillErrors(responses swResponses, tags tags.DocTags) {
 
 	for key, value := range tags {
@@ -299,6 +354,50 @@ func (doc *swagger) fillErrors(responses swResponses, tags tags.DocTags) {
 	}
 }
 
+// fillSecurity documents schemes of method as alternatives, roles are listed by OpenAPI 3.1 only.
+func (doc *swagger) fillSecurity(swaggerDoc *swObject, operation *swOperation, method *method, withResponses bool) {
+
+	roles := method.authRoles()
+
+	for _, scheme := range method.authSchemes() {
+
+		name, securityScheme := securitySchemeOf(scheme)
+		if swaggerDoc.Components.SecuritySchemes == nil {
+			swaggerDoc.Components.SecuritySchemes = make(map[string]swSecurityScheme)
+		}
+		swaggerDoc.Components.SecuritySchemes[name] = securityScheme
+
+		scopes := make([]string, 0)
+		if doc.openAPI == OpenAPI31 {
+			scopes = append(scopes, roles...)
+		}
+		operation.Security = append(operation.Security, swSecurity{name: scopes})
+	}
+
+	if !withResponses || len(operation.Security) == 0 {
+		return
+	}
+	if _, found := operation.Responses["401"]; !found {
+		operation.Responses["401"] = swResponse{Description: codeToText(fasthttp.StatusUnauthorized)}
+	}
+	if _, found := operation.Responses["403"]; !found && len(roles) != 0 {
+		operation.Responses["403"] = swResponse{Description: codeToText(fasthttp.StatusForbidden)}
+	}
+}
+
+func securitySchemeOf(scheme string) (name string, securityScheme swSecurityScheme) {
+
+	tokens := strings.SplitN(scheme, ":", 2)
+	switch tokens[0] {
+	case authBearer:
+		return "bearerAuth", swSecurityScheme{Type: "http", Scheme: authBearer}
+	case authBasic:
+		return "basicAuth", swSecurityScheme{Type: "http", Scheme: authBasic}
+	default:
+		return "apiKey-" + tokens[len(tokens)-1], swSecurityScheme{Type: "apiKey", In: "header", Name: tokens[len(tokens)-1]}
+	}
+}
+
 func (doc *swagger) clearContent(content swContent) swContent {
 
 	for mime, media := range content {
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (swagger_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const swaggerTypes = `package types
+
+type File struct {
+	Name string ` + "`json:\"name\"`" + `
+	Size *int   ` + "`json:\"size,omitempty\"`" + `
+	// @tg enum=file
+	Kind string ` + "`json:\"kind\"`" + `
+}
+`
+
+const swaggerServices = `// @tg title=` + "`Files API`" + `
+// @tg version=1.0.0
+package interfaces
+
+import (
+	"context"
+
+	"gentest/types"
+)
+
+// @tg http-server
+// @tg http-prefix=api
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files
+	List(ctx context.Context) (files []types.File, err error)
+
+	// @tg http-method=DELETE
+	// @tg http-path=/files/{name}
+	// @tg swagger-audience=internal
+	Remove(ctx context.Context, name string) (err error)
+}
+
+// @tg jsonRPC-server
+// @tg swagger-audience=internal
+type Admin interface {
+	// @tg swagger-audience=public|internal
+	Stats(ctx context.Context) (count int, err error)
+	Reset(ctx context.Context) (err error)
+}
+`
+
+// swaggerPaths renders document in JSON and returns its paths
+func swaggerPaths(t *testing.T, render func(outFile string) error) (paths string) {
+
+	t.Helper()
+
+	outFile := filepath.Join(t.TempDir(), "swagger.json")
+	if err := render(outFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for path, methods := range doc.Paths {
+		for method := range methods {
+			keys = append(keys, method+" "+path)
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+// TestSwaggerFilters checks, that documents contain only methods of selected interfaces and audience.
+func TestSwaggerFilters(t *testing.T) {
+
+	tr, _ := testTransport(t, map[string]string{
+		"interfaces/interface.go": swaggerServices,
+		"types/types.go":          swaggerTypes,
+	})
+	tests := []struct {
+		name   string
+		render func(outFile string) error
+		paths  string
+	}{
+		{
+			name:   "all",
+			render: func(outFile string) error { return tr.RenderSwagger(outFile) },
+			paths:  "delete /api/files/{name}, get /api/files, post /admin/reset, post /admin/stats",
+		},
+		{
+			name:   "iface",
+			render: func(outFile string) error { return tr.RenderSwagger(outFile, "Files") },
+			paths:  "delete /api/files/{name}, get /api/files",
+		},
+		{
+			name:   "public",
+			render: func(outFile string) error { return tr.RenderSwaggerAudience(outFile, "public") },
+			paths:  "get /api/files, post /admin/stats",
+		},
+		{
+			name:   "internal of iface",
+			render: func(outFile string) error { return tr.RenderSwaggerAudience(outFile, "internal", "Admin") },
+			paths:  "post /admin/reset, post /admin/stats",
+		},
+	}
+	for _, test := range tests {
+		if paths := swaggerPaths(t, test.render); paths != test.paths {
+			t.Errorf("%s: unexpected paths %q, want %q", test.name, paths, test.paths)
+		}
+	}
+	if err := tr.RenderSwagger(filepath.Join(t.TempDir(), "swagger.yaml"), "Unknown"); err == nil {
+		t.Error("unknown interface is not reported")
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (tracing.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+
+	. "github.com/dave/jennifer/jen"
+
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+const (
+	TracerOpentracing   = "opentracing"
+	TracerJaeger        = "jaeger"
+	TracerZipkin        = "zipkin"
+	TracerOpenTelemetry = "otel"
+	TracerNone          = "none"
+)
+
+const tagTracer = "tracer"
+
+var tracers = utils.SliceStringToMap([]string{
+	TracerOpentracing, TracerJaeger, TracerZipkin, TracerOpenTelemetry, TracerNone,
+})
+
+// instrumentation scope of spans in OpenTelemetry mode
+const otelScope = "github.com/seniorGolang/tg"
+
+// tracing renders tracer specific statements of handlers and clients.
+// Jaeger and Zipkin are opentracing backends, so differ only by setup functions.
+type tracing string
+
+func (t tracing) isOTel() bool {
+	return t == TracerOpenTelemetry
+}
+
+func (t tracing) isNone() bool {
+	return t == TracerNone
+}
+
+func (t tracing) withJaeger() bool {
+	return t == TracerOpentracing || t == TracerJaeger
+}
+
+func (t tracing) withZipkin() bool {
+	return t == TracerOpentracing || t == TracerZipkin
+}
+
+func isKnownTracer(tracer string) (found bool) {
+	_, found = tracers[tracer]
+	return
+}
+
+// tracerError reports unknown tracer and disabled tracer for interfaces, which require it.
+func (tr Transport) tracerError() error {
+
+	if !isKnownTracer(string(tr.tracer)) {
+		return fmt.Errorf("unknown tracer '%s'", tr.tracer)
+	}
+	if tr.tracer.isNone() {
+		for _, serviceName := range tr.serviceKeys() {
+			if tr.services[serviceName].tags.Contains(tagTrace) {
+				return fmt.Errorf("tracer '%s' could not be used, interface %s is tagged '%s'", TracerNone, serviceName, tagTrace)
+			}
+		}
+	}
+	return nil
+}
+
+func (t tracing) spanType() Code {
+
+	if t.isNone() {
+		return Id("noopSpan")
+	}
+	if t.isOTel() {
+		return Qual(packageOTelTrace, "Span")
+	}
+	return Qual(packageOpentracing, "Span")
+}
+
+func (t tracing) setTag(span string, key, value Code) Code {
+
+	if t.isNone() {
+		return Null()
+	}
+	if t.isOTel() {
+		return Id(span).Dot("SetAttributes").Call(Id("spanAttribute").Call(key, value))
+	}
+	return Id(span).Dot("SetTag").Call(key, value)
+}
+
+func (t tracing) setError(span string, msg Code) Code {
+
+	if t.isNone() {
+		return Null()
+	}
+	if t.isOTel() {
+		return Id(span).Dot("SetStatus").Call(Qual(packageOTelCodes, "Error"), msg)
+	}
+	return Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id(span), True()).Line().
+		Id(span).Dot("SetTag").Call(Lit("msg"), msg)
+}
+
+func (t tracing) finish(span string) Code {
+
+	if t.isNone() {
+		return Null()
+	}
+	if t.isOTel() {
+		return Id(span).Dot("End").Call()
+	}
+	return Id(span).Dot("Finish").Call()
+}
+
+// deferSpan renders deferred injection and finishing of server span.
+// OpenTelemetry span could not be changed after end, so it ends after injection.
+func (t tracing) deferSpan(log Code, span string, args ...Code) Code {
+
+	inject := Defer().Id("injectSpan").Call(append([]Code{log, Id(span)}, args...)...)
+	if t.isNone() {
+		return inject
+	}
+	if t.isOTel() {
+		return Defer().Add(t.finish(span)).Line().Add(inject)
+	}
+	return inject.Line().Defer().Add(t.finish(span))
+}
+
+func (t tracing) deferFinish(span string) Code {
+
+	if t.isNone() {
+		return Null()
+	}
+	return Defer().Add(t.finish(span))
+}
+
+// extractSpan renders start of root span of receiver, OpenTelemetry spans are started by provider of receiver.
+func (t tracing) extractSpan(recv string, args ...Code) Code {
+
+	params := []Code{Id(recv).Dot("log")}
+	if t.isOTel() {
+		params = append(params, Id(recv).Dot("tracerProvider"))
+	}
+	return Id("extractSpan").Call(append(params, args...)...)
+}
+
+func (t tracing) startChild(parent string, opName Code) Code {
+
+	if t.isNone() {
+		return Id(parent)
+	}
+	if t.isOTel() {
+		return Id("startSpan").Call(Qual(packageOTelTrace, "ContextWithSpan").Call(Qual(packageContext, "Background").Call(), Id(parent)), opName)
+	}
+	return Qual(packageOpentracing, "StartSpan").Call(opName, Qual(packageOpentracing, "ChildOf").Call(Id(parent).Dot("Context").Call()))
+}
+
+func (t tracing) contextWithSpan(ctx Code, span string) Code {
+
+	if t.isNone() {
+		return ctx
+	}
+	if t.isOTel() {
+		return Qual(packageOTelTrace, "ContextWithSpan").Call(ctx, Id(span))
+	}
+	return Qual(packageOpentracing, "ContextWithSpan").Call(ctx, Id(span))
+}
+
+func (t tracing) spanFromContext(span string, ctx Code) Code {
+
+	if t.isNone() {
+		return Null()
+	}
+	if t.isOTel() {
+		return Id(span).Op(":=").Qual(packageOTelTrace, "SpanFromContext").Call(ctx)
+	}
+	return Id(span).Op(":=").Qual(packageOpentracing, "SpanFromContext").Call(ctx)
+}
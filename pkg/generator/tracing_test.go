@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (tracing_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tracerNoneServices = `// @tg tracer=none
+package interfaces
+
+import "context"
+
+// @tg jsonRPC-server log metrics
+type Store interface {
+	Get(ctx context.Context, key string) (value string, err error)
+}
+`
+
+// tracerNoneCheck checks, that generated code does not depend on tracing libraries
+const tracerNoneCheck = `package gentest
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDependencies(t *testing.T) {
+
+	output, err := exec.Command("go", "list", "-deps", "./transport", "./clients").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %s", err, output)
+	}
+	for _, dependency := range strings.Fields(string(output)) {
+		for _, tracer := range []string{"opentracing", "jaeger", "zipkin", "go.opentelemetry.io"} {
+			if strings.Contains(dependency, tracer) {
+				t.Errorf("transport without tracing depends on %s", dependency)
+			}
+		}
+	}
+}
+`
+
+// TestTracerNone checks, that transport without tracing has no dependencies of tracers and rejects traced interfaces.
+func TestTracerNone(t *testing.T) {
+
+	t.Run("trace", func(t *testing.T) {
+		tr, dir := testTransport(t, map[string]string{
+			"interfaces/interface.go": strings.Replace(tracerNoneServices, "log metrics", "log trace metrics", 1),
+		})
+		err := tr.RenderServer(filepath.Join(dir, "transport"))
+		if err == nil || !strings.Contains(err.Error(), "interface Store is tagged 'trace'") {
+			t.Errorf("unexpected error of traced interface: %v", err)
+		}
+	})
+
+	testGenerated(t, map[string]string{
+		"dependencies_test.go":    tracerNoneCheck,
+		"interfaces/interface.go": tracerNoneServices,
+	}, nil)
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-auth.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	authNone   = "none"
+	authBearer = "bearer"
+	authBasic  = "basic"
+	authAPIKey = "apiKey"
+)
+
+// authSchemes returns schemes of method annotation or interface annotation, 'none' disables authentication of method.
+func (m method) authSchemes() (schemes []string) {
+
+	for _, scheme := range strings.Split(m.tags.Value(tagAuth, m.svc.tags.Value(tagAuth)), "|") {
+		if scheme = strings.TrimSpace(scheme); scheme != "" && scheme != authNone {
+			schemes = append(schemes, scheme)
+		}
+	}
+	return
+}
+
+func (m method) authRoles() (roles []string) {
+
+	for _, role := range strings.Split(m.tags.Value(tagRoles, m.svc.tags.Value(tagRoles)), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return
+}
+
+func (svc *service) hasAuth() bool {
+
+	for _, method := range svc.methods {
+		if len(method.authSchemes()) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (tr Transport) hasAuth() bool {
+
+	for _, serviceName := range tr.serviceKeys() {
+		if tr.services[serviceName].hasAuth() {
+			return true
+		}
+	}
+	return false
+}
+
+// authArgs renders arguments of authentication, which are common for HTTP, jsonRPC and gRPC handlers.
+func (svc *service) authArgs(recv *Statement, method *method, request ...Code) []Code {
+
+	list := func(values []string) Code {
+		if len(values) == 0 {
+			return Nil()
+		}
+		var items []Code
+		for _, value := range values {
+			items = append(items, Lit(value))
+		}
+		return Index().String().Values(items...)
+	}
+	args := append([]Code{recv.Dot("authenticator")}, request...)
+	return append(args, Lit(svc.Name), Lit(method.lccName()), list(method.authSchemes()), list(method.authRoles()))
+}
+
+// authHTTP renders authentication of REST method, which responds 401 or 403 by itself.
+func (svc *service) authHTTP(method *method) Code {
+
+	if len(method.authSchemes()) == 0 {
+		return Null()
+	}
+	principal := Id("principal")
+	if method.tags.Value(tagHttpResponse, "") != "" {
+		// response handler takes principal from request
+		principal = Id("_")
+	}
+	return Line().List(principal, Id("authorized")).Op(":=").Id("authHTTP").Call(svc.authArgs(Id("http"), method, svc.engine.handlerArgs()...)...).Line().
+		If(Op("!").Id("authorized")).Block(
+		svc.tracer.setError("span", svc.engine.statusText(svc.engine.responseStatus())),
+		Return(),
+	)
+}
+
+// authContext renders putting of principal of REST method into context of method.
+func (m *method) authContext() Code {
+
+	if len(m.authSchemes()) == 0 {
+		return Null()
+	}
+	return Id("methodContext").Op("=").Id("withPrincipal").Call(Id("methodContext"), Id("principal"))
+}
+
+// authJsonRPC renders authentication of jsonRPC method, principal is put into context of method.
+func (svc *service) authJsonRPC(method *method) Code {
+
+	if len(method.authSchemes()) == 0 {
+		return Null()
+	}
+	return Line().If(List(Id("methodContext"), Err()).Op("=").Id("authJsonRPC").Call(append([]Code{Id("methodContext")}, svc.authArgs(Id("http"), method, svc.engine.request())...)...).Op(";").Err().Op("!=").Nil()).Block(
+		svc.tracer.setError("span", Err().Dot("Error").Call()),
+		Return(Id("makeErrorResponseJsonRPC").Call(Id("requestBase").Dot("ID"), Id("authErrorCode").Call(Err()), Err().Dot("Error").Call(), Nil())),
+	)
+}
+
+// authGRPC renders authentication of gRPC method by request metadata, principal is put into context of method.
+func (svc *service) authGRPC(method *method) Code {
+
+	if len(method.authSchemes()) == 0 {
+		return Null()
+	}
+	return Line().If(List(Id(_ctx_), Err()).Op("=").Id("authGRPC").Call(svc.authArgs(Id("grpc").Dot("http"), method, Id(_ctx_))...).Op(";").Err().Op("!=").Nil()).Block(
+		Return(Nil(), Err()),
+	)
+}
+
+func (tr Transport) renderAuth(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+
+		srcFile.Comment("userValuePrincipal is key of principal in user values of request, which are keyed by strings only")
+		srcFile.Const().Id("userValuePrincipal").Op("=").Lit("tg.principal")
+	}
+
+	srcFile.Line().Type().Id("ctxKeyPrincipal").Struct()
+
+	srcFile.Line().Var().Op("(").
+		Line().Id("errUnauthorized").Op("=").Qual(packageErrors, "New").Call(Lit("unauthorized")).
+		Line().Id("errForbidden").Op("=").Qual(packageErrors, "New").Call(Lit("forbidden")).
+		Line().Op(")")
+
+	srcFile.Line().Comment("Credentials of request, Token is bearer token or API key, Username and Password are set by basic scheme")
+	srcFile.Type().Id("Credentials").Struct(
+		Id("Scheme").String(),
+		Id("Token").String(),
+		Id("Username").String(),
+		Id("Password").String(),
+		Id("Service").String(),
+		Id("Method").String(),
+	)
+
+	srcFile.Line().Type().Id("Principal").Interface(
+		Comment("ID identifies principal, it is key of limiters annotated by limit-key=principal"),
+		Id("ID").Params().String(),
+		Id("HasRole").Params(Id("role").String()).Bool(),
+	)
+
+	srcFile.Line().Comment("Authenticator is called before methods annotated by auth, error means that request is unauthorized")
+	srcFile.Type().Id("Authenticator").Interface(
+		Id("Authenticate").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("credentials").Id("Credentials")).Params(Id("principal").Id("Principal"), Err().Error()),
+	)
+
+	srcFile.Line().Func().Id("WithAuthenticator").Params(Id("authenticator").Id("Authenticator")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("authenticator").Op("=").Id("authenticator"),
+		)),
+	)
+
+	srcFile.Line().Func().Id("PrincipalFromContext").Params(Id(_ctx_).Qual(packageContext, "Context")).Params(Id("principal").Id("Principal"), Id("ok").Bool()).BlockFunc(func(bg *Group) {
+		if !tr.isNetHTTP() {
+			bg.If(List(Id("requestCtx"), Id("isRequest")).Op(":=").Id(_ctx_).Op(".").Call(Op("*").Qual(packageFastHttp, "RequestCtx")).Op(";").Id("isRequest")).Block(
+				List(Id("principal"), Id("ok")).Op("=").Id("requestCtx").Dot("UserValue").Call(Id("userValuePrincipal")).Op(".").Call(Id("Principal")),
+				Return(),
+			)
+		}
+		bg.List(Id("principal"), Id("ok")).Op("=").Id(_ctx_).Dot("Value").Call(Id("ctxKeyPrincipal").Values()).Op(".").Call(Id("Principal"))
+		bg.Return()
+	})
+
+	srcFile.Line().Func().Id("withPrincipal").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("principal").Id("Principal")).Params(Qual(packageContext, "Context")).Block(
+		Return(Qual(packageContext, "WithValue").Call(Id(_ctx_), Id("ctxKeyPrincipal").Values(), Id("principal"))),
+	)
+
+	srcFile.Line().Add(tr.authenticateFunc())
+	srcFile.Line().Add(tr.requestHeaderFunc())
+	srcFile.Line().Add(tr.extractCredentialsFunc())
+	srcFile.Line().Add(tr.authHTTPFunc())
+	if tr.hasJsonRPC {
+		srcFile.Line().Add(tr.authJsonRPCFunc())
+		srcFile.Line().Add(tr.authErrorCodeFunc())
+	}
+	if tr.hasGRPC {
+		srcFile.ImportName(packageGRPCCodes, "codes")
+		srcFile.ImportName(packageGRPCStatus, "status")
+		srcFile.ImportName(packageGRPCMetadata, "metadata")
+		srcFile.Line().Add(tr.authGRPCFunc())
+	}
+	return srcFile.Save(path.Join(outDir, "auth.go"))
+}
+
+// authenticateFunc renders check of credentials and roles, request without authenticator is unauthorized.
+// Credentials are taken from headers of HTTP request or metadata of gRPC call.
+func (tr Transport) authenticateFunc() Code {
+
+	return Func().Id("authenticate").
+		Params(Id("authenticator").Id("Authenticator"), Id(_ctx_).Qual(packageContext, "Context"), Id("header").Func().Params(Id("key").String()).String(), List(Id("service"), Id("method")).String(), List(Id("schemes"), Id("roles")).Op("[]").String()).
+		Params(Id("principal").Id("Principal"), Err().Error()).Block(
+
+		Line().If(Id("authenticator").Op("==").Nil()).Block(
+			Return(Nil(), Id("errUnauthorized")),
+		),
+		List(Id("credentials"), Id("found")).Op(":=").Id("extractCredentials").Call(Id("header"), Id("schemes")),
+		If(Op("!").Id("found")).Block(
+			Return(Nil(), Id("errUnauthorized")),
+		),
+		Id("credentials").Dot("Service").Op("=").Id("service"),
+		Id("credentials").Dot("Method").Op("=").Id("method"),
+
+		Line().If(List(Id("principal"), Err()).Op("=").Id("authenticator").Dot("Authenticate").Call(Id(_ctx_), Id("credentials")).Op(";").Err().Op("!=").Nil().Op("||").Id("principal").Op("==").Nil()).Block(
+			Return(Nil(), Id("errUnauthorized")),
+		),
+		If(Len(Id("roles")).Op("==").Lit(0)).Block(
+			Return(),
+		),
+		For(List(Id("_"), Id("role")).Op(":=").Range().Id("roles")).Block(
+			If(Id("principal").Dot("HasRole").Call(Id("role"))).Block(
+				Return(),
+			),
+		),
+		Return(Nil(), Id("errForbidden")),
+	)
+}
+
+// extractCredentialsFunc renders search of credentials, first scheme found in request wins.
+func (tr Transport) extractCredentialsFunc() Code {
+
+	headerAuthorization := Qual(packageFastHttp, "HeaderAuthorization")
+	if tr.isNetHTTP() {
+		headerAuthorization = Lit("Authorization")
+	}
+	return Func().Id("extractCredentials").Params(Id("header").Func().Params(Id("key").String()).String(), Id("schemes").Op("[]").String()).Params(Id("credentials").Id("Credentials"), Id("found").Bool()).Block(
+
+		Line().Id("authorization").Op(":=").Id("header").Call(headerAuthorization),
+		For(List(Id("_"), Id("scheme")).Op(":=").Range().Id("schemes")).Block(
+
+			Line().Id("tokens").Op(":=").Qual(packageStrings, "SplitN").Call(Id("scheme"), Lit(":"), Lit(2)),
+			Switch(Id("tokens").Index(Lit(0))).Block(
+
+				Case(Lit(authBearer)).Block(
+					If(Len(Id("authorization")).Op(">").Lit(7).Op("&&").Qual(packageStrings, "EqualFold").Call(Id("authorization").Index(Op(":").Lit(7)), Lit("Bearer "))).Block(
+						Return(Id("Credentials").Values(Dict{Id("Scheme"): Lit(authBearer), Id("Token"): Id("authorization").Index(Lit(7).Op(":"))}), True()),
+					),
+				),
+				Case(Lit(authBasic)).Block(
+					If(Len(Id("authorization")).Op(">").Lit(6).Op("&&").Qual(packageStrings, "EqualFold").Call(Id("authorization").Index(Op(":").Lit(6)), Lit("Basic "))).Block(
+						List(Id("decoded"), Err()).Op(":=").Qual(packageBase64, "StdEncoding").Dot("DecodeString").Call(Id("authorization").Index(Lit(6).Op(":"))),
+						If(Err().Op("!=").Nil()).Block(
+							Continue(),
+						),
+						If(Id("pair").Op(":=").Qual(packageStrings, "SplitN").Call(String().Call(Id("decoded")), Lit(":"), Lit(2)).Op(";").Len(Id("pair")).Op("==").Lit(2)).Block(
+							Return(Id("Credentials").Values(Dict{Id("Scheme"): Lit(authBasic), Id("Username"): Id("pair").Index(Lit(0)), Id("Password"): Id("pair").Index(Lit(1))}), True()),
+						),
+					),
+				),
+				Case(Lit(authAPIKey)).Block(
+					If(Id("key").Op(":=").Id("header").Call(Id("tokens").Index(Lit(1))).Op(";").Id("key").Op("!=").Lit("")).Block(
+						Return(Id("Credentials").Values(Dict{Id("Scheme"): Lit(authAPIKey), Id("Token"): Id("key")}), True()),
+					),
+				),
+			),
+		),
+		Return(),
+	)
+}
+
+func (tr Transport) authHTTPFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Id("authHTTP").
+			Params(append(append([]Code{Id("authenticator").Id("Authenticator")}, tr.engine.handlerParams()...), List(Id("service"), Id("method")).String(), List(Id("schemes"), Id("roles")).Op("[]").String())...).
+			Params(Id("principal").Id("Principal"), Id("ok").Bool()).Block(
+
+			Line().List(Id("principal"), Err()).Op(":=").Id("authenticate").Call(Id("authenticator"), Id("r").Dot("Context").Call(), Id("requestHeader").Call(Id("r")), Id("service"), Id("method"), Id("schemes"), Id("roles")),
+			If(Qual(packageErrors, "Is").Call(Err(), Id("errForbidden"))).Block(
+				Id("replyError").Call(Id("w"), Qual(packageHttp, "StatusText").Call(Qual(packageHttp, "StatusForbidden")), Qual(packageHttp, "StatusForbidden")),
+				Return(Nil(), False()),
+			),
+			If(Err().Op("!=").Nil()).Block(
+				Id("replyError").Call(Id("w"), Qual(packageHttp, "StatusText").Call(Qual(packageHttp, "StatusUnauthorized")), Qual(packageHttp, "StatusUnauthorized")),
+				Return(Nil(), False()),
+			),
+			Comment("request is changed in place, so response handler finds principal in context of request"),
+			Op("*").Id("r").Op("=").Op("*").Id("r").Dot("WithContext").Call(Id("withPrincipal").Call(Id("r").Dot("Context").Call(), Id("principal"))),
+			Return(Id("principal"), True()),
+		)
+	}
+
+	return Func().Id("authHTTP").
+		Params(Id("authenticator").Id("Authenticator"), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), List(Id("service"), Id("method")).String(), List(Id("schemes"), Id("roles")).Op("[]").String()).
+		Params(Id("principal").Id("Principal"), Id("ok").Bool()).Block(
+
+		Line().List(Id("principal"), Err()).Op(":=").Id("authenticate").Call(Id("authenticator"), Id(_ctx_), Id("requestHeader").Call(Id(_ctx_)), Id("service"), Id("method"), Id("schemes"), Id("roles")),
+		If(Qual(packageErrors, "Is").Call(Err(), Id("errForbidden"))).Block(
+			Id(_ctx_).Dot("Error").Call(Qual(packageFastHttp, "StatusMessage").Call(Qual(packageFastHttp, "StatusForbidden")), Qual(packageFastHttp, "StatusForbidden")),
+			Return(Nil(), False()),
+		),
+		If(Err().Op("!=").Nil()).Block(
+			Id(_ctx_).Dot("Error").Call(Qual(packageFastHttp, "StatusMessage").Call(Qual(packageFastHttp, "StatusUnauthorized")), Qual(packageFastHttp, "StatusUnauthorized")),
+			Return(Nil(), False()),
+		),
+		Id(_ctx_).Dot("SetUserValue").Call(Id("userValuePrincipal"), Id("principal")),
+		Return(Id("principal"), True()),
+	)
+}
+
+// authJsonRPCFunc renders authentication of jsonRPC call, calls of batch share request, so principal is kept in context of call.
+func (tr Transport) authJsonRPCFunc() Code {
+
+	requestContext := tr.engine.request()
+	if tr.isNetHTTP() {
+		requestContext = Id("r").Dot("Context").Call()
+	}
+	return Func().Id("authJsonRPC").
+		Params(Id("methodContext").Qual(packageContext, "Context"), Id("authenticator").Id("Authenticator"), tr.engine.requestParam(), List(Id("service"), Id("method")).String(), List(Id("schemes"), Id("roles")).Op("[]").String()).
+		Params(Qual(packageContext, "Context"), Error()).Block(
+
+		Line().List(Id("principal"), Err()).Op(":=").Id("authenticate").Call(Id("authenticator"), requestContext, Id("requestHeader").Call(tr.engine.request()), Id("service"), Id("method"), Id("schemes"), Id("roles")),
+		If(Err().Op("!=").Nil()).Block(
+			Return(Id("methodContext"), Err()),
+		),
+		Return(Id("withPrincipal").Call(Id("methodContext"), Id("principal")), Nil()),
+	)
+}
+
+func (tr Transport) authErrorCodeFunc() Code {
+
+	return Func().Id("authErrorCode").Params(Err().Error()).Int().Block(
+		If(Qual(packageErrors, "Is").Call(Err(), Id("errForbidden"))).Block(
+			Return(Id("forbiddenError")),
+		),
+		Return(Id("unauthorizedError")),
+	)
+}
+
+func (tr Transport) requestHeaderFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Id("requestHeader").Params(tr.engine.requestParam()).Params(Func().Params(String()).String()).Block(
+			Return(Id("r").Dot("Header").Dot("Get")),
+		)
+	}
+	return Func().Id("requestHeader").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Params(Func().Params(String()).String()).Block(
+		Return(Func().Params(Id("key").String()).String().Block(
+			Return(String().Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Id("key")))),
+		)),
+	)
+}
+
+// authGRPCFunc renders authentication of gRPC call by metadata, keys of metadata are lower case names of headers.
+func (tr Transport) authGRPCFunc() Code {
+
+	return Func().Id("authGRPC").
+		Params(Id("authenticator").Id("Authenticator"), Id(_ctx_).Qual(packageContext, "Context"), List(Id("service"), Id("method")).String(), List(Id("schemes"), Id("roles")).Op("[]").String()).
+		Params(Qual(packageContext, "Context"), Error()).Block(
+
+		Line().List(Id("md"), Id("_")).Op(":=").Qual(packageGRPCMetadata, "FromIncomingContext").Call(Id(_ctx_)),
+		Id("header").Op(":=").Func().Params(Id("key").String()).String().Block(
+			If(Id("values").Op(":=").Id("md").Dot("Get").Call(Id("key")).Op(";").Len(Id("values")).Op("!=").Lit(0)).Block(
+				Return(Id("values").Index(Lit(0))),
+			),
+			Return(Lit("")),
+		),
+		List(Id("principal"), Err()).Op(":=").Id("authenticate").Call(Id("authenticator"), Id(_ctx_), Id("header"), Id("service"), Id("method"), Id("schemes"), Id("roles")),
+		If(Qual(packageErrors, "Is").Call(Err(), Id("errForbidden"))).Block(
+			Return(Id(_ctx_), Qual(packageGRPCStatus, "Error").Call(Qual(packageGRPCCodes, "PermissionDenied"), Err().Dot("Error").Call())),
+		),
+		If(Err().Op("!=").Nil()).Block(
+			Return(Id(_ctx_), Qual(packageGRPCStatus, "Error").Call(Qual(packageGRPCCodes, "Unauthenticated"), Err().Dot("Error").Call())),
+		),
+		Return(Id("withPrincipal").Call(Id(_ctx_), Id("principal")), Nil()),
+	)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-auth_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const authServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+// @tg auth=bearer|apiKey:X-Api-Key
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files
+	// @tg roles=admin
+	Get(ctx context.Context) (owner string, err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/public
+	// @tg auth=none
+	Public(ctx context.Context) (owner string, err error)
+}
+
+// @tg jsonRPC-server
+// @tg auth=basic
+type Admin interface {
+	// @tg roles=admin
+	Reset(ctx context.Context) (owner string, err error)
+}
+`
+
+const authCheck = `package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type user string
+
+func (u user) ID() string {
+	return string(u)
+}
+
+func (u user) HasRole(role string) bool {
+	return string(u) == role
+}
+
+type authenticator struct{}
+
+func (authenticator) Authenticate(ctx context.Context, credentials transport.Credentials) (principal transport.Principal, err error) {
+	switch credentials.Scheme {
+	case "bearer", "apiKey":
+		if credentials.Token != "wrong" {
+			return user(credentials.Token), nil
+		}
+	case "basic":
+		if credentials.Password == "secret" {
+			return user(credentials.Username), nil
+		}
+	}
+	return nil, errors.New("wrong credentials")
+}
+
+func owner(ctx context.Context) (string, error) {
+	principal, found := transport.PrincipalFromContext(ctx)
+	if !found {
+		return "", nil
+	}
+	return principal.ID(), nil
+}
+
+type files struct{}
+
+func (files) Get(ctx context.Context) (string, error) {
+	return owner(ctx)
+}
+
+func (files) Public(ctx context.Context) (string, error) {
+	return owner(ctx)
+}
+
+type admin struct{}
+
+func (admin) Reset(ctx context.Context) (string, error) {
+	return owner(ctx)
+}
+
+func request(t *testing.T, method, url, body string, header ...string) (status int, response string) {
+
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestAuth(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	srv := transport.New(log,
+		transport.WithAuthenticator(authenticator{}),
+		transport.Files(transport.NewFiles(log, files{})),
+		transport.Admin(transport.NewAdmin(log, admin{})),
+	)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+	url := "http://" + address
+
+	for _, test := range []struct {
+		header []string
+		status int
+		body   string
+	}{
+		{nil, http.StatusUnauthorized, ""},
+		{[]string{"Authorization", "Bearer wrong"}, http.StatusUnauthorized, ""},
+		{[]string{"Authorization", "Basic YWRtaW46c2VjcmV0"}, http.StatusUnauthorized, ""},
+		{[]string{"Authorization", "Bearer guest"}, http.StatusForbidden, ""},
+		{[]string{"Authorization", "Bearer admin"}, http.StatusOK, ` + "`" + `{"owner":"admin"}` + "`" + `},
+		{[]string{"X-Api-Key", "admin"}, http.StatusOK, ` + "`" + `{"owner":"admin"}` + "`" + `},
+	} {
+		status, body := request(t, http.MethodGet, url+"/files", "", test.header...)
+		if status != test.status || (test.body != "" && strings.TrimSpace(body) != test.body) {
+			t.Errorf("%v: unexpected response %d %s", test.header, status, body)
+		}
+	}
+	if status, body := request(t, http.MethodGet, url+"/public", ""); status != http.StatusOK || strings.TrimSpace(body) != ` + "`" + `{"owner":""}` + "`" + ` {
+		t.Errorf("unexpected response of method without auth %d %s", status, body)
+	}
+
+	reset := ` + "`" + `[{"jsonrpc":"2.0","id":1,"method":"admin.reset"}]` + "`" + `
+	for _, test := range []struct {
+		header []string
+		code   int
+		owner  string
+	}{
+		{nil, -32002, ""},
+		{[]string{"Authorization", "Basic YWRtaW46d3Jvbmc="}, -32002, ""},
+		{[]string{"Authorization", "Basic Z3Vlc3Q6c2VjcmV0"}, -32003, ""},
+		{[]string{"Authorization", "Basic YWRtaW46c2VjcmV0"}, 0, "admin"},
+	} {
+		status, body := request(t, http.MethodPost, url+"/", reset, test.header...)
+		var responses []struct {
+			Result *struct {
+				Owner string ` + "`json:\"owner\"`" + `
+			} ` + "`json:\"result\"`" + `
+			Error *struct {
+				Code int ` + "`json:\"code\"`" + `
+			} ` + "`json:\"error\"`" + `
+		}
+		if err := json.Unmarshal([]byte(body), &responses); err != nil || status != http.StatusOK || len(responses) != 1 {
+			t.Fatalf("%v: unexpected response %d %s", test.header, status, body)
+		}
+		switch response := responses[0]; {
+		case test.code != 0 && (response.Error == nil || response.Error.Code != test.code):
+			t.Errorf("%v: unexpected response %s", test.header, body)
+		case test.code == 0 && (response.Result == nil || response.Result.Owner != test.owner):
+			t.Errorf("%v: unexpected response %s", test.header, body)
+		}
+	}
+}
+`
+
+// TestAuth checks, that server rejects requests without credentials or roles and passes principal to methods.
+func TestAuth(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"auth_test.go":            authCheck,
+		"interfaces/interface.go": authServices,
+	}, nil, WithTracer(TracerNone))
+}
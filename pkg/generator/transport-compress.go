@@ -0,0 +1,425 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-compress.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	compressMinSize   = 1024
+	encodingBrotli    = "br"
+	encodingGzip      = "gzip"
+	encodingDeflate   = "deflate"
+	acceptEncodingAll = "br, gzip, deflate"
+)
+
+// compress returns false for methods annotated by compress=false, annotation of interface is used as default.
+func (m method) compress() bool {
+	return m.tags.Value(tagCompress, m.svc.tags.Value(tagCompress, "true")) != "false"
+}
+
+// compressRouteHandler wraps handler of route, so response is compressed by encoding accepted by client.
+func (svc *service) compressRouteHandler(method *method, handler Code) Code {
+
+	if method != nil && !method.compress() {
+		return handler
+	}
+	return Id("http").Dot("compress").Call(handler)
+}
+
+func (svc *service) compressFunc() Code {
+	return compressFunc(svc.engine, Id("http").Op("*").Id("http"+svc.Name), Id("http").Dot("compressMinSize"))
+}
+
+func compressFunc(engine httpEngine, recv *Statement, minSize Code) Code {
+
+	return Func().Params(recv).Id("compress").Params(Id("handler").Add(engine.handlerType())).Params(engine.handlerType()).Block(
+		Return(Func().Params(engine.handlerParams()...).Block(
+			Id("handler").Call(engine.handlerArgs()...),
+			Id("compressResponse").Call(append(engine.handlerArgs(), minSize)...),
+		)),
+	)
+}
+
+// renderCompress renders negotiation of content encoding, response is compressed by the most preferred
+// of br, gzip and deflate, compressed request body is decoded before hooks and handlers.
+func (tr Transport) renderCompress(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageBrotli, "brotli")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Const().Id("defaultCompressMinSize").Op("=").Lit(compressMinSize)
+
+	srcFile.Line().Var().Id("compressEncodings").Op("=").Index().String().Values(Lit(encodingBrotli), Lit(encodingGzip), Lit(encodingDeflate))
+
+	srcFile.Line().Comment("CompressMinSize sets size of response body, smaller responses are not compressed, negative size disables compression")
+	srcFile.Func().Id("CompressMinSize").Params(Id("size").Int()).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("compressMinSize").Op("=").Id("size"),
+		)),
+	)
+
+	if tr.hasJsonRPC {
+		srcFile.Line().Add(compressFunc(tr.engine, Id("srv").Op("*").Id("Server"), Id("srv").Dot("compressMinSize")))
+	}
+
+	srcFile.Line().Add(tr.compressResponseFunc())
+	srcFile.Line().Add(tr.acceptedEncodingFunc())
+	srcFile.Line().Add(tr.decompressRequestFunc())
+
+	return srcFile.Save(path.Join(outDir, "compress.go"))
+}
+
+// compressResponseFunc renders compression of response body, streams, encoded bodies and bodies,
+// which become larger after compression, are sent as is.
+func (tr Transport) compressResponseFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Id("compressResponse").Params(Id("w").Qual(packageHttp, "ResponseWriter"), Id("r").Op("*").Qual(packageHttp, "Request"), Id("minSize").Int()).Block(
+
+			Line().List(Id("response"), Id("ok")).Op(":=").Id("w").Op(".").Call(Op("*").Id("responseWriter")),
+			If(Id("minSize").Op("<").Lit(0).Op("||").Op("!").Id("ok").Op("||").Id("response").Dot("streamed").Op("||").Id("w").Dot("Header").Call().Dot("Get").Call(Lit("Content-Encoding")).Op("!=").Lit("")).Block(
+				Return(),
+			),
+			Id("w").Dot("Header").Call().Dot("Add").Call(Lit("Vary"), Lit("Accept-Encoding")),
+
+			Line().Id("body").Op(":=").Id("response").Dot("body").Dot("Bytes").Call(),
+			If(Len(Id("body")).Op("<").Id("minSize").Op("||").Len(Id("body")).Op("==").Lit(0)).Block(
+				Return(),
+			),
+			Var().Id("compressed").Qual(packageBytes, "Buffer"),
+			Var().Id("writer").Qual(packageIO, "WriteCloser"),
+			Id("encoding").Op(":=").Id("acceptedEncoding").Call(Id("r").Dot("Header").Dot("Get").Call(Lit("Accept-Encoding"))),
+			Switch(Id("encoding")).Block(
+				Case(Lit(encodingBrotli)).Block(
+					Id("writer").Op("=").Qual(packageBrotli, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+				Case(Lit(encodingGzip)).Block(
+					Id("writer").Op("=").Qual(packageGzip, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+				Case(Lit(encodingDeflate)).Block(
+					Id("writer").Op("=").Qual(packageZlib, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+				Default().Block(
+					Return(),
+				),
+			),
+			If(List(Id("_"), Err()).Op(":=").Id("writer").Dot("Write").Call(Id("body")).Op(";").Err().Op("!=").Nil().Op("||").Id("writer").Dot("Close").Call().Op("!=").Nil().Op("||").Id("compressed").Dot("Len").Call().Op(">=").Len(Id("body"))).Block(
+				Return(),
+			),
+			Id("response").Dot("body").Op("=").Id("compressed"),
+			Id("w").Dot("Header").Call().Dot("Del").Call(Lit("Content-Length")),
+			Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Content-Encoding"), Id("encoding")),
+		)
+	}
+	return Func().Id("compressResponse").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("minSize").Int()).Block(
+
+		Line().If(Id("minSize").Op("<").Lit(0).Op("||").Id(_ctx_).Dot("Response").Dot("IsBodyStream").Call().Op("||").Len(Id(_ctx_).Dot("Response").Dot("Header").Dot("Peek").Call(Qual(packageFastHttp, "HeaderContentEncoding"))).Op("!=").Lit(0)).Block(
+			Return(),
+		),
+		Id(_ctx_).Dot("Response").Dot("Header").Dot("Add").Call(Qual(packageFastHttp, "HeaderVary"), Qual(packageFastHttp, "HeaderAcceptEncoding")),
+
+		Line().Id("body").Op(":=").Id(_ctx_).Dot("Response").Dot("Body").Call(),
+		If(Len(Id("body")).Op("<").Id("minSize").Op("||").Len(Id("body")).Op("==").Lit(0)).Block(
+			Return(),
+		),
+		Var().Id("compressed").Op("[]").Byte(),
+		Id("encoding").Op(":=").Id("acceptedEncoding").Call(String().Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Qual(packageFastHttp, "HeaderAcceptEncoding")))),
+		Switch(Id("encoding")).Block(
+			Case(Lit(encodingBrotli)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendBrotliBytes").Call(Nil(), Id("body")),
+			),
+			Case(Lit(encodingGzip)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendGzipBytes").Call(Nil(), Id("body")),
+			),
+			Case(Lit(encodingDeflate)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendDeflateBytes").Call(Nil(), Id("body")),
+			),
+			Default().Block(
+				Return(),
+			),
+		),
+		If(Len(Id("compressed")).Op(">=").Len(Id("body"))).Block(
+			Return(),
+		),
+		Id(_ctx_).Dot("Response").Dot("SetBody").Call(Id("compressed")),
+		Id(_ctx_).Dot("Response").Dot("Header").Dot("Set").Call(Qual(packageFastHttp, "HeaderContentEncoding"), Id("encoding")),
+	)
+}
+
+// acceptedEncodingFunc renders choice of encoding with the highest quality in Accept-Encoding, '*' is applied
+// to encodings, which are not listed, encodings with equal quality are preferred in order of compressEncodings.
+func (tr Transport) acceptedEncodingFunc() Code {
+
+	return Func().Id("acceptedEncoding").Params(Id("header").String()).Params(Id("encoding").String()).Block(
+
+		Line().Id("weights").Op(":=").Make(Map(String()).Float64()),
+		For(List(Id("_"), Id("item")).Op(":=").Range().Qual(packageStrings, "Split").Call(Id("header"), Lit(","))).Block(
+			Id("tokens").Op(":=").Qual(packageStrings, "Split").Call(Id("item"), Lit(";")),
+			Id("weight").Op(":=").Lit(1.0),
+			For(List(Id("_"), Id("param")).Op(":=").Range().Id("tokens").Index(Lit(1).Op(":"))).Block(
+				If(Id("param").Op("=").Qual(packageStrings, "TrimSpace").Call(Id("param")).Op(";").Qual(packageStrings, "HasPrefix").Call(Id("param"), Lit("q="))).Block(
+					List(Id("weight"), Id("_")).Op("=").Qual(packageStrconv, "ParseFloat").Call(Id("param").Index(Lit(2).Op(":")), Lit(64)),
+				),
+			),
+			Id("weights").Index(Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(Id("tokens").Index(Lit(0))))).Op("=").Id("weight"),
+		),
+		Var().Id("best").Float64(),
+		For(List(Id("_"), Id("name")).Op(":=").Range().Id("compressEncodings")).Block(
+			List(Id("weight"), Id("found")).Op(":=").Id("weights").Index(Id("name")),
+			If(Op("!").Id("found")).Block(
+				Id("weight").Op("=").Id("weights").Index(Lit("*")),
+			),
+			If(Id("weight").Op(">").Id("best")).Block(
+				List(Id("encoding"), Id("best")).Op("=").List(Id("name"), Id("weight")),
+			),
+		),
+		Return(),
+	)
+}
+
+// decompressRequestFunc renders decoding of request body, decoded body is limited by maxSize,
+// so small compressed body could not exhaust memory of server.
+func (tr Transport) decompressRequestFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Id("decompressRequest").Params(Id("w").Qual(packageHttp, "ResponseWriter"), Id("r").Op("*").Qual(packageHttp, "Request"), Id("maxSize").Int()).Params(Id("statusCode").Int(), Err().Error()).Block(
+
+			Line().Id("encoding").Op(":=").Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(Id("r").Dot("Header").Dot("Get").Call(Lit("Content-Encoding")))),
+			If(Id("encoding").Op("==").Lit("").Op("||").Id("encoding").Op("==").Lit("identity")).Block(
+				Return(),
+			),
+			Var().Id("reader").Qual(packageIO, "Reader"),
+			Switch(Id("encoding")).Block(
+				Case(Lit(encodingBrotli)).Block(
+					Id("reader").Op("=").Qual(packageBrotli, "NewReader").Call(Id("r").Dot("Body")),
+				),
+				Case(Lit(encodingGzip)).Block(
+					If(List(Id("reader"), Err()).Op("=").Qual(packageGzip, "NewReader").Call(Id("r").Dot("Body")).Op(";").Err().Op("!=").Nil()).Block(
+						Return(Id("bodyErrorStatus").Call(Err()), Err()),
+					),
+				),
+				Case(Lit(encodingDeflate)).Block(
+					If(List(Id("reader"), Err()).Op("=").Qual(packageZlib, "NewReader").Call(Id("r").Dot("Body")).Op(";").Err().Op("!=").Nil()).Block(
+						Return(Id("bodyErrorStatus").Call(Err()), Err()),
+					),
+				),
+				Default().Block(
+					Return(Qual(packageHttp, "StatusUnsupportedMediaType"), Qual(packageFmt, "Errorf").Call(Lit("unsupported content encoding '%s'"), Id("encoding"))),
+				),
+			),
+			Id("r").Dot("Body").Op("=").Qual(packageHttp, "MaxBytesReader").Call(Id("w"), Qual(packageIOUtil, "NopCloser").Call(Id("reader")), Int64().Call(Id("maxSize"))),
+			Id("r").Dot("Header").Dot("Del").Call(Lit("Content-Encoding")),
+			Id("r").Dot("ContentLength").Op("=").Lit(-1),
+			Return(),
+		)
+	}
+	return Func().Id("decompressRequest").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("maxSize").Int()).Params(Id("statusCode").Int(), Err().Error()).Block(
+
+		Line().Id("encoding").Op(":=").Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(String().Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Qual(packageFastHttp, "HeaderContentEncoding"))))),
+		If(Id("encoding").Op("==").Lit("").Op("||").Id("encoding").Op("==").Lit("identity")).Block(
+			Return(),
+		),
+		Var().Id("reader").Qual(packageIO, "Reader"),
+		Id("body").Op(":=").Qual(packageBytes, "NewReader").Call(Id(_ctx_).Dot("Request").Dot("Body").Call()),
+		Switch(Id("encoding")).Block(
+			Case(Lit(encodingBrotli)).Block(
+				Id("reader").Op("=").Qual(packageBrotli, "NewReader").Call(Id("body")),
+			),
+			Case(Lit(encodingGzip)).Block(
+				If(List(Id("reader"), Err()).Op("=").Qual(packageGzip, "NewReader").Call(Id("body")).Op(";").Err().Op("!=").Nil()).Block(
+					Return(Qual(packageFastHttp, "StatusBadRequest"), Err()),
+				),
+			),
+			Case(Lit(encodingDeflate)).Block(
+				If(List(Id("reader"), Err()).Op("=").Qual(packageZlib, "NewReader").Call(Id("body")).Op(";").Err().Op("!=").Nil()).Block(
+					Return(Qual(packageFastHttp, "StatusBadRequest"), Err()),
+				),
+			),
+			Default().Block(
+				Return(Qual(packageFastHttp, "StatusUnsupportedMediaType"), Qual(packageFmt, "Errorf").Call(Lit("unsupported content encoding '%s'"), Id("encoding"))),
+			),
+		),
+		Var().Id("decoded").Op("[]").Byte(),
+		If(List(Id("decoded"), Err()).Op("=").Qual(packageIOUtil, "ReadAll").Call(Qual(packageIO, "LimitReader").Call(Id("reader"), Int64().Call(Id("maxSize")).Op("+").Lit(1))).Op(";").Err().Op("!=").Nil()).Block(
+			Return(Qual(packageFastHttp, "StatusBadRequest"), Err()),
+		),
+		If(Len(Id("decoded")).Op(">").Id("maxSize")).Block(
+			Return(Qual(packageFastHttp, "StatusRequestEntityTooLarge"), Qual(packageErrors, "New").Call(Lit("decoded request body is too large"))),
+		),
+		Id(_ctx_).Dot("Request").Dot("Header").Dot("Del").Call(Qual(packageFastHttp, "HeaderContentEncoding")),
+		Id(_ctx_).Dot("Request").Dot("SetBody").Call(Id("decoded")),
+		Return(),
+	)
+}
+
+// renderClientCompress renders compression of request bodies by option Compress and decoding of responses,
+// clients accept all encodings of server.
+func (tr Transport) renderClientCompress(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Const().Id("compressMinSize").Op("=").Lit(compressMinSize)
+
+	srcFile.Line().Comment("Compress sets encoding of request bodies: br, gzip or deflate, smaller bodies are sent as is")
+	srcFile.Func().Id("Compress").Params(Id("encoding").String()).Params(Id("Option")).Block(
+		Return(Func().Params(Id("cli").Op("*").Id("clientOptions"))).Block(
+			Id("cli").Dot("compress").Op("=").Id("encoding"),
+		),
+	)
+
+	if tr.isNetHTTP() {
+		srcFile.Line().Add(tr.compressRequestNetHTTPFunc())
+		srcFile.Line().Add(tr.decompressResponseNetHTTPFunc())
+		return srcFile.Save(path.Join(outDir, "compress.go"))
+	}
+
+	srcFile.Line().Func().Params(Id("cli").Op("*").Id("clientOptions")).Id("compressRequest").Params(Id("request").Op("*").Qual(packageFastHttp, "Request")).Block(
+
+		Line().Id("request").Dot("Header").Dot("Set").Call(Qual(packageFastHttp, "HeaderAcceptEncoding"), Lit(acceptEncodingAll)),
+		If(Len(Id("request").Dot("Body").Call()).Op("<").Id("compressMinSize")).Block(
+			Return(),
+		),
+		Var().Id("compressed").Op("[]").Byte(),
+		Switch(Id("cli").Dot("compress")).Block(
+			Case(Lit(encodingBrotli)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendBrotliBytes").Call(Nil(), Id("request").Dot("Body").Call()),
+			),
+			Case(Lit(encodingGzip)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendGzipBytes").Call(Nil(), Id("request").Dot("Body").Call()),
+			),
+			Case(Lit(encodingDeflate)).Block(
+				Id("compressed").Op("=").Qual(packageFastHttp, "AppendDeflateBytes").Call(Nil(), Id("request").Dot("Body").Call()),
+			),
+			Default().Block(
+				Return(),
+			),
+		),
+		Id("request").Dot("SetBody").Call(Id("compressed")),
+		Id("request").Dot("Header").Dot("Set").Call(Qual(packageFastHttp, "HeaderContentEncoding"), Id("cli").Dot("compress")),
+	)
+
+	srcFile.Line().Func().Id("decompressResponse").Params(Id("response").Op("*").Qual(packageFastHttp, "Response")).Params(Err().Error()).Block(
+
+		Line().Var().Id("body").Op("[]").Byte(),
+		Id("encoding").Op(":=").Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(String().Call(Id("response").Dot("Header").Dot("Peek").Call(Qual(packageFastHttp, "HeaderContentEncoding"))))),
+		Switch(Id("encoding")).Block(
+			Case(Lit(""), Lit("identity")).Block(
+				Return(),
+			),
+			Case(Lit(encodingBrotli)).Block(
+				List(Id("body"), Err()).Op("=").Id("response").Dot("BodyUnbrotli").Call(),
+			),
+			Case(Lit(encodingGzip)).Block(
+				List(Id("body"), Err()).Op("=").Id("response").Dot("BodyGunzip").Call(),
+			),
+			Case(Lit(encodingDeflate)).Block(
+				List(Id("body"), Err()).Op("=").Id("response").Dot("BodyInflate").Call(),
+			),
+			Default().Block(
+				Return(Qual(packageFmt, "Errorf").Call(Lit("unsupported content encoding '%s'"), Id("encoding"))),
+			),
+		),
+		If(Err().Op("!=").Nil()).Block(
+			Return(),
+		),
+		Id("response").Dot("Header").Dot("Del").Call(Qual(packageFastHttp, "HeaderContentEncoding")),
+		Id("response").Dot("SetBody").Call(Id("body")),
+		Return(),
+	)
+
+	return srcFile.Save(path.Join(outDir, "compress.go"))
+}
+
+// compressRequestNetHTTPFunc renders compression of body and setting of it to request.
+func (tr Transport) compressRequestNetHTTPFunc() Code {
+
+	return Func().Params(Id("cli").Op("*").Id("clientOptions")).Id("compressRequest").Params(Id("request").Op("*").Qual(packageHttp, "Request"), Id("body").Op("[]").Byte()).Params(Err().Error()).Block(
+
+		Line().Id("request").Dot("Header").Dot("Set").Call(Lit("Accept-Encoding"), Lit(acceptEncodingAll)),
+		If(Len(Id("body")).Op(">=").Id("compressMinSize")).Block(
+			Var().Id("compressed").Qual(packageBytes, "Buffer"),
+			Var().Id("writer").Qual(packageIO, "WriteCloser"),
+			Switch(Id("cli").Dot("compress")).Block(
+				Case(Lit(encodingBrotli)).Block(
+					Id("writer").Op("=").Qual(packageBrotli, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+				Case(Lit(encodingGzip)).Block(
+					Id("writer").Op("=").Qual(packageGzip, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+				Case(Lit(encodingDeflate)).Block(
+					Id("writer").Op("=").Qual(packageZlib, "NewWriter").Call(Op("&").Id("compressed")),
+				),
+			),
+			If(Id("writer").Op("!=").Nil()).Block(
+				If(List(Id("_"), Err()).Op("=").Id("writer").Dot("Write").Call(Id("body")).Op(";").Err().Op("!=").Nil()).Block(
+					Return(),
+				),
+				If(Err().Op("=").Id("writer").Dot("Close").Call().Op(";").Err().Op("!=").Nil()).Block(
+					Return(),
+				),
+				Id("body").Op("=").Id("compressed").Dot("Bytes").Call(),
+				Id("request").Dot("Header").Dot("Set").Call(Lit("Content-Encoding"), Id("cli").Dot("compress")),
+			),
+		),
+		If(Id("body").Op("==").Nil()).Block(
+			Return(),
+		),
+		Id("request").Dot("ContentLength").Op("=").Int64().Call(Len(Id("body"))),
+		Id("request").Dot("Body").Op("=").Qual(packageIOUtil, "NopCloser").Call(Qual(packageBytes, "NewReader").Call(Id("body"))),
+		Id("request").Dot("GetBody").Op("=").Func().Params().Params(Qual(packageIO, "ReadCloser"), Error()).Block(
+			Return(Qual(packageIOUtil, "NopCloser").Call(Qual(packageBytes, "NewReader").Call(Id("body"))), Nil()),
+		),
+		Return(),
+	)
+}
+
+// decompressResponseNetHTTPFunc renders reading of response body, body is decoded by its encoding.
+func (tr Transport) decompressResponseNetHTTPFunc() Code {
+
+	return Func().Id("decompressResponse").Params(Id("resp").Op("*").Qual(packageHttp, "Response")).Params(Id("response").Op("*").Id("httpResponse"), Err().Error()).Block(
+
+		Line().Var().Id("reader").Qual(packageIO, "Reader").Op("=").Id("resp").Dot("Body"),
+		Id("encoding").Op(":=").Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(Id("resp").Dot("Header").Dot("Get").Call(Lit("Content-Encoding")))),
+		Switch(Id("encoding")).Block(
+			Case(Lit(""), Lit("identity")).Block(),
+			Case(Lit(encodingBrotli)).Block(
+				Id("reader").Op("=").Qual(packageBrotli, "NewReader").Call(Id("resp").Dot("Body")),
+			),
+			Case(Lit(encodingGzip)).Block(
+				If(List(Id("reader"), Err()).Op("=").Qual(packageGzip, "NewReader").Call(Id("resp").Dot("Body")).Op(";").Err().Op("!=").Nil()).Block(
+					Return(),
+				),
+			),
+			Case(Lit(encodingDeflate)).Block(
+				If(List(Id("reader"), Err()).Op("=").Qual(packageZlib, "NewReader").Call(Id("resp").Dot("Body")).Op(";").Err().Op("!=").Nil()).Block(
+					Return(),
+				),
+			),
+			Default().Block(
+				Return(Nil(), Qual(packageFmt, "Errorf").Call(Lit("unsupported content encoding '%s'"), Id("encoding"))),
+			),
+		),
+		Id("response").Op("=").Op("&").Id("httpResponse").Values(Dict{Id("Response"): Id("resp")}),
+		If(List(Id("response").Dot("body"), Err()).Op("=").Qual(packageIOUtil, "ReadAll").Call(Id("reader")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(Nil(), Err()),
+		),
+		Id("resp").Dot("Header").Dot("Del").Call(Lit("Content-Encoding")),
+		Return(),
+	)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-compress_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const compressServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files
+	// @tg http-args=size|size
+	Get(ctx context.Context, size int) (data string, err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/raw
+	// @tg http-args=size|size
+	// @tg compress=false
+	Raw(ctx context.Context, size int) (data string, err error)
+
+	// @tg http-method=POST
+	// @tg http-path=/files
+	Put(ctx context.Context, data string) (size int, err error)
+}
+
+// @tg jsonRPC-server
+type Text interface {
+	Repeat(ctx context.Context, size int) (data string, err error)
+}
+`
+
+const compressCheck = `package gentest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/transport"
+)
+
+type files struct{}
+
+func (files) Get(ctx context.Context, size int) (string, error) {
+	return strings.Repeat("a", size), nil
+}
+
+func (files) Raw(ctx context.Context, size int) (string, error) {
+	return strings.Repeat("a", size), nil
+}
+
+func (files) Put(ctx context.Context, data string) (int, error) {
+	return len(data), nil
+}
+
+type text struct{}
+
+func (text) Repeat(ctx context.Context, size int) (string, error) {
+	return strings.Repeat("a", size), nil
+}
+
+// client does not decompress responses itself
+var client = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+func serve(t *testing.T, options ...transport.Option) string {
+
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	options = append(options,
+		transport.Files(transport.NewFiles(log, files{})),
+		transport.Text(transport.NewText(log, text{})),
+	)
+	srv := transport.New(log, options...)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+	waitServing(t, address)
+	return "http://" + address
+}
+
+func send(t *testing.T, method, url string, body []byte, header ...string) (response *http.Response, data []byte) {
+
+	t.Helper()
+
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(header); i += 2 {
+		request.Header.Set(header[i], header[i+1])
+	}
+	if response, err = client.Do(request); err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	var reader io.Reader = response.Body
+	switch response.Header.Get("Content-Encoding") {
+	case "br":
+		reader = brotli.NewReader(response.Body)
+	case "gzip":
+		if reader, err = gzip.NewReader(response.Body); err != nil {
+			t.Fatal(err)
+		}
+	case "deflate":
+		if reader, err = zlib.NewReader(response.Body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data, err = io.ReadAll(reader); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func TestCompressResponse(t *testing.T) {
+
+	url := serve(t)
+
+	for _, test := range []struct {
+		path     string
+		accept   string
+		encoding string
+	}{
+		{"/files?size=2000", "gzip", "gzip"},
+		{"/files?size=2000", "deflate", "deflate"},
+		{"/files?size=2000", "gzip, deflate, br", "br"},
+		{"/files?size=2000", "gzip;q=0.5, br;q=0.2", "gzip"},
+		{"/files?size=2000", "*", "br"},
+		{"/files?size=2000", "identity", ""},
+		{"/files?size=2000", "", ""},
+		{"/files?size=1000", "gzip", ""},
+		{"/raw?size=2000", "gzip", ""},
+	} {
+		response, data := send(t, http.MethodGet, url+test.path, nil, "Accept-Encoding", test.accept)
+		if encoding := response.Header.Get("Content-Encoding"); encoding != test.encoding {
+			t.Errorf("%s %q: unexpected encoding %q", test.path, test.accept, encoding)
+		}
+		size, _ := strconv.Atoi(test.path[strings.Index(test.path, "=")+1:])
+		if !strings.Contains(string(data), strings.Repeat("a", size)) {
+			t.Errorf("%s %q: unexpected body %d bytes", test.path, test.accept, len(data))
+		}
+	}
+
+	batch := []byte(` + "`" + `[{"jsonrpc":"2.0","id":1,"method":"text.repeat","params":{"size":2000}}]` + "`" + `)
+	response, data := send(t, http.MethodPost, url+"/", batch, "Accept-Encoding", "gzip")
+	if response.Header.Get("Content-Encoding") != "gzip" || !strings.Contains(string(data), strings.Repeat("a", 2000)) {
+		t.Errorf("unexpected response of batch %v %d bytes", response.Header, len(data))
+	}
+}
+
+func TestCompressMinSize(t *testing.T) {
+
+	url := serve(t, transport.CompressMinSize(64))
+	if response, _ := send(t, http.MethodGet, url+"/files?size=100", nil, "Accept-Encoding", "gzip"); response.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("response over threshold is not compressed %v", response.Header)
+	}
+	url = serve(t, transport.CompressMinSize(-1))
+	if response, _ := send(t, http.MethodGet, url+"/files?size=2000", nil, "Accept-Encoding", "gzip"); response.Header.Get("Content-Encoding") != "" {
+		t.Errorf("disabled compression compresses response %v", response.Header)
+	}
+}
+
+func TestDecompressRequest(t *testing.T) {
+
+	url := serve(t)
+
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	_, _ = writer.Write([]byte(` + "`" + `{"data":"` + "`" + ` + strings.Repeat("a", 3000) + ` + "`" + `"}` + "`" + `))
+	_ = writer.Close()
+	response, data := send(t, http.MethodPost, url+"/files", body.Bytes(), "Content-Encoding", "gzip")
+	if response.StatusCode != http.StatusOK || strings.TrimSpace(string(data)) != ` + "`" + `{"size":3000}` + "`" + ` {
+		t.Errorf("unexpected response to compressed request %d %s", response.StatusCode, data)
+	}
+	if response, _ = send(t, http.MethodPost, url+"/files", body.Bytes(), "Content-Encoding", "zstd"); response.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("unexpected status of unknown encoding %d", response.StatusCode)
+	}
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	cli := clients.NewHTTP("files", log, url, clients.Compress("br")).Files()
+	size, err := cli.Put(context.Background(), strings.Repeat("b", 5000))
+	if err != nil || size != 5000 {
+		t.Errorf("unexpected result of compressed request %d %v", size, err)
+	}
+	text, err := cli.Get(context.Background(), 5000)
+	if err != nil || text != strings.Repeat("a", 5000) {
+		t.Errorf("client does not decompress response %d %v", len(text), err)
+	}
+}
+`
+
+// TestCompress checks negotiation of response encoding, its threshold and decompression of requests.
+func TestCompress(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"compress_test.go":        compressCheck,
+		"interfaces/interface.go": compressServices,
+	}, nil, WithTracer(TracerNone))
+}
@@ -6,6 +6,8 @@ package generator
 import (
 	"path"
 	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
 )
 
 func (tr Transport) renderContext(outDir string) (err error) {
@@ -14,5 +16,128 @@ func (tr Transport) renderContext(outDir string) (err error) {
 	srcFile.PackageComment(doNotEdit)
 	srcFile.Const().Id("CtxCancelRequest").Op("=").Lit("ctxCancelRequest")
 
+	// contexts of net/http requests are canceled by server, so need no detaching
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+
+		srcFile.Line().Add(tr.detachedContextType())
+		srcFile.Line().Add(tr.requestContextFunc())
+		srcFile.Line().Add(tr.closeNotifyListenerType())
+		srcFile.Line().Add(tr.closeNotifyConnType())
+	}
+
 	return srcFile.Save(path.Join(outDir, "context.go"))
 }
+
+// detachedContextType renders context, which keeps values of request but is canceled by server only.
+// fasthttp cancels contexts of all requests at the start of shutdown, so in-flight calls could not be drained.
+func (tr Transport) detachedContextType() Code {
+
+	return Type().Id("detachedContext").Struct(
+		Qual(packageContext, "Context"),
+		Id("values").Qual(packageContext, "Context"),
+	).Line().Line().
+		Func().Params(Id(_ctx_).Id("detachedContext")).Id("Value").Params(Id("key").Interface()).Params(Interface()).Block(
+		Return(Id(_ctx_).Dot("values").Dot("Value").Call(Id("key"))),
+	)
+}
+
+// requestContextFunc renders context of request, which is canceled when client closes connection.
+func (tr Transport) requestContextFunc() Code {
+
+	return Comment("userValueRequestContext is key of request context in user values of request, which are keyed by strings only").Line().
+		Const().Id("userValueRequestContext").Op("=").Lit("tg.requestContext").Line().
+		Line().Comment("requestContext returns context of request served by ServeHTTP or ServeHTTPS, other requests get base context").Line().
+		Func().Id("requestContext").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("base").Qual(packageContext, "Context")).Qual(packageContext, "Context").Block(
+		If(List(Id("requestCtx"), Id("ok")).Op(":=").Id(_ctx_).Dot("UserValue").Call(Id("userValueRequestContext")).Op(".").Call(Qual(packageContext, "Context")).Op(";").Id("ok")).Block(
+			Return(Id("requestCtx")),
+		),
+		Return(Id("base")),
+	)
+}
+
+// closeNotifyListenerType renders listener of HTTP server, connections of which notify about close by client.
+// TLS is served by listener itself, so close_notify of client is read as end of connection.
+func (tr Transport) closeNotifyListenerType() Code {
+
+	return Comment("closeNotifier is connection, which cancels context of request when client closes it").Line().
+		Type().Id("closeNotifier").Interface(
+		Id("notifyClose").Params(Id("cancel").Qual(packageContext, "CancelFunc")),
+	).Line().
+		Line().Comment("closeNotifyListener wraps connections of HTTP server, so context of request is canceled when client closes connection").Line().
+		Type().Id("closeNotifyListener").Struct(
+		Qual(packageNet, "Listener"),
+	).Line().
+		Line().Func().Params(Id("listener").Id("closeNotifyListener")).Id("Accept").Params().Params(Qual(packageNet, "Conn"), Error()).Block(
+		List(Id("conn"), Err()).Op(":=").Id("listener").Dot("Listener").Dot("Accept").Call(),
+		If(Err().Op("!=").Nil()).Block(
+			Return(Nil(), Err()),
+		),
+		Id("notifier").Op(":=").Op("&").Id("closeNotifyConn").Values(Dict{Id("Conn"): Id("conn")}),
+		If(List(Id("_"), Id("ok")).Op(":=").Id("conn").Op(".").Call(Op("*").Qual(packageTLS, "Conn")).Op(";").Id("ok")).Block(
+			Return(Id("closeNotifyTLSConn").Values(Dict{Id("closeNotifyConn"): Id("notifier")}), Nil()),
+		),
+		Return(Id("notifier"), Nil()),
+	)
+}
+
+// closeNotifyConnType renders connection, which is read in background while request is handled, as net/http server does.
+func (tr Transport) closeNotifyConnType() Code {
+
+	return Comment("closeNotifyConn reads connection in background while request is handled,").Line().
+		Comment("byte read meanwhile is returned to server by next Read").Line().
+		Type().Id("closeNotifyConn").Struct(
+		Qual(packageNet, "Conn"),
+		Id("reading").Chan().Error(),
+		Id("buffered").Op("[]").Byte(),
+		Id("readErr").Error(),
+	).Line().
+		Line().Func().Params(Id("conn").Op("*").Id("closeNotifyConn")).Id("notifyClose").Params(Id("cancel").Qual(packageContext, "CancelFunc")).Block(
+		If(Id("conn").Dot("reading").Op("!=").Nil().Op("||").Len(Id("conn").Dot("buffered")).Op("!=").Lit(0).Op("||").Id("conn").Dot("readErr").Op("!=").Nil()).Block(
+			Return(),
+		),
+		Comment("deadline of reading request must not end background read of long request,").Line().
+			Comment("server sets deadline of connection again, when it waits for next request"),
+		Id("_").Op("=").Id("conn").Dot("Conn").Dot("SetReadDeadline").Call(Qual(packageTime, "Time").Values()),
+		Id("reading").Op(":=").Make(Chan().Error(), Lit(1)),
+		Id("conn").Dot("reading").Op("=").Id("reading"),
+		Go().Func().Params().Block(
+			Id("data").Op(":=").Make(Op("[]").Byte(), Lit(1)),
+			List(Id("n"), Err()).Op(":=").Id("conn").Dot("Conn").Dot("Read").Call(Id("data")),
+			Id("conn").Dot("buffered").Op("=").Id("data").Op("[:").Id("n").Op("]"),
+			Comment("idle deadline of next request may expire, it is not close of connection"),
+			If(Err().Op("!=").Nil().Op("&&").Op("!").Qual(packageOS, "IsTimeout").Call(Err())).Block(
+				Id("cancel").Call(),
+			),
+			Id("reading").Op("<-").Err(),
+		).Call(),
+	).Line().
+		Line().Func().Params(Id("conn").Op("*").Id("closeNotifyConn")).Id("Read").Params(Id("data").Op("[]").Byte()).Params(Id("n").Int(), Err().Error()).Block(
+		If(Id("conn").Dot("reading").Op("!=").Nil()).Block(
+			Err().Op("=").Op("<-").Id("conn").Dot("reading"),
+			Id("conn").Dot("reading").Op("=").Nil(),
+			If(Err().Op("!=").Nil().Op("&&").Op("!").Qual(packageOS, "IsTimeout").Call(Err())).Block(
+				Id("conn").Dot("readErr").Op("=").Err(),
+			),
+		),
+		If(Len(Id("conn").Dot("buffered")).Op("!=").Lit(0)).Block(
+			Id("n").Op("=").Copy(Id("data"), Id("conn").Dot("buffered")),
+			Id("conn").Dot("buffered").Op("=").Id("conn").Dot("buffered").Op("[").Id("n").Op(":]"),
+			Return(Id("n"), Nil()),
+		),
+		If(Id("conn").Dot("readErr").Op("!=").Nil()).Block(
+			Return(Lit(0), Id("conn").Dot("readErr")),
+		),
+		Return(Id("conn").Dot("Conn").Dot("Read").Call(Id("data"))),
+	).Line().
+		Line().Comment("closeNotifyTLSConn keeps methods of TLS connection, so fasthttp recognizes HTTPS requests").Line().
+		Type().Id("closeNotifyTLSConn").Struct(
+		Op("*").Id("closeNotifyConn"),
+	).Line().
+		Line().Func().Params(Id("conn").Id("closeNotifyTLSConn")).Id("Handshake").Params().Error().Block(
+		Return(Id("conn").Dot("Conn").Op(".").Call(Op("*").Qual(packageTLS, "Conn")).Dot("Handshake").Call()),
+	).Line().
+		Line().Func().Params(Id("conn").Id("closeNotifyTLSConn")).Id("ConnectionState").Params().Qual(packageTLS, "ConnectionState").Block(
+		Return(Id("conn").Dot("Conn").Op(".").Call(Op("*").Qual(packageTLS, "Conn")).Dot("ConnectionState").Call()),
+	)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-context_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const closeNotifyServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+type Waiter interface {
+
+	// @tg http-method=GET
+	// @tg http-path=/wait
+	Wait(ctx context.Context) (err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/sleep
+	// @tg http-args=ms|ms
+	Sleep(ctx context.Context, ms int) (err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/limited
+	// @tg timeout=100ms
+	Limited(ctx context.Context) (err error)
+}
+`
+
+// closeNotifyCheck is internal test of transport, server of which reads requests with short timeout
+const closeNotifyCheck = `package transport
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+)
+
+const readTimeout = 100 * time.Millisecond
+
+type waiter struct {
+	done chan error
+}
+
+func (w waiter) Wait(ctx context.Context) (err error) {
+	select {
+	case <-ctx.Done():
+		w.done <- ctx.Err()
+	case <-time.After(3 * time.Second):
+		w.done <- nil
+	}
+	return
+}
+
+func (w waiter) Sleep(ctx context.Context, ms int) (err error) {
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return ctx.Err()
+}
+
+func (w waiter) Limited(ctx context.Context) (err error) {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func serveWaiter(t *testing.T) (svc waiter, address string) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc = waiter{done: make(chan error, 1)}
+	srv := New(log, Waiter(NewWaiter(log, svc)))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fasthttp.Server{Handler: srv.httpHandler(), ReadTimeout: readTimeout}
+	go func() { _ = server.Serve(closeNotifyListener{Listener: listener}) }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	return svc, listener.Addr().String()
+}
+
+func TestCloseAfterReadTimeout(t *testing.T) {
+
+	svc, address := serveWaiter(t)
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(conn, "GET /wait HTTP/1.1\r\nHost: test\r\n\r\n")
+	// request is handled longer than server reads requests
+	time.Sleep(5 * readTimeout)
+	_ = conn.Close()
+
+	select {
+	case err = <-svc.done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error of method: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("context of method is not canceled, when client closed connection after read timeout")
+	}
+}
+
+func TestKeepAliveAfterReadTimeout(t *testing.T) {
+
+	_, address := serveWaiter(t)
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for _, ms := range []int{300, 0} {
+		fmt.Fprintf(conn, "GET /sleep?ms=%d HTTP/1.1\r\nHost: test\r\n\r\n", ms)
+		response, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatalf("request %dms: %v", ms, err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("request %dms: unexpected status %d", ms, response.StatusCode)
+		}
+	}
+	// idle connection is closed by server
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err = reader.ReadByte(); err == nil || isTimeout(err) {
+		t.Errorf("idle connection is not closed by server: %v", err)
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+
+	_, address := serveWaiter(t)
+	response, err := http.Get("http://" + address + "/limited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusGatewayTimeout {
+		t.Errorf("unexpected status of method timeout %d", response.StatusCode)
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+`
+
+// TestCloseNotify checks, that context of method is canceled by its timeout and by close of connection after read timeout of server.
+func TestCloseNotify(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": closeNotifyServices,
+		"transport/close_test.go": closeNotifyCheck,
+	}, nil, WithTracer(TracerNone))
+}
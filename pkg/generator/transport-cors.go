@@ -0,0 +1,350 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-cors.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/dave/jennifer/jen"
+
+	"github.com/seniorGolang/tg/pkg/tags"
+)
+
+var corsTagNames = []string{tagCorsOrigins, tagCorsMethods, tagCorsHeaders, tagCorsExpose, tagCorsCreds, tagCorsMaxAge}
+
+// corsTags returns CORS annotations of interface, annotations of interface have priority over package.
+func corsTags(pkgTags, svcTags tags.DocTags) (docTags tags.DocTags) {
+
+	docTags = make(tags.DocTags)
+	for _, source := range []tags.DocTags{pkgTags, svcTags} {
+		for _, tagName := range corsTagNames {
+			if source.IsSet(tagName) {
+				docTags[tagName] = source.Value(tagName)
+			}
+		}
+	}
+	return
+}
+
+func corsList(value string) (list []string) {
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+func (tr Transport) hasCORS() bool {
+
+	if tr.tags.IsSet(tagCorsOrigins) {
+		return true
+	}
+	for _, svc := range tr.services {
+		if svc.tags.IsSet(tagCorsOrigins) {
+			return true
+		}
+	}
+	return false
+}
+
+func (svc *service) hasCORS() bool {
+	return svc.cors != nil
+}
+
+// corsConfigCode renders configuration of annotations, interface without origins has no configuration
+// and is served without CORS headers until WithCORS option is set.
+func corsConfigCode(docTags tags.DocTags) Code {
+
+	if !docTags.IsSet(tagCorsOrigins) {
+		return Nil()
+	}
+	list := func(values []string) Code {
+		return Index().String().ValuesFunc(func(g *Group) {
+			for _, value := range values {
+				g.Lit(value)
+			}
+		})
+	}
+	values := Dict{
+		Id("AllowedOrigins"): list(corsList(docTags.Value(tagCorsOrigins))),
+	}
+	if methods := corsList(strings.ToUpper(docTags.Value(tagCorsMethods))); len(methods) != 0 {
+		values[Id("AllowedMethods")] = list(methods)
+	}
+	if headers := corsList(docTags.Value(tagCorsHeaders)); len(headers) != 0 {
+		values[Id("AllowedHeaders")] = list(headers)
+	}
+	if headers := corsList(docTags.Value(tagCorsExpose)); len(headers) != 0 {
+		values[Id("ExposedHeaders")] = list(headers)
+	}
+	if docTags.IsSet(tagCorsCreds) && docTags.Value(tagCorsCreds) != "false" {
+		values[Id("AllowCredentials")] = True()
+	}
+	if maxAge, _ := time.ParseDuration(docTags.Value(tagCorsMaxAge, "0")); maxAge >= time.Second {
+		values[Id("MaxAge")] = Lit(int(maxAge/time.Second)).Op("*").Qual(packageTime, "Second")
+	}
+	return Op("&").Id("CORSConfig").Values(values)
+}
+
+// corsRoutes returns registered paths of interface with their HTTP methods in order of registration.
+func (svc *service) corsRoutes() (paths []string, methods map[string][]string) {
+
+	methods = make(map[string][]string)
+	add := func(path, httpMethod string) {
+		if _, found := methods[path]; !found {
+			paths = append(paths, path)
+		}
+		methods[path] = append(methods[path], httpMethod)
+	}
+	if svc.tags.Contains(tagServerJsonRPC) {
+		add(svc.batchPath(), "POST")
+		for _, method := range svc.methods {
+			if method.isJsonRPC() {
+				add(method.jsonrpcPath(), "POST")
+			}
+		}
+	}
+	if svc.tags.Contains(tagServerHTTP) {
+		for _, method := range svc.methods {
+			if method.isHTTP() {
+				add(method.httpPath(), method.httpMethod())
+			}
+		}
+	}
+	return
+}
+
+// corsRouteHandler wraps handler of route, so CORS headers are added to responses of interface.
+func (svc *service) corsRouteHandler(handler Code) Code {
+
+	if !svc.hasCORS() {
+		return handler
+	}
+	return Id("http").Dot("withCORS").Call(handler)
+}
+
+// corsPreflightRoutes renders OPTIONS routes for all paths of interface, paths with own OPTIONS method are skipped.
+func (svc *service) corsPreflightRoutes(bg *Group) {
+
+	paths, methods := svc.corsRoutes()
+	bg.Line()
+	for _, routePath := range paths {
+		var hasOptions bool
+		var list []Code
+		for _, httpMethod := range methods[routePath] {
+			hasOptions = hasOptions || httpMethod == "OPTIONS"
+			list = append(list, Lit(httpMethod))
+		}
+		if hasOptions {
+			continue
+		}
+		bg.Add(svc.engine.route(Id("route"), "OPTIONS", routePath, Id("http").Dot("preflight").Call(list...)))
+	}
+}
+
+func (svc *service) corsFuncs() Code {
+	return corsFuncs(svc.engine, Id("http").Op("*").Id("http"+svc.Name), Id("http").Dot("cors"))
+}
+
+func corsFuncs(engine httpEngine, recv *Statement, config *Statement) Code {
+
+	return Func().Params(recv).Id("withCORS").Params(Id("handler").Add(engine.handlerType())).Params(engine.handlerType()).Block(
+		Return(Func().Params(engine.handlerParams()...).Block(
+			Id("handler").Call(engine.handlerArgs()...),
+			Add(config).Dot("setHeaders").Call(engine.handlerArgs()...),
+		)),
+	).Line().Line().Func().Params(recv).Id("preflight").Params(Id("methods").Op("...").String()).Params(engine.handlerType()).Block(
+		Return(Func().Params(engine.handlerParams()...).Block(
+			Add(config).Dot("preflight").Call(append(engine.handlerArgs(), Id("methods"))...),
+		)),
+	)
+}
+
+// renderCORS renders configuration of cross-origin requests, headers are set after handler,
+// so responses of errors and rejected calls are readable by browser too.
+func (tr Transport) renderCORS(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Line().Comment("CORSConfig of cross-origin requests, origin may contain one wildcard like 'https://*.example.com',")
+	srcFile.Comment("methods of route are allowed if AllowedMethods is empty, CORS-safelisted headers are allowed always")
+	srcFile.Type().Id("CORSConfig").Struct(
+		Id("AllowedOrigins").Op("[]").String(),
+		Id("AllowedMethods").Op("[]").String(),
+		Id("AllowedHeaders").Op("[]").String(),
+		Id("ExposedHeaders").Op("[]").String(),
+		Id("AllowCredentials").Bool(),
+		Id("MaxAge").Qual(packageTime, "Duration"),
+	)
+
+	srcFile.Line().Var().Id("corsSafelisted").Op("=").Map(String()).Struct().Values(Dict{
+		Lit("accept"):           Values(),
+		Lit("accept-language"):  Values(),
+		Lit("content-language"): Values(),
+		Lit("content-type"):     Values(),
+	})
+
+	srcFile.Line().Comment("WithCORS replaces CORS configuration of annotations for all interfaces and batch endpoint")
+	srcFile.Func().Id("WithCORS").Params(Id("config").Id("CORSConfig")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("cors").Op("=").Op("&").Id("config"),
+		)),
+	)
+
+	if tr.hasJsonRPC {
+		srcFile.Line().Add(corsFuncs(tr.engine, Id("srv").Op("*").Id("Server"), Id("srv").Dot("cors")))
+	}
+
+	srcFile.Line().Add(tr.corsSetHeadersFunc())
+	srcFile.Line().Add(tr.corsPreflightFunc())
+	srcFile.Line().Add(tr.corsAllowOriginFunc())
+
+	srcFile.Line().Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("allowedMethods").Params(Id("methods").Op("[]").String()).Params(Id("allowed").Op("[]").String()).Block(
+		Line().If(Len(Id("config").Dot("AllowedMethods")).Op("==").Lit(0)).Block(
+			Return(Id("methods")),
+		),
+		For(List(Id("_"), Id("method")).Op(":=").Range().Id("methods")).Block(
+			For(List(Id("_"), Id("configured")).Op(":=").Range().Id("config").Dot("AllowedMethods")).Block(
+				If(Id("configured").Op("==").Lit("*").Op("||").Id("configured").Op("==").Id("method")).Block(
+					Id("allowed").Op("=").Append(Id("allowed"), Id("method")),
+					Break(),
+				),
+			),
+		),
+		Return(),
+	)
+
+	srcFile.Line().Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("allowHeaders").Params(Id("headers").String()).Bool().Block(
+		Line().For(List(Id("_"), Id("header")).Op(":=").Range().Qual(packageStrings, "Split").Call(Id("headers"), Lit(","))).Block(
+			If(Id("header").Op("=").Qual(packageStrings, "ToLower").Call(Qual(packageStrings, "TrimSpace").Call(Id("header"))).Op(";").Id("header").Op("==").Lit("")).Block(
+				Continue(),
+			),
+			If(List(Id("_"), Id("found")).Op(":=").Id("corsSafelisted").Index(Id("header")).Op(";").Id("found")).Block(
+				Continue(),
+			),
+			Id("allowed").Op(":=").False(),
+			For(List(Id("_"), Id("configured")).Op(":=").Range().Id("config").Dot("AllowedHeaders")).Block(
+				If(Id("configured").Op("==").Lit("*").Op("||").Qual(packageStrings, "EqualFold").Call(Id("configured"), Id("header"))).Block(
+					Id("allowed").Op("=").True(),
+					Break(),
+				),
+			),
+			If(Op("!").Id("allowed")).Block(
+				Return(False()),
+			),
+		),
+		Return(True()),
+	)
+
+	return srcFile.Save(path.Join(outDir, "cors.go"))
+}
+
+// corsSetHeadersFunc renders headers of actual request, wildcard origin is replaced by origin of request for credentials.
+func (tr Transport) corsSetHeadersFunc() Code {
+
+	return Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("setHeaders").Params(tr.engine.handlerParams()...).Block(
+
+		Line().Id("origin").Op(":=").Add(tr.corsRequestHeader(Lit("Origin"))),
+		If(Id("config").Op("==").Nil().Op("||").Id("origin").Op("==").Lit("").Op("||").Op("!").Id("config").Dot("allowOrigin").Call(Id("origin"))).Block(
+			Return(),
+		),
+		tr.engine.responseHeader("Add", Lit("Vary"), Lit("Origin")),
+		tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Origin"), Id("config").Dot("originValue").Call(Id("origin"))),
+		If(Id("config").Dot("AllowCredentials")).Block(
+			tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Credentials"), Lit("true")),
+		),
+		If(Len(Id("config").Dot("ExposedHeaders")).Op("!=").Lit(0)).Block(
+			tr.engine.responseHeader("Set", Lit("Access-Control-Expose-Headers"), Qual(packageStrings, "Join").Call(Id("config").Dot("ExposedHeaders"), Lit(", "))),
+		),
+	)
+}
+
+// corsPreflightFunc renders response to OPTIONS request, preflight of not allowed origin, method or headers is forbidden.
+func (tr Transport) corsPreflightFunc() Code {
+
+	return Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("preflight").Params(append(tr.engine.handlerParams(), Id("methods").Op("[]").String())...).Block(
+
+		Line().Add(tr.engine.setStatus(tr.engine.status("StatusNoContent"))),
+		tr.engine.responseHeader("Set", Lit("Allow"), Qual(packageStrings, "Join").Call(Id("methods"), Lit(", ")).Op("+").Lit(", OPTIONS")),
+
+		Line().Id("origin").Op(":=").Add(tr.corsRequestHeader(Lit("Origin"))),
+		Id("method").Op(":=").Add(tr.corsRequestHeader(Lit("Access-Control-Request-Method"))),
+		If(Id("config").Op("==").Nil().Op("||").Id("origin").Op("==").Lit("").Op("||").Id("method").Op("==").Lit("")).Block(
+			Return(),
+		),
+		tr.engine.responseHeader("Add", Lit("Vary"), Lit("Origin")),
+		tr.engine.responseHeader("Add", Lit("Vary"), Lit("Access-Control-Request-Method")),
+		tr.engine.responseHeader("Add", Lit("Vary"), Lit("Access-Control-Request-Headers")),
+
+		Line().Id("allowed").Op(":=").Id("config").Dot("allowedMethods").Call(Id("methods")),
+		Id("headers").Op(":=").Add(tr.corsRequestHeader(Lit("Access-Control-Request-Headers"))),
+		If(Op("!").Id("config").Dot("allowOrigin").Call(Id("origin")).Op("||").Op("!").Id("containsString").Call(Id("allowed"), Id("method")).Op("||").Op("!").Id("config").Dot("allowHeaders").Call(Id("headers"))).Block(
+			tr.engine.setStatus(tr.engine.status("StatusForbidden")),
+			Return(),
+		),
+		tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Origin"), Id("config").Dot("originValue").Call(Id("origin"))),
+		tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Methods"), Qual(packageStrings, "Join").Call(Id("allowed"), Lit(", "))),
+		If(Id("headers").Op("!=").Lit("")).Block(
+			tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Headers"), Id("headers")),
+		),
+		If(Id("config").Dot("AllowCredentials")).Block(
+			tr.engine.responseHeader("Set", Lit("Access-Control-Allow-Credentials"), Lit("true")),
+		),
+		If(Id("config").Dot("MaxAge").Op(">").Lit(0)).Block(
+			tr.engine.responseHeader("Set", Lit("Access-Control-Max-Age"), Qual(packageStrconv, "Itoa").Call(Int().Call(Id("config").Dot("MaxAge").Dot("Seconds").Call()))),
+		),
+	)
+}
+
+func (tr Transport) corsRequestHeader(name Code) Code {
+
+	if tr.isNetHTTP() {
+		return Id("r").Dot("Header").Dot("Get").Call(name)
+	}
+	return String().Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(name))
+}
+
+func (tr Transport) corsAllowOriginFunc() Code {
+
+	return Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("allowOrigin").Params(Id("origin").String()).Bool().Block(
+
+		Line().For(List(Id("_"), Id("allowed")).Op(":=").Range().Id("config").Dot("AllowedOrigins")).Block(
+			If(Id("allowed").Op("==").Lit("*").Op("||").Qual(packageStrings, "EqualFold").Call(Id("allowed"), Id("origin"))).Block(
+				Return(True()),
+			),
+			If(Id("i").Op(":=").Qual(packageStrings, "Index").Call(Id("allowed"), Lit("*")).Op(";").Id("i").Op(">=").Lit(0).Op("&&").Len(Id("origin")).Op(">=").Len(Id("allowed")).Op("&&").
+				Qual(packageStrings, "HasPrefix").Call(Id("origin"), Id("allowed").Index(Op(":").Id("i"))).Op("&&").Qual(packageStrings, "HasSuffix").Call(Id("origin"), Id("allowed").Index(Id("i").Op("+").Lit(1).Op(":")))).Block(
+				Return(True()),
+			),
+		),
+		Return(False()),
+	).Line().Line().Func().Params(Id("config").Op("*").Id("CORSConfig")).Id("originValue").Params(Id("origin").String()).String().Block(
+
+		Line().If(Op("!").Id("config").Dot("AllowCredentials")).Block(
+			For(List(Id("_"), Id("allowed")).Op(":=").Range().Id("config").Dot("AllowedOrigins")).Block(
+				If(Id("allowed").Op("==").Lit("*")).Block(
+					Return(Lit("*")),
+				),
+			),
+		),
+		Return(Id("origin")),
+	).Line().Line().Func().Id("containsString").Params(Id("values").Op("[]").String(), Id("value").String()).Bool().Block(
+		For(List(Id("_"), Id("item")).Op(":=").Range().Id("values")).Block(
+			If(Id("item").Op("==").Id("value")).Block(
+				Return(True()),
+			),
+		),
+		Return(False()),
+	)
+}
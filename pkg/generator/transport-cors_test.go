@@ -0,0 +1,172 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-cors_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const corsServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+// @tg cors-origins=` + "`https://*.example.com`" + `
+// @tg cors-headers=X-Token cors-expose=X-Request-Id
+// @tg cors-credentials cors-max-age=10m
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files/{id}
+	Get(ctx context.Context, id string) (name string, err error)
+
+	// @tg http-method=DELETE
+	// @tg http-path=/files/{id}
+	Remove(ctx context.Context, id string) (err error)
+}
+
+// @tg jsonRPC-server
+type Calc interface {
+	Add(ctx context.Context, first int, second int) (sum int, err error)
+}
+`
+
+const corsCheck = `package gentest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type files struct{}
+
+func (files) Get(ctx context.Context, id string) (string, error) {
+	return id, nil
+}
+
+func (files) Remove(ctx context.Context, id string) error {
+	return nil
+}
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, first int, second int) (int, error) {
+	return first + second, nil
+}
+
+func serve(t *testing.T, options ...transport.Option) string {
+
+	t.Helper()
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	options = append(options,
+		transport.Files(transport.NewFiles(log, files{})),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	)
+	srv := transport.New(log, options...)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+	waitServing(t, address)
+	return "http://" + address
+}
+
+func send(t *testing.T, method, url string, header ...string) *http.Response {
+
+	t.Helper()
+
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(header); i += 2 {
+		request.Header.Set(header[i], header[i+1])
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	return response
+}
+
+func TestCORS(t *testing.T) {
+
+	url := serve(t)
+
+	response := send(t, http.MethodOptions, url+"/files/1",
+		"Origin", "https://app.example.com",
+		"Access-Control-Request-Method", "DELETE",
+		"Access-Control-Request-Headers", "X-Token, Content-Type",
+	)
+	if response.StatusCode != http.StatusNoContent {
+		t.Fatalf("unexpected status of preflight %d", response.StatusCode)
+	}
+	for header, value := range map[string]string{
+		"Access-Control-Allow-Origin":      "https://app.example.com",
+		"Access-Control-Allow-Methods":     "GET, DELETE",
+		"Access-Control-Allow-Headers":     "X-Token, Content-Type",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	} {
+		if actual := response.Header.Get(header); actual != value {
+			t.Errorf("unexpected preflight header %s: %q", header, actual)
+		}
+	}
+
+	for _, header := range [][]string{
+		{"Origin", "https://example.org", "Access-Control-Request-Method", "GET"},
+		{"Origin", "https://app.example.com", "Access-Control-Request-Method", "PUT"},
+		{"Origin", "https://app.example.com", "Access-Control-Request-Method", "GET", "Access-Control-Request-Headers", "X-Other"},
+	} {
+		response = send(t, http.MethodOptions, url+"/files/1", header...)
+		if response.StatusCode != http.StatusForbidden || response.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("%v: unexpected response of disallowed preflight %d %v", header, response.StatusCode, response.Header)
+		}
+	}
+
+	response = send(t, http.MethodGet, url+"/files/1", "Origin", "https://app.example.com")
+	if response.StatusCode != http.StatusOK ||
+		response.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		response.Header.Get("Access-Control-Expose-Headers") != "X-Request-Id" {
+		t.Errorf("unexpected response of method %d %v", response.StatusCode, response.Header)
+	}
+	response = send(t, http.MethodGet, url+"/files/1", "Origin", "https://example.org")
+	if response.StatusCode != http.StatusOK || response.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unexpected response to disallowed origin %d %v", response.StatusCode, response.Header)
+	}
+}
+
+func TestWithCORS(t *testing.T) {
+
+	url := serve(t, transport.WithCORS(transport.CORSConfig{AllowedOrigins: []string{"*"}}))
+
+	preflight := []string{"Origin", "https://example.org", "Access-Control-Request-Method", "POST"}
+	response := send(t, http.MethodOptions, url+"/", preflight...)
+	if response.StatusCode != http.StatusNoContent || response.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("unexpected preflight of batch %d %v", response.StatusCode, response.Header)
+	}
+	if response = send(t, http.MethodOptions, url+"/files/1", preflight...); response.StatusCode != http.StatusForbidden {
+		t.Errorf("method is allowed, which route does not have, %d", response.StatusCode)
+	}
+	response = send(t, http.MethodGet, url+"/files/1", "Origin", "https://example.org")
+	if response.Header.Get("Access-Control-Allow-Origin") != "*" || response.Header.Get("Access-Control-Expose-Headers") != "" {
+		t.Errorf("option does not replace annotations %v", response.Header)
+	}
+}
+`
+
+// TestCORS checks headers of preflight requests and responses by annotations and by option of server.
+func TestCORS(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"cors_test.go":            corsCheck,
+		"interfaces/interface.go": corsServices,
+	}, nil, WithTracer(TracerNone))
+}
@@ -18,8 +18,14 @@ func (tr Transport) renderErrors(outDir string) (err error) {
 	srcFile.Line().Type().Id("withErrorCode").Interface(
 		Id("Code").Call().Int(),
 	)
+	if tr.hasJsonRPC {
+		srcFile.Line().Type().Id("withErrorCodeJsonRPC").Interface(
+			Id("JsonRPCCode").Call().Int(),
+		)
+	}
 
 	srcFile.Line().Add(tr.strErrorType())
+	srcFile.Line().Add(tr.shutdownErrorsType())
 	srcFile.Line().Add(tr.exitOnErrorFunc())
 
 	return srcFile.Save(path.Join(outDir, "errors.go"))
@@ -33,6 +39,27 @@ func (tr Transport) strErrorType() Code {
 	)
 }
 
+func (tr Transport) shutdownErrorsType() Code {
+
+	return Type().Id("shutdownErrors").Op("[]").Error().Line().
+		Func().Params(Id("e").Id("shutdownErrors")).Id("Error").Params().Params(String()).Block(
+		Id("messages").Op(":=").Make(Op("[]").String(), Lit(0), Len(Id("e"))),
+		For(List(Id("_"), Err()).Op(":=").Range().Id("e")).Block(
+			Id("messages").Op("=").Append(Id("messages"), Err().Dot("Error").Call()),
+		),
+		Return(Qual(packageStrings, "Join").Call(Id("messages"), Lit("; "))),
+	).Line().
+		Line().Comment("Is reports whether any error of shutdown matches target, like context.DeadlineExceeded").Line().
+		Func().Params(Id("e").Id("shutdownErrors")).Id("Is").Params(Id("target").Error()).Bool().Block(
+		For(List(Id("_"), Err()).Op(":=").Range().Id("e")).Block(
+			If(Qual(packageErrors, "Is").Call(Err(), Id("target"))).Block(
+				Return(True()),
+			),
+		),
+		Return(False()),
+	)
+}
+
 func (tr Transport) exitOnErrorFunc() Code {
 
 	return Func().Id("ExitOnError").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Err().Error(), Id("msg").String()).Block(
@@ -0,0 +1,350 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-grpc.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+type protoField struct {
+	name      string
+	goName    string
+	protoName string
+	pType     string
+	vType     types.Type
+	pkg       string
+}
+
+type protoMessage struct {
+	name    string
+	pkgPath string
+	goType  Code
+	fields  []protoField
+}
+
+type grpcGen struct {
+	*Transport
+
+	ctx      context.Context
+	srcFile  srcFile
+	pbPkg    string
+	protoPkg string
+
+	depth        int
+	values       int
+	useTimestamp bool
+	messages     map[string]*protoMessage
+	namedTypes   map[string]protoInfo
+	typeMessages map[string]string
+}
+
+func newGRPC(tr *Transport, outDir string) (gen *grpcGen) {
+
+	gen = &grpcGen{
+		Transport:    tr,
+		messages:     make(map[string]*protoMessage),
+		namedTypes:   make(map[string]protoInfo),
+		typeMessages: make(map[string]string),
+	}
+	gen.srcFile = newSrc(filepath.Base(outDir))
+	gen.ctx = context.WithValue(context.Background(), "code", gen.srcFile)
+
+	pkgDir := outDir
+	if tr.pkgDir != "" {
+		pkgDir = tr.pkgDir
+	}
+	absPath, _ := filepath.Abs(path.Join(pkgDir, "pb"))
+	gen.pbPkg, _ = utils.GetPkgPath(absPath, true)
+	gen.protoPkg = protoIdent(tr.tags.Value(tagGRPCPackage, filepath.Base(outDir)))
+	return
+}
+
+func (tr Transport) renderGRPC(outDir string) (err error) {
+
+	gen := newGRPC(&tr, outDir)
+
+	if err = os.MkdirAll(path.Join(outDir, "pb"), 0777); err != nil {
+		return
+	}
+	for _, serviceName := range tr.serviceKeys() {
+		if svc := tr.services[serviceName]; svc.tags.Contains(tagServerGRPC) {
+			if err = gen.renderService(outDir, svc); err != nil {
+				return
+			}
+		}
+	}
+	if err = gen.renderProto(outDir); err != nil {
+		return
+	}
+	if err = gen.renderPbDoc(outDir); err != nil {
+		return
+	}
+	return gen.renderConverters(outDir)
+}
+
+func (gen *grpcGen) protoFile() string {
+	return gen.protoPkg + ".proto"
+}
+
+func (gen *grpcGen) renderPbDoc(outDir string) (err error) {
+
+	pbFile := NewFile("pb")
+	pbFile.PackageComment(doNotEdit)
+	pbFile.PackageComment("//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative " + gen.protoFile())
+	return pbFile.Save(path.Join(outDir, "pb", "pb.go"))
+}
+
+func (gen *grpcGen) renderProto(outDir string) (err error) {
+
+	var proto strings.Builder
+
+	_, _ = fmt.Fprintf(&proto, "// %s\nsyntax = \"proto3\";\n\npackage %s;\n\noption go_package = %q;\n", doNotEdit, gen.protoPkg, gen.pbPkg)
+	if gen.useTimestamp {
+		_, _ = fmt.Fprint(&proto, "\nimport \"google/protobuf/timestamp.proto\";\n")
+	}
+
+	for _, serviceName := range gen.serviceKeys() {
+
+		svc := gen.services[serviceName]
+		if !svc.tags.Contains(tagServerGRPC) {
+			continue
+		}
+		_, _ = fmt.Fprintf(&proto, "\nservice %s {\n", svc.Name)
+		for _, method := range svc.methods {
+			_, _ = fmt.Fprintf(&proto, "    rpc %s(%s) returns (%s);\n", method.Name, method.grpcRequestName(), method.grpcResponseName())
+		}
+		_, _ = fmt.Fprint(&proto, "}\n")
+	}
+
+	var names []string
+	for name := range gen.messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		_, _ = fmt.Fprintf(&proto, "\nmessage %s {\n", name)
+		for i, field := range gen.messages[name].fields {
+			_, _ = fmt.Fprintf(&proto, "    %s %s = %d;\n", field.pType, field.protoName, i+1)
+		}
+		_, _ = fmt.Fprint(&proto, "}\n")
+	}
+
+	protoPath := path.Join(outDir, "pb", gen.protoFile())
+	gen.log.Info("write to ", protoPath)
+	return ioutil.WriteFile(protoPath, []byte(proto.String()), 0600)
+}
+
+func (gen *grpcGen) renderConverters(outDir string) (err error) {
+
+	srcFile := gen.srcFile
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(gen.pbPkg, "pb")
+	srcFile.ImportName(packageGRPCCodes, "codes")
+	srcFile.ImportName(packageGRPCStatus, "status")
+
+	srcFile.Line().Add(gen.grpcErrorFunc())
+	srcFile.Line().Add(gen.grpcCodeFunc())
+
+	var names []string
+	for name, message := range gen.messages {
+		if message.goType != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		message := gen.messages[name]
+
+		srcFile.Line().Func().Id("toProto"+name).Params(Id("v").Op("*").Add(message.goType)).Params(Id("m").Op("*").Qual(gen.pbPkg, goCamelCase(name)), Err().Error()).BlockFunc(func(bg *Group) {
+			gen.values = 0
+			bg.If(Id("v").Op("==").Nil()).Block(Return())
+			bg.Id("m").Op("=").Op("&").Qual(gen.pbPkg, goCamelCase(name)).Values()
+			for _, field := range message.fields {
+				bg.Add(gen.toProto(field.pkg, field.vType, Id("m").Dot(field.goName), Id("v").Dot(field.name), Return()))
+			}
+			bg.Return()
+		})
+		srcFile.Line().Func().Id("fromProto"+name).Params(Id("m").Op("*").Qual(gen.pbPkg, goCamelCase(name))).Params(Id("v").Op("*").Add(message.goType), Err().Error()).BlockFunc(func(bg *Group) {
+			gen.values = 0
+			bg.If(Id("m").Op("==").Nil()).Block(Return())
+			bg.Id("v").Op("=").Op("&").Add(message.goType).Values()
+			for _, field := range message.fields {
+				bg.Add(gen.fromProto(field.pkg, field.vType, Id("v").Dot(field.name), Id("m").Dot(field.goName), Return()))
+			}
+			bg.Return()
+		})
+	}
+	return srcFile.Save(path.Join(outDir, "grpc.go"))
+}
+
+func (gen *grpcGen) grpcErrorFunc() Code {
+
+	return Func().Id("grpcError").Params(Err().Error()).Error().Block(
+		If(Err().Op("==").Nil()).Block(Return(Nil())),
+		If(List(Id("_"), Id("ok")).Op(":=").Qual(packageGRPCStatus, "FromError").Call(Err()).Op(";").Id("ok")).Block(Return(Err())),
+		Id("code").Op(":=").Qual(packageGRPCCodes, "Unknown"),
+		If(List(Id("errCoder"), Id("ok")).Op(":=").Err().Op(".").Parens(Id("withErrorCode")).Op(";").Id("ok")).Block(
+			Id("code").Op("=").Id("grpcCode").Call(Id("errCoder").Dot("Code").Call()),
+		).Else().If(Qual(packageErrors, "Is").Call(Err(), Qual(packageContext, "DeadlineExceeded"))).Block(
+			Id("code").Op("=").Qual(packageGRPCCodes, "DeadlineExceeded"),
+		),
+		Return(Qual(packageGRPCStatus, "Error").Call(Id("code"), Err().Dot("Error").Call())),
+	)
+}
+
+func (gen *grpcGen) grpcCodeFunc() Code {
+
+	httpToGRPC := []struct {
+		http int
+		grpc string
+	}{
+		{400, "InvalidArgument"},
+		{401, "Unauthenticated"},
+		{403, "PermissionDenied"},
+		{404, "NotFound"},
+		{409, "AlreadyExists"},
+		{429, "ResourceExhausted"},
+		{499, "Canceled"},
+		{501, "Unimplemented"},
+		{503, "Unavailable"},
+		{504, "DeadlineExceeded"},
+	}
+	return Func().Id("grpcCode").Params(Id("httpCode").Int()).Qual(packageGRPCCodes, "Code").Block(
+		Switch(Id("httpCode")).BlockFunc(func(sg *Group) {
+			for _, code := range httpToGRPC {
+				sg.Case(Lit(code.http)).Block(Return(Qual(packageGRPCCodes, code.grpc)))
+			}
+		}),
+		If(Id("httpCode").Op(">=").Lit(500)).Block(Return(Qual(packageGRPCCodes, "Internal"))),
+		Return(Qual(packageGRPCCodes, "Unknown")),
+	)
+}
+
+func (m method) grpcRequestName() string {
+	return m.svc.Name + m.Name + "Request"
+}
+
+func (m method) grpcResponseName() string {
+	return m.svc.Name + m.Name + "Response"
+}
+
+// registerMessage adds request or response message of method. Variables without names are skipped.
+func (gen *grpcGen) registerMessage(name, pkgPath string, vars []types.Variable) {
+
+	message := &protoMessage{name: name, pkgPath: pkgPath}
+	gen.messages[name] = message
+
+	for _, variable := range vars {
+		message.fields = append(message.fields, gen.newField(utils.ToLowerCamel(variable.Name), variable.Name, pkgPath, variable.Type))
+	}
+}
+
+func (gen *grpcGen) newField(protoName, goName, pkgPath string, vType types.Type) protoField {
+
+	protoName = protoIdent(protoName)
+	return protoField{
+		name:      goName,
+		goName:    goCamelCase(protoName),
+		protoName: protoName,
+		pType:     gen.protoType(pkgPath, vType),
+		vType:     vType,
+		pkg:       pkgPath,
+	}
+}
+
+// structMessage returns name of proto message for named struct type and registers it on first use.
+func (gen *grpcGen) structMessage(pkgPath, typeName string, structType types.Struct) string {
+
+	key := pkgPath + "." + typeName
+	if name, found := gen.typeMessages[key]; found {
+		return name
+	}
+
+	name := typeName
+	if _, found := gen.messages[name]; found {
+		name = utils.ToCamel(filepath.Base(pkgPath)) + typeName
+	}
+	gen.typeMessages[key] = name
+
+	message := &protoMessage{name: name, pkgPath: pkgPath, goType: Qual(pkgPath, typeName)}
+	gen.messages[name] = message
+
+	for _, field := range structType.Fields {
+
+		if field.Name == "" || !isExported(field.Name) {
+			continue
+		}
+		fieldName := jsonName(field)
+		if fieldName == "-" {
+			continue
+		}
+		protoField := gen.newField(fieldName, field.Name, pkgPath, field.Type)
+		message.fields = append(message.fields, protoField)
+	}
+	return name
+}
+
+func isExported(name string) bool {
+	return name[:1] == strings.ToUpper(name[:1])
+}
+
+// protoIdent replaces symbols which are not allowed in proto identifiers.
+func protoIdent(name string) string {
+
+	ident := []byte(name)
+	for i, c := range ident {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' && i > 0 || c == '_') {
+			ident[i] = '_'
+		}
+	}
+	return string(ident)
+}
+
+// goCamelCase is a port of protoc-gen-go naming rules to predict field names of generated structs.
+func goCamelCase(s string) string {
+
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isLower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isLower(s[i+1]):
+		case isDigit(c):
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isLower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
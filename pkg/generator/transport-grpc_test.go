@@ -0,0 +1,227 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-grpc_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const grpcTypes = `package types
+
+import "time"
+
+type Item struct {
+	Name string    ` + "`json:\"name\"`" + `
+	Tags []string  ` + "`json:\"tags\"`" + `
+	At   time.Time ` + "`json:\"at\"`" + `
+}
+`
+
+const grpcServices = `package interfaces
+
+import (
+	"context"
+
+	"gentest/types"
+)
+
+// @tg grpc-server
+type Store interface {
+	Put(ctx context.Context, key string, item types.Item, ttl int64) (ok bool, err error)
+	Get(ctx context.Context, key string) (item *types.Item, items map[string]types.Item, err error)
+	// @tg timeout=50ms
+	Slow(ctx context.Context) (err error)
+}
+`
+
+// grpcMessages replaces code of protoc, so adapters are checked without protoc and its plugins
+const grpcMessages = `package pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Item struct {
+	Name string
+	Tags []string
+	At   *timestamppb.Timestamp
+}
+
+type StorePutRequest struct {
+	Key  string
+	Item *Item
+	Ttl  int64
+}
+
+type StorePutResponse struct {
+	Ok bool
+}
+
+type StoreGetRequest struct {
+	Key string
+}
+
+type StoreGetResponse struct {
+	Item  *Item
+	Items map[string]*Item
+}
+
+type StoreSlowRequest struct{}
+
+type StoreSlowResponse struct{}
+
+type StoreServer interface {
+	Put(context.Context, *StorePutRequest) (*StorePutResponse, error)
+	Get(context.Context, *StoreGetRequest) (*StoreGetResponse, error)
+	Slow(context.Context, *StoreSlowRequest) (*StoreSlowResponse, error)
+}
+
+type UnimplementedStoreServer struct{}
+
+// Registered is adapter of service registered by server
+var Registered StoreServer
+
+func RegisterStoreServer(server *grpc.Server, srv StoreServer) {
+	Registered = srv
+}
+`
+
+const grpcCheck = `package gentest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"gentest/transport"
+	"gentest/transport/pb"
+	"gentest/types"
+)
+
+type notFound string
+
+func (e notFound) Error() string {
+	return fmt.Sprintf("%s is not found", string(e))
+}
+
+func (e notFound) Code() int {
+	return 404
+}
+
+type store struct {
+	items map[string]types.Item
+}
+
+func (s store) Put(ctx context.Context, key string, item types.Item, ttl int64) (ok bool, err error) {
+	s.items[key] = item
+	return ttl > 0, nil
+}
+
+func (s store) Get(ctx context.Context, key string) (item *types.Item, items map[string]types.Item, err error) {
+	found, ok := s.items[key]
+	if !ok {
+		return nil, nil, notFound(key)
+	}
+	return &found, s.items, nil
+}
+
+func (s store) Slow(ctx context.Context) (err error) {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestGRPC(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	srv := transport.New(log, transport.Store(transport.NewStore(log, store{items: make(map[string]types.Item)})))
+	if err := srv.ServeGRPC(freeAddress(t)); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	adapter := pb.Registered
+	if adapter == nil {
+		t.Fatal("adapter of service is not registered")
+	}
+
+	at := time.Date(2020, 5, 14, 2, 13, 0, 0, time.UTC)
+	putResponse, err := adapter.Put(context.Background(), &pb.StorePutRequest{
+		Key:  "a",
+		Item: &pb.Item{Name: "first", Tags: []string{"x", "y"}, At: timestamppb.New(at)},
+		Ttl:  1,
+	})
+	if err != nil || !putResponse.Ok {
+		t.Fatalf("unexpected result of put %v: %v", putResponse, err)
+	}
+
+	getResponse, err := adapter.Get(context.Background(), &pb.StoreGetRequest{Key: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := getResponse.Item
+	if item == nil || item.Name != "first" || len(item.Tags) != 2 || item.Tags[1] != "y" || !item.At.AsTime().Equal(at) {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if len(getResponse.Items) != 1 || getResponse.Items["a"].Name != "first" {
+		t.Errorf("unexpected items %+v", getResponse.Items)
+	}
+
+	if _, err = adapter.Get(context.Background(), &pb.StoreGetRequest{Key: "b"}); status.Code(err) != codes.NotFound {
+		t.Errorf("unexpected error of missing item: %v", err)
+	}
+	if _, err = adapter.Slow(context.Background(), &pb.StoreSlowRequest{}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("unexpected error of method timeout: %v", err)
+	}
+}
+`
+
+// TestGRPC renders proto of interface and checks conversions and errors of gRPC adapters.
+func TestGRPC(t *testing.T) {
+
+	t.Run("proto", func(t *testing.T) {
+		tr, dir := testTransport(t, map[string]string{
+			"interfaces/interface.go": grpcServices,
+			"types/types.go":          grpcTypes,
+		}, WithTracer(TracerNone))
+		outDir := filepath.Join(dir, "transport")
+		if err := tr.RenderServer(outDir); err != nil {
+			t.Fatal(err)
+		}
+		proto, err := ioutil.ReadFile(filepath.Join(outDir, "pb", "transport.proto"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			`option go_package = "gentest/transport/pb";`,
+			`import "google/protobuf/timestamp.proto";`,
+			"rpc Put(StorePutRequest) returns (StorePutResponse);",
+			"message Item {\n    string name = 1;\n    repeated string tags = 2;\n    google.protobuf.Timestamp at = 3;\n}",
+			"message StoreGetResponse {\n    Item item = 1;\n    map<string, Item> items = 2;\n}",
+			"message StorePutRequest {\n    string key = 1;\n    Item item = 2;\n    int64 ttl = 3;\n}",
+		} {
+			if !strings.Contains(string(proto), want) {
+				t.Errorf("proto does not contain %q:\n%s", want, proto)
+			}
+		}
+	})
+
+	testGenerated(t, map[string]string{
+		"grpc_test.go":             grpcCheck,
+		"interfaces/interface.go":  grpcServices,
+		"transport/pb/messages.go": grpcMessages,
+		"types/types.go":           grpcTypes,
+	}, nil, WithTracer(TracerNone))
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-health.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+func (tr Transport) renderHealth(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Line().Const().Id("defaultHealthTimeout").Op("=").Qual(packageTime, "Second").Op("*").Lit(5)
+
+	srcFile.Line().Const().Defs(
+		Id("healthOK").Op("=").Lit("ok"),
+		Id("healthFail").Op("=").Lit("fail"),
+		Id("healthShutdown").Op("=").Lit("shutdown"),
+	)
+
+	srcFile.Line().Type().Id("HealthCheck").Func().Params(Id(_ctx_).Qual(packageContext, "Context")).Params(Error())
+
+	srcFile.Line().Comment("HealthChecker could be implemented by service to be checked by readiness probe automatically")
+	srcFile.Type().Id("HealthChecker").Interface(
+		Id("HealthCheck").Params(Id(_ctx_).Qual(packageContext, "Context")).Params(Error()),
+	)
+
+	srcFile.Line().Type().Id("healthCheck").Struct(
+		Id("name").String(),
+		Id("check").Id("HealthCheck"),
+		Id("timeout").Qual(packageTime, "Duration"),
+	)
+
+	srcFile.Line().Type().Id("healthStatus").Struct(
+		Id("Status").String().Tag(map[string]string{"json": "status"}),
+		Id("Error").String().Tag(map[string]string{"json": "error,omitempty"}),
+		Id("Checks").Map(String()).Id("healthStatus").Tag(map[string]string{"json": "checks,omitempty"}),
+	)
+
+	srcFile.Line().Add(tr.withHealthCheckFunc())
+	srcFile.Line().Add(tr.withHealthCheckTimeoutFunc())
+	srcFile.Line().Add(tr.registerHealthCheckersFunc())
+	srcFile.Line().Add(tr.serveHealthFunc())
+	srcFile.Line().Add(tr.readinessFunc())
+	srcFile.Line().Add(tr.runHealthCheckFunc())
+
+	return srcFile.Save(path.Join(outDir, "health.go"))
+}
+
+func (tr Transport) withHealthCheckFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("WithHealthCheck").Params(Id("name").String(), Id("check").Id("HealthCheck")).Params(Op("*").Id("Server")).Block(
+		Return(Id("srv").Dot("WithHealthCheckTimeout").Call(Id("name"), Id("defaultHealthTimeout"), Id("check"))),
+	)
+}
+
+func (tr Transport) withHealthCheckTimeoutFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("WithHealthCheckTimeout").Params(Id("name").String(), Id("timeout").Qual(packageTime, "Duration"), Id("check").Id("HealthCheck")).Params(Op("*").Id("Server")).Block(
+		Id("srv").Dot("healthMtx").Dot("Lock").Call(),
+		Defer().Id("srv").Dot("healthMtx").Dot("Unlock").Call(),
+		Id("srv").Dot("healthChecks").Op("=").Append(Id("srv").Dot("healthChecks"), Id("healthCheck").Values(Dict{
+			Id("name"):    Id("name"),
+			Id("check"):   Id("check"),
+			Id("timeout"): Id("timeout"),
+		})),
+		Return(Id("srv")),
+	)
+}
+
+// registerHealthCheckersFunc renders registration of checks for services, which implement HealthChecker.
+func (tr Transport) registerHealthCheckersFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("registerHealthCheckers").Params().BlockFunc(func(bg *Group) {
+
+		for _, serviceName := range tr.serviceKeys() {
+			bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
+				If(List(Id("checker"), Id("ok")).Op(":=").Id("srv").Dot("http" + serviceName).Dot("base").Assert(Id("HealthChecker")).Op(";").Id("ok")).Block(
+					Id("srv").Dot("WithHealthCheck").Call(Lit(tr.services[serviceName].lcName()), Id("checker").Dot("HealthCheck")),
+				),
+			)
+		}
+	})
+}
+
+func (tr Transport) serveHealthFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHealth").Params(Id("address").String()).Params(Err().Error()).Block(
+
+		Line().Id("srv").Dot("srvHealth").Op("=").Add(tr.healthServer(Func().Params(tr.engine.handlerParams()...).Block(
+			Switch(tr.healthPath()).Block(
+				Case(Lit("/live")).Block(
+					tr.engine.sendResponse(Id("srv").Dot("log"), Id("healthStatus").Values(Dict{Id("Status"): Id("healthOK")})),
+				),
+				Comment("root path is kept for probes of previous versions, which answered on any path"),
+				Case(Lit("/"), Lit("/ready")).Block(
+					Id("srv").Dot("readiness").Call(tr.engine.handlerArgs()...),
+				),
+				Default().Block(
+					tr.engine.setStatus(tr.engine.status("StatusNotFound")),
+				),
+			),
+		))),
+		Return(Id("srv").Dot("serve").Call(Id("address"), Lit("health"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+			Return(Id("srv").Dot("srvHealth").Dot("Serve").Call(Id("listener"))),
+		))),
+	)
+}
+
+// readinessFunc renders probe, which runs all checks in parallel and fails if any of them fails or server is stopping.
+// Checks get context of server instead of request context, which is reused by fasthttp after response.
+func (tr Transport) readinessFunc() Code {
+
+	send := func(status Code) Code {
+		return tr.engine.sendResponse(Id("srv").Dot("log"), status)
+	}
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("readiness").Params(tr.engine.handlerParams()...).Block(
+
+		Line().If(Qual(packageAtomic, "LoadInt32").Call(Op("&").Id("srv").Dot("stopping")).Op("!=").Lit(0)).Block(
+			tr.connectionClose(),
+			tr.engine.setStatus(tr.engine.status("StatusServiceUnavailable")),
+			send(Id("healthStatus").Values(Dict{Id("Status"): Id("healthShutdown")})),
+			Return(),
+		),
+
+		Line().Id("srv").Dot("healthMtx").Dot("RLock").Call(),
+		Id("checks").Op(":=").Append(Op("[]").Id("healthCheck").Call(Nil()), Id("srv").Dot("healthChecks").Op("...")),
+		Id("srv").Dot("healthMtx").Dot("RUnlock").Call(),
+
+		Line().Id("status").Op(":=").Id("healthStatus").Values(Dict{
+			Id("Status"): Id("healthOK"),
+			Id("Checks"): Make(Map(String()).Id("healthStatus"), Len(Id("checks"))),
+		}),
+
+		Line().Var().Id("mtx").Qual(packageSync, "Mutex"),
+		Var().Id("wg").Qual(packageSync, "WaitGroup"),
+		For(List(Id("_"), Id("check")).Op(":=").Range().Id("checks")).Block(
+			Id("wg").Dot("Add").Call(Lit(1)),
+			Go().Func().Params(Id("check").Id("healthCheck")).Block(
+				Defer().Id("wg").Dot("Done").Call(),
+				Id("checkStatus").Op(":=").Id("healthStatus").Values(Dict{Id("Status"): Id("healthOK")}),
+				If(Err().Op(":=").Id("runHealthCheck").Call(Id("srv").Dot("ctx"), Id("check")).Op(";").Err().Op("!=").Nil()).Block(
+					Id("checkStatus").Op("=").Id("healthStatus").Values(Dict{Id("Status"): Id("healthFail"), Id("Error"): Err().Dot("Error").Call()}),
+				),
+				Id("mtx").Dot("Lock").Call(),
+				Id("status").Dot("Checks").Index(Id("check").Dot("name")).Op("=").Id("checkStatus"),
+				If(Id("checkStatus").Dot("Status").Op("!=").Id("healthOK")).Block(
+					Id("status").Dot("Status").Op("=").Id("healthFail"),
+				),
+				Id("mtx").Dot("Unlock").Call(),
+			).Call(Id("check")),
+		),
+		Id("wg").Dot("Wait").Call(),
+
+		Line().If(Id("status").Dot("Status").Op("!=").Id("healthOK")).Block(
+			tr.engine.setStatus(tr.engine.status("StatusServiceUnavailable")),
+		),
+		send(Id("status")),
+	)
+}
+
+// healthServer renders server of probes, response of net/http handler is buffered,
+// so status could be set before body.
+func (tr Transport) healthServer(handler Code) Code {
+
+	if tr.isNetHTTP() {
+		return Op("&").Qual(packageHttp, "Server").Values(Dict{
+			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
+			Id("Handler"):     Id("buffered").Call(handler),
+		})
+	}
+	return Op("&").Qual(packageFastHttp, "Server").Values(Dict{
+		Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
+		Id("Handler"):     handler,
+	})
+}
+
+func (tr Transport) healthPath() Code {
+
+	if tr.isNetHTTP() {
+		return Id("r").Dot("URL").Dot("Path")
+	}
+	return String().Call(Id(_ctx_).Dot("Path").Call())
+}
+
+func (tr Transport) connectionClose() Code {
+
+	if tr.isNetHTTP() {
+		return Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Connection"), Lit("close"))
+	}
+	return Id(_ctx_).Dot("SetConnectionClose").Call()
+}
+
+// runHealthCheckFunc renders check call limited by timeout, even if check ignores context.
+func (tr Transport) runHealthCheckFunc() Code {
+
+	return Func().Id("runHealthCheck").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("check").Id("healthCheck")).Params(Err().Error()).Block(
+
+		Line().List(Id(_ctx_), Id("cancel")).Op(":=").Qual(packageContext, "WithTimeout").Call(Id(_ctx_), Id("check").Dot("timeout")),
+		Defer().Id("cancel").Call(),
+
+		Line().Id("done").Op(":=").Make(Chan().Error(), Lit(1)),
+		Go().Func().Params().Block(
+			Id("done").Op("<-").Id("check").Dot("check").Call(Id(_ctx_)),
+		).Call(),
+		Select().Block(
+			Case(Err().Op("=").Op("<-").Id("done")).Block(
+				Return(),
+			),
+			Case(Op("<-").Id(_ctx_).Dot("Done").Call()).Block(
+				Return(Id(_ctx_).Dot("Err").Call()),
+			),
+		),
+	)
+}
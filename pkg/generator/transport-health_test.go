@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-health_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const healthServices = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server
+type Store interface {
+	Get(ctx context.Context, key string) (value string, err error)
+}
+`
+
+const healthCheck = `package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type store struct {
+	broken int32
+}
+
+func (s *store) Get(ctx context.Context, key string) (value string, err error) {
+	return key, nil
+}
+
+// HealthCheck makes store checked by readiness probe automatically
+func (s *store) HealthCheck(ctx context.Context) error {
+	if atomic.LoadInt32(&s.broken) != 0 {
+		return errors.New("store is broken")
+	}
+	return nil
+}
+
+type health struct {
+	Status string            ` + "`json:\"status\"`" + `
+	Checks map[string]health ` + "`json:\"checks\"`" + `
+}
+
+func probe(t *testing.T, url string) (statusCode int, status health) {
+
+	t.Helper()
+
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	_ = json.NewDecoder(response.Body).Decode(&status)
+	return response.StatusCode, status
+}
+
+func TestHealth(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := &store{}
+	srv := transport.New(log, transport.ShutdownDelay(500*time.Millisecond), transport.Store(transport.NewStore(log, svc)))
+	var slow int32
+	srv.WithHealthCheckTimeout("slow", 50*time.Millisecond, func(ctx context.Context) error {
+		if atomic.LoadInt32(&slow) != 0 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	address := freeAddress(t)
+	if err := srv.ServeHealth(address); err != nil {
+		t.Fatal(err)
+	}
+	waitServing(t, address)
+	url := "http://" + address
+
+	if code, status := probe(t, url+"/ready"); code != http.StatusOK || status.Checks["store"].Status != "ok" || status.Checks["slow"].Status != "ok" {
+		t.Errorf("unexpected readiness of healthy server %d %+v", code, status)
+	}
+
+	atomic.StoreInt32(&svc.broken, 1)
+	atomic.StoreInt32(&slow, 1)
+	if code, status := probe(t, url+"/ready"); code != http.StatusServiceUnavailable || status.Checks["store"].Status != "fail" || status.Checks["slow"].Status != "fail" {
+		t.Errorf("unexpected readiness of broken server %d %+v", code, status)
+	}
+	if code, status := probe(t, url+"/live"); code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("broken checks fail liveness %d %+v", code, status)
+	}
+	if code, _ := probe(t, url+"/unknown"); code != http.StatusNotFound {
+		t.Errorf("unexpected status of unknown path %d", code)
+	}
+
+	atomic.StoreInt32(&svc.broken, 0)
+	atomic.StoreInt32(&slow, 0)
+	go func() { _ = srv.Shutdown(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+	if code, status := probe(t, url+"/ready"); code != http.StatusServiceUnavailable || status.Status != "shutdown" {
+		t.Errorf("unexpected readiness of stopping server %d %+v", code, status)
+	}
+	if code, _ := probe(t, url+"/live"); code != http.StatusOK {
+		t.Errorf("unexpected liveness of stopping server %d", code)
+	}
+}
+`
+
+// TestHealth checks liveness and readiness probes with checks of service, their timeouts and shutdown.
+func TestHealth(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"health_test.go":          healthCheck,
+		"interfaces/interface.go": healthServices,
+	}, nil, WithTracer(TracerNone))
+}
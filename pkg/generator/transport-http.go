@@ -16,9 +16,27 @@ func (tr Transport) renderHTTP(outDir string) (err error) {
 	srcFile.PackageComment(doNotEdit)
 
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
 	srcFile.ImportName(packageMultipart, "multipart")
 
+	if tr.isNetHTTP() {
+		srcFile.Line().Type().Id("cookieType").Interface(
+			Id("Cookie").Params().Params(Op("*").Qual(packageHttp, "Cookie")),
+		)
+
+		srcFile.Line().Func().Id("uploadFile").Params(Id("r").Op("*").Qual(packageHttp, "Request"), Id("key").String()).Params(Id("data").Op("[]").Byte(), Err().Error()).Block(
+
+			Line().Var().Id("file").Qual(packageMultipart, "File"),
+			If(List(Id("file"), Id("_"), Err()).Op("=").Id("r").Dot("FormFile").Call(Id("key")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			),
+			Defer().Id("file").Dot("Close").Call(),
+			Return(Qual(packageIOUtil, "ReadAll").Call(Id("file"))),
+		)
+		return srcFile.Save(path.Join(outDir, "http.go"))
+	}
+
+	srcFile.ImportName(packageFastHttp, "fasthttp")
+
 	srcFile.Line().Type().Id("cookieType").Interface(
 		Id("Cookie").Params().Params(Op("*").Qual(packageFastHttp, "Cookie")),
 	)
@@ -18,7 +18,9 @@ func (tr Transport) renderJsonRPC(outDir string) (err error) {
 	srcFile.PackageComment(doNotEdit)
 
 	srcFile.ImportName(packageGotils, "gotils")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 	srcFile.ImportName(packageOpentracingExt, "ext")
 	srcFile.ImportName(packageOpentracing, "opentracing")
 
@@ -28,9 +30,14 @@ func (tr Transport) renderJsonRPC(outDir string) (err error) {
 	srcFile.Add(tr.errorJsonRPC()).Line()
 	srcFile.Add(tr.jsonrpcResponsesTypeFunc())
 
-	srcFile.Line().Type().Id("methodJsonRPC").Func().Params(Id("span").Qual(packageOpentracing, "Span"), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("requestBase").Id("baseJsonRPC")).Params(Id("responseBase").Op("*").Id("baseJsonRPC"))
+	srcFile.Line().Type().Id("methodJsonRPC").Func().Params(append(append([]Code{Id("span").Add(tr.tracer.spanType())}, tr.engine.handlerParams()...), Id("requestBase").Id("baseJsonRPC"))...).Params(Id("responseBase").Op("*").Id("baseJsonRPC"))
+
+	srcFile.Line().Const().Id("defaultMaxParallelBatch").Op("=").Lit(100)
 
-	srcFile.Add(tr.serveBatchFunc())
+	srcFile.Line().Add(tr.serveBatchFunc())
+	srcFile.Line().Add(tr.batchCallFunc())
+	srcFile.Line().Add(tr.decodeBatchFunc())
+	srcFile.Line().Add(tr.callBatchFunc())
 
 	srcFile.Line().Add(tr.makeErrorResponseJsonRPCFunc())
 
@@ -39,101 +46,155 @@ func (tr Transport) renderJsonRPC(outDir string) (err error) {
 
 func (tr Transport) serveBatchFunc() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("serveBatch").Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("serveBatch").Params(tr.engine.handlerParams()...).Block(
 
-		Line().Id("batchSpan").Op(":=").Id("extractSpan").Call(Id("srv").Dot("log"), Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("URI").Call().Dot("Path").Call())), Id(_ctx_)),
-		Defer().Id("injectSpan").Call(Id("srv").Dot("log"), Id("batchSpan"), Id(_ctx_)),
-		Defer().Id("batchSpan").Dot("Finish").Call(),
+		Line().Id("batchSpan").Op(":=").Add(tr.tracer.extractSpan("srv", Qual(packageFmt, "Sprintf").Call(Lit("jsonRPC:%s"), tr.engine.requestPath()), tr.engine.request())),
+		tr.tracer.deferSpan(Id("srv").Dot("log"), "batchSpan", tr.engine.handlerArgs()...),
+		Defer().Id("srv").Dot("observeBatch").Call(tr.engine.handlerArgs()...),
 
-		Id("methodHTTP").Op(":=").Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Method").Call()),
+		Id("methodHTTP").Op(":=").Add(tr.engine.requestMethod()),
 
-		Line().If(Id("methodHTTP").Op("!=").Qual(packageFastHttp, "MethodPost")).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("batchSpan"), True()),
-			Id("batchSpan").Dot("SetTag").Call(Lit("msg"), Lit("only POST method supported")),
-			Id(_ctx_).Dot("Error").Call(Lit("only POST method supported"), Qual(packageFastHttp, "StatusMethodNotAllowed")),
+		Line().If(Id("methodHTTP").Op("!=").Add(tr.engine.status("MethodPost"))).Block(
+			tr.tracer.setError("batchSpan", Lit("only POST method supported")),
+			tr.engine.replyError(Lit("only POST method supported"), tr.engine.status("StatusMethodNotAllowed")),
 			Return(),
 		),
 
 		Line().For(List(Id("_"), Id("handler")).Op(":=").Range().Id("srv").Dot("httpBefore")).Block(
-			Id("handler").Call(Id(_ctx_)),
+			Id("handler").Call(tr.engine.handlerArgs()...),
 		),
 
-		Line().If(Id("value").Op(":=").Id(_ctx_).Dot("Value").Call(Id("CtxCancelRequest")).Op(";").Id("value").Op("!=").Nil()).Block(
+		Line().If(Id("value").Op(":=").Add(tr.engine.cancelRequest()).Op(";").Id("value").Op("!=").Nil()).Block(
 			Return(),
 		),
 
-		Line().Id(_ctx_).Dot("SetContentType").Call(Id("contentTypeJson")),
-
-		Line().Var().Err().Error(),
-		Var().Id("requests").Op("[]").Id("baseJsonRPC"),
+		Line().Add(tr.engine.contentType(Id("contentTypeJson"))),
 
-		Line().If(Err().Op("=").Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("PostBody").Call(), Op("&").Id("requests")).Op(";").Err().Op("!=").Nil()).Block(
-			Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("batchSpan"), True()),
-			Id("batchSpan").Dot("SetTag").Call(Lit("msg"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call()),
+		Line().Id("requests").Op(",").Id("errResponse").Op(":=").Id("decodeBatch").Call(append(tr.engine.requestBody(), Id("srv").Dot("maxBatchSize"))...),
+		If(Id("errResponse").Op("!=").Nil()).Block(
+			tr.tracer.setError("batchSpan", Id("errResponse").Dot("Error").Dot("Message")),
 			Line().For(List(Id("_"), Id("handler")).Op(":=").Range().Id("srv").Dot("httpAfter")).Block(
-				Id("handler").Call(Id(_ctx_)),
+				Id("handler").Call(tr.engine.handlerArgs()...),
 			),
-			Id("sendResponse").Call(Id("srv").Dot("log"), Id(_ctx_), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
+			tr.engine.sendResponse(Id("srv").Dot("log"), Id("errResponse")),
 			Return(),
 		),
 
-		Line().Id("responses").Op(":=").Make(Id("jsonrpcResponses"), Lit(0), Len(Id("requests"))),
+		Line().Id("responses").Op(":=").Id("callBatch").Call(Id("requests"), Id("srv").Dot("maxParallelBatch"), Func().Params(Id("request").Id("baseJsonRPC")).Params(Op("*").Id("baseJsonRPC")).Block(
+			Return(Id("srv").Dot("batchCall").Call(append(append([]Code{Id("batchSpan")}, tr.engine.handlerArgs()...), Id("request"))...)),
+		)),
 
-		Line().Var().Id("n").Int(),
-		Var().Id("wg").Qual(packageSync, "WaitGroup"),
+		Line().For(List(Id("_"), Id("handler")).Op(":=").Range().Id("srv").Dot("httpAfter")).Block(
+			Id("handler").Call(tr.engine.handlerArgs()...),
+		),
+		tr.engine.sendResponse(Id("srv").Dot("log"), Id("responses")),
+	)
+}
 
-		Line().For(List(Id("_"), Id("request")).Op(":=").Range().Id("requests")).Block(
+// decodeBatchFunc renders decoding of batch, which is shared by root and per-service batch handlers.
+func (tr Transport) decodeBatchFunc() Code {
 
-			Line().Id("methodNameOrigin").Op(":=").Id("request").Dot("Method"),
-			Id("method").Op(":=").Qual(packageStrings, "ToLower").Call(Id("request").Dot("Method")),
+	params := []Code{Id("body").Op("[]").Byte(), Id("maxBatchSize").Int()}
+	if tr.isNetHTTP() {
+		params = append(tr.engine.handlerParams(), Id("maxBatchSize").Int())
+	}
+	var decode Code = Qual(packageJson, "Unmarshal").Call(Id("body"), Op("&").Id("requests"))
+	if tr.isNetHTTP() {
+		decode = tr.engine.decodeBody(Op("&").Id("requests"))
+	}
+	return Func().Id("decodeBatch").Params(params...).Params(Id("requests").Op("[]").Id("baseJsonRPC"), Id("errResponse").Op("*").Id("baseJsonRPC")).Block(
 
-			Line().Switch(Id("method")).BlockFunc(func(bg *Group) {
+		Line().If(Err().Op(":=").Add(decode).Op(";").Err().Op("!=").Nil()).Block(
+			tr.engine.bodyTooLarge(),
+			Return(Nil(), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("parseError"), Lit("request body could not be decoded: ").Op("+").Err().Dot("Error").Call(), Nil())),
+		),
+		If(Len(Id("requests")).Op("==").Lit(0)).Block(
+			Return(Nil(), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("invalidRequestError"), Lit("empty batch"), Nil())),
+		),
+		If(Id("maxBatchSize").Op(">").Lit(0).Op("&&").Len(Id("requests")).Op(">").Id("maxBatchSize")).Block(
+			Return(Nil(), Id("makeErrorResponseJsonRPC").Call(Op("[]").Byte().Call(Lit(`"0"`)), Id("invalidRequestError"), Qual(packageFmt, "Sprintf").Call(Lit("batch size exceeded, max %d requests"), Id("maxBatchSize")), Nil())),
+		),
+		Return(),
+	)
+}
 
-				for serviceName, service := range tr.services {
+// callBatchFunc renders bounded pool of workers, which executes requests of batch.
+func (tr Transport) callBatchFunc() Code {
 
-					for _, method := range service.methods {
+	return Func().Id("callBatch").Params(Id("requests").Op("[]").Id("baseJsonRPC"), Id("workers").Int(), Id("call").Func().Params(Id("request").Id("baseJsonRPC")).Params(Op("*").Id("baseJsonRPC"))).Params(Id("responses").Id("jsonrpcResponses")).Block(
 
-						if !method.isJsonRPC() {
-							continue
-						}
+		Line().If(Id("workers").Op("<=").Lit(0).Op("||").Id("workers").Op(">").Len(Id("requests"))).Block(
+			Id("workers").Op("=").Len(Id("requests")),
+		),
 
-						bg.Line().Case(Lit(service.lcName()+"."+method.lcName())).Block(
-							Id("wg").Dot("Add").Call(Lit(1)),
-							Go().Func().Params(Id("request").Id("baseJsonRPC")).Block(
-								Line().Id("span").Op(":=").Qual(packageOpentracing, "StartSpan").Call(Id("request").Dot("Method"), Qual(packageOpentracing, "ChildOf").Call(Id("batchSpan").Dot("Context").Call())),
-								Id("span").Dot("SetTag").Call(Lit("batch"), True()),
-								Defer().Id("span").Dot("Finish").Call(),
-								If(Id("request").Dot("ID").Op("!=").Nil()).Block(
-									Id("responses").Dot("append").Call(Id("srv").Dot("http"+serviceName).Dot(utils.ToLowerCamel(method.Name)).Call(Id("span"), Id(_ctx_), Id("request"))),
-									Id("wg").Dot("Done").Call(),
-									Return(),
-								),
-								Id("srv").Dot("http"+serviceName).Dot(utils.ToLowerCamel(method.Name)).Call(Id("span"), Id(_ctx_), Id("request")),
-								Id("wg").Dot("Done").Call(),
-							).Call(Id("request")),
-						)
+		Line().Comment("each worker writes only to its own element of results, order of requests is kept"),
+		Id("results").Op(":=").Make(Op("[]").Op("*").Id("baseJsonRPC"), Len(Id("requests"))),
+		Id("queue").Op(":=").Make(Chan().Int()),
+
+		Line().Var().Id("wg").Qual(packageSync, "WaitGroup"),
+		Id("wg").Dot("Add").Call(Id("workers")),
+
+		Line().For(Id("i").Op(":=").Lit(0).Op(";").Id("i").Op("<").Id("workers").Op(";").Id("i").Op("++")).Block(
+			Go().Func().Params().Block(
+				Defer().Id("wg").Dot("Done").Call(),
+				For(Id("n").Op(":=").Range().Id("queue")).Block(
+					Id("results").Index(Id("n")).Op("=").Id("call").Call(Id("requests").Index(Id("n"))),
+				),
+			).Call(),
+		),
+		For(Id("n").Op(":=").Range().Id("requests")).Block(
+			Id("queue").Op("<-").Id("n"),
+		),
+		Close(Id("queue")),
+		Id("wg").Dot("Wait").Call(),
+
+		Line().Id("responses").Op("=").Make(Id("jsonrpcResponses"), Lit(0), Len(Id("requests"))),
+		For(List(Id("_"), Id("response")).Op(":=").Range().Id("results")).Block(
+			Id("responses").Dot("append").Call(Id("response")),
+		),
+		Return(),
+	)
+}
+
+func (tr Transport) batchCallFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("batchCall").
+		Params(append(append([]Code{Id("batchSpan").Add(tr.tracer.spanType())}, tr.engine.handlerParams()...), Id("request").Id("baseJsonRPC"))...).
+		Params(Id("response").Op("*").Id("baseJsonRPC")).Block(
+
+		Line().Id("methodNameOrigin").Op(":=").Id("request").Dot("Method"),
+		Id("method").Op(":=").Qual(packageStrings, "ToLower").Call(Id("request").Dot("Method")),
+
+		Line().Id("span").Op(":=").Add(tr.tracer.startChild("batchSpan", Id("request").Dot("Method"))),
+		tr.tracer.setTag("span", Lit("batch"), True()),
+		tr.tracer.deferFinish("span"),
+
+		Line().Switch(Id("method")).BlockFunc(func(bg *Group) {
+
+			for _, serviceName := range tr.serviceKeys() {
+
+				service := tr.services[serviceName]
+
+				for _, method := range service.methods {
+
+					if !method.isJsonRPC() {
+						continue
 					}
+					bg.Case(Lit(service.lcName() + "." + method.lcName())).Block(
+						Return(Id("srv").Dot("http" + serviceName).Dot(utils.ToLowerCamel(method.Name)).Call(append(append([]Code{Id("span")}, tr.engine.handlerArgs()...), Id("request"))...)),
+					)
 				}
-				bg.Line().Default().Block(
-					Id("span").Op(":=").Qual(packageOpentracing, "StartSpan").Call(Id("request").Dot("Method"), Qual(packageOpentracing, "ChildOf").Call(Id("batchSpan").Dot("Context").Call())),
-					Id("span").Dot("SetTag").Call(Lit("batch"), True()),
-					Qual(packageOpentracingExt, "Error").Dot("Set").Call(Id("span"), True()),
-					Id("span").Dot("SetTag").Call(Lit("msg"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'")),
-					Id("responses").Dot("append").Call(Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'"), Nil())),
-					Id("span").Dot("Finish").Call(),
+			}
+			if tr.hasDiscover() {
+				bg.Case(Lit("rpc.discover")).Block(
+					Return(Id("srv").Dot("discover").Call(Id("request"))),
 				)
-			}),
-			Line().If(Id("n").Op(">").Id("maxParallelBatch")).Block(
-				Id("n").Op("=").Lit(0),
-				Id("wg").Dot("Wait").Call(),
-			),
-			Id("n").Op("++"),
-		),
-		Line().Id("wg").Dot("Wait").Call(),
-		Line().For(List(Id("_"), Id("handler")).Op(":=").Range().Id("srv").Dot("httpAfter")).Block(
-			Id("handler").Call(Id(_ctx_)),
-		),
-		Id("sendResponse").Call(Id("srv").Dot("log"), Id(_ctx_), Id("responses")),
+			}
+			bg.Default().Block(
+				tr.tracer.setError("span", Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'")),
+				Return(Id("makeErrorResponseJsonRPC").Call(Id("request").Dot("ID"), Id("methodNotFoundError"), Lit("invalid method '").Op("+").Id("methodNameOrigin").Op("+").Lit("'"), Nil())),
+			)
+		}),
 	)
 }
 
@@ -218,7 +279,6 @@ func (tr Transport) jsonrpcConstants(exportErrors bool) Code {
 	}
 
 	return Const().Op("(").
-		Line().Id("maxParallelBatch").Op("=").Lit(100).
 		Line().Comment("Version defines the version of the JSON RPC implementation").
 		Line().Id("Version").Op("=").Lit("2.0").
 		Line().Comment("contentTypeJson defines the content type to be served").
@@ -234,5 +294,13 @@ func (tr Transport) jsonrpcConstants(exportErrors bool) Code {
 		Line().Id(export("invalidParamsError", exportErrors)).Op("=").Lit(-32602).
 		Line().Comment("InternalError defines a server error").
 		Line().Id(export("internalError", exportErrors)).Op("=").Lit(-32603).
+		Line().Comment("TimeoutError defines the method call exceeded its deadline").
+		Line().Id(export("timeoutError", exportErrors)).Op("=").Lit(-32001).
+		Line().Comment("UnauthorizedError defines the request has no valid credentials for the method").
+		Line().Id(export("unauthorizedError", exportErrors)).Op("=").Lit(-32002).
+		Line().Comment("ForbiddenError defines the principal has no role required by the method").
+		Line().Id(export("forbiddenError", exportErrors)).Op("=").Lit(-32003).
+		Line().Comment("LimitExceededError defines the call is rejected by rate or concurrency limit of the method").
+		Line().Id(export("limitExceededError", exportErrors)).Op("=").Lit(-32004).
 		Op(")")
 }
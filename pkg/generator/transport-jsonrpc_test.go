@@ -0,0 +1,132 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-jsonrpc_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const batchServices = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server
+type Worker interface {
+	Work(ctx context.Context, ms int) (done int, err error)
+}
+`
+
+const batchCheck = `package gentest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type worker struct {
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (w *worker) Work(ctx context.Context, ms int) (done int, err error) {
+	current := atomic.AddInt32(&w.inFlight, 1)
+	defer atomic.AddInt32(&w.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&w.maxInFlight)
+		if current <= max || atomic.CompareAndSwapInt32(&w.maxInFlight, max, current) {
+			break
+		}
+	}
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return ms, nil
+}
+
+type response struct {
+	ID     json.RawMessage ` + "`json:\"id\"`" + `
+	Result struct {
+		Done int ` + "`json:\"done\"`" + `
+	} ` + "`json:\"result\"`" + `
+	Error *struct {
+		Code    int    ` + "`json:\"code\"`" + `
+		Message string ` + "`json:\"message\"`" + `
+	} ` + "`json:\"error\"`" + `
+}
+
+func postBatch(t *testing.T, address string, sleeps ...int) (body []byte) {
+
+	t.Helper()
+
+	var requests []string
+	for i, ms := range sleeps {
+		requests = append(requests, fmt.Sprintf(` + "`" + `{"jsonrpc":"2.0","id":%d,"method":"worker.work","params":{"ms":%d}}` + "`" + `, i+1, ms))
+	}
+	httpResponse, err := http.Post("http://"+address+"/", "application/json", strings.NewReader("["+strings.Join(requests, ",")+"]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer httpResponse.Body.Close()
+	var raw json.RawMessage
+	if err = json.NewDecoder(httpResponse.Body).Decode(&raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestBatch(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := &worker{}
+	address := freeAddress(t)
+	srv := transport.New(log, transport.MaxBatchSize(5), transport.MaxParallelBatch(2), transport.Worker(transport.NewWorker(log, svc)))
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+
+	// later requests are done earlier, but responses keep order of requests
+	sleeps := []int{200, 150, 100, 50, 0}
+	var responses []response
+	if err := json.Unmarshal(postBatch(t, address, sleeps...), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != len(sleeps) {
+		t.Fatalf("unexpected count of responses %d", len(responses))
+	}
+	for i, resp := range responses {
+		if string(resp.ID) != fmt.Sprint(i+1) || resp.Error != nil || resp.Result.Done != sleeps[i] {
+			t.Errorf("unexpected response %d: %s %+v %+v", i, resp.ID, resp.Result, resp.Error)
+		}
+	}
+	if max := atomic.LoadInt32(&svc.maxInFlight); max != 2 {
+		t.Errorf("batch is executed by %d parallel calls, want 2", max)
+	}
+
+	var exceeded response
+	if err := json.Unmarshal(postBatch(t, address, 0, 0, 0, 0, 0, 0), &exceeded); err != nil {
+		t.Fatal(err)
+	}
+	if exceeded.Error == nil || exceeded.Error.Code != -32600 || !strings.Contains(exceeded.Error.Message, "max 5") {
+		t.Errorf("batch of 6 requests is not rejected: %+v", exceeded.Error)
+	}
+}
+`
+
+// TestBatch checks, that jsonRPC batch is limited by size, executed by bounded pool and keeps order of responses.
+func TestBatch(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"batch_test.go":           batchCheck,
+		"interfaces/interface.go": batchServices,
+	}, nil, WithTracer(TracerNone))
+}
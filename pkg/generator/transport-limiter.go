@@ -0,0 +1,290 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-limiter.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+const (
+	limitKeyPrincipal = "principal"
+	limitKeyHeader    = "header"
+	limiterMaxKeys    = 10000
+)
+
+var rateUnits = map[string]float64{
+	"s": 1,
+	"m": 60,
+	"h": 3600,
+}
+
+// parseRate parses rate like '100/s', '10/m' or '1000/h' into calls per second, rate without unit is per second.
+func parseRate(value string) (perSecond float64, err error) {
+
+	tokens := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	unit := "s"
+	if len(tokens) == 2 {
+		unit = strings.TrimSpace(tokens[1])
+	}
+	var calls int
+	if calls, err = strconv.Atoi(strings.TrimSpace(tokens[0])); err != nil || calls <= 0 {
+		return 0, fmt.Errorf("invalid rate '%s', must be like '100/s', '10/m' or '1000/h'", value)
+	}
+	seconds, found := rateUnits[unit]
+	if !found {
+		return 0, fmt.Errorf("unknown rate unit '%s', must be one of s, m, h", unit)
+	}
+	return float64(calls) / seconds, nil
+}
+
+// rateLimit returns rate of method or interface annotation, burst is rounded up rate if it is not annotated.
+func (m method) rateLimit() (perSecond float64, burst int) {
+
+	if perSecond, _ = parseRate(m.tags.Value(tagRateLimit, m.svc.tags.Value(tagRateLimit))); perSecond <= 0 {
+		return 0, 0
+	}
+	if burst, _ = strconv.Atoi(m.tags.Value(tagBurst, m.svc.tags.Value(tagBurst))); burst <= 0 {
+		burst = int(math.Ceil(perSecond))
+	}
+	return
+}
+
+func (m method) maxConcurrent() (max int) {
+	max, _ = strconv.Atoi(m.tags.Value(tagMaxConcurrent, m.svc.tags.Value(tagMaxConcurrent)))
+	return
+}
+
+func (m method) limitKey() string {
+	return strings.TrimSpace(m.tags.Value(tagLimitKey, m.svc.tags.Value(tagLimitKey)))
+}
+
+func (m method) hasLimits() bool {
+
+	perSecond, _ := m.rateLimit()
+	return perSecond > 0 || m.maxConcurrent() > 0
+}
+
+func (svc *service) hasLimits() bool {
+
+	for _, method := range svc.methods {
+		if method.hasLimits() {
+			return true
+		}
+	}
+	return false
+}
+
+func (tr Transport) hasLimits() bool {
+
+	for _, serviceName := range tr.serviceKeys() {
+		if tr.services[serviceName].hasLimits() {
+			return true
+		}
+	}
+	return false
+}
+
+// limitHeader returns header name, when limiter of method is keyed by header.
+func (m method) limitHeader() string {
+
+	if tokens := strings.SplitN(m.limitKey(), ":", 2); len(tokens) == 2 && tokens[0] == limitKeyHeader {
+		return strings.TrimSpace(tokens[1])
+	}
+	return ""
+}
+
+// limitContext renders putting of header value into context of method, so limiter middleware can read it.
+func (m *method) limitContext() Code {
+
+	if header := m.limitHeader(); header != "" && m.hasLimits() {
+		var value Code = String().Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Lit(header)))
+		if m.svc.engine.isNetHTTP() {
+			value = m.svc.engine.requestHeader(Lit(header))
+		}
+		return Id("methodContext").Op("=").Qual(packageContext, "WithValue").Call(Id("methodContext"), Id("ctxKeyLimit").Values(), value)
+	}
+	return Null()
+}
+
+// limitRejection renders counting of rejected call, when it is collected by metrics.
+func (svc *service) limitRejection(method *method) Code {
+
+	if !svc.tags.Contains(tagMetrics) {
+		return Null()
+	}
+	return If(Id("http").Dot("metrics").Op("!=").Nil()).Block(
+		Id("http").Dot("metrics").Dot("rejections").Dot("With").Call(Lit("method"), Lit(method.lccName()), Lit("service"), Lit(svc.Name)).Dot("Add").Call(Lit(1)),
+	)
+}
+
+// limitMiddlewareFunc renders middleware of method, which rejects calls exceeding rate or number of concurrent calls.
+func (svc *service) limitMiddlewareFunc(ctx context.Context, method *method) Code {
+
+	ctxName := utils.ToLowerCamel(method.Args[0].Name)
+	errName := utils.ToLowerCamel(method.Results[len(method.Results)-1].Name)
+
+	return Func().Id("limitMiddleware"+svc.Name+method.Name).Params(Id("rate").Op("*").Id("rateLimiter"), Id("concurrency").Op("*").Id("concurrencyLimiter")).Params(Id("Middleware" + svc.Name + method.Name)).Block(
+		Return(Func().Params(Id(_next_).Id(svc.Name + method.Name)).Params(Id(svc.Name + method.Name)).Block(
+			Return(Func().Params(funcDefinitionParams(ctx, method.Args)).Params(funcDefinitionParams(ctx, method.Results)).BlockFunc(func(bg *Group) {
+
+				bg.Line()
+				switch key := method.limitKey(); {
+				case key == limitKeyPrincipal:
+					bg.Var().Id("key").String()
+					bg.If(List(Id("principal"), Id("ok")).Op(":=").Id("PrincipalFromContext").Call(Id(ctxName)).Op(";").Id("ok")).Block(
+						Id("key").Op("=").Id("principal").Dot("ID").Call(),
+					)
+				case method.limitHeader() != "":
+					bg.List(Id("key"), Id("_")).Op(":=").Id(ctxName).Dot("Value").Call(Id("ctxKeyLimit").Values()).Op(".").Call(String())
+				default:
+					bg.Id("key").Op(":=").Lit("")
+				}
+				bg.If(Op("!").Id("rate").Dot("allow").Call(Id("key")).Op("||").Op("!").Id("concurrency").Dot("acquire").Call(Id("key"))).Block(
+					Id(errName).Op("=").Id("ErrLimitExceeded"),
+					Return(),
+				)
+				bg.Defer().Id("concurrency").Dot("release").Call(Id("key"))
+				bg.Return(Id(_next_).Call(paramNames(method.Args)))
+			})),
+		)),
+	)
+}
+
+// limitersCode renders constructor of limiters from annotation of method.
+func (m *method) limitersCode() Code {
+
+	rate, concurrency := Nil(), Nil()
+	if perSecond, burst := m.rateLimit(); perSecond > 0 {
+		rate = Id("newRateLimiter").Call(Lit(perSecond), Lit(burst))
+	}
+	if max := m.maxConcurrent(); max > 0 {
+		concurrency = Id("newConcurrencyLimiter").Call(Lit(max))
+	}
+	return Id("limitMiddleware"+m.svc.Name+m.Name).Call(rate, concurrency)
+}
+
+// renderLimiter renders token bucket and counter of concurrent calls, both are kept for each key of limiter.
+// Buckets are kept in LRU order, so number of keys is bounded, counters are removed when calls are finished.
+func (tr Transport) renderLimiter(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.Const().Id("limiterMaxKeys").Op("=").Lit(limiterMaxKeys)
+
+	srcFile.Line().Comment("ctxKeyLimit is key of header value in context of method, which is key of limiter")
+	srcFile.Type().Id("ctxKeyLimit").Struct()
+
+	srcFile.Line().Comment("ErrLimitExceeded is returned for calls rejected by rate-limit or max-concurrent annotations")
+	srcFile.Var().Id("ErrLimitExceeded").Op("=").Qual(packageErrors, "New").Call(Lit("limit exceeded"))
+
+	srcFile.Line().Type().Id("tokenBucket").Struct(
+		Id("key").String(),
+		Id("tokens").Float64(),
+		Id("updated").Qual(packageTime, "Time"),
+	)
+
+	srcFile.Line().Type().Id("rateLimiter").Struct(
+		Id("lock").Qual(packageSync, "Mutex"),
+		Id("rate").Float64(),
+		Id("burst").Float64(),
+		Id("recent").Op("*").Qual(packageList, "List"),
+		Id("buckets").Map(String()).Op("*").Qual(packageList, "Element"),
+	)
+
+	srcFile.Line().Func().Id("newRateLimiter").Params(Id("rate").Float64(), Id("burst").Int()).Params(Op("*").Id("rateLimiter")).Block(
+		Return(Op("&").Id("rateLimiter").Values(Dict{
+			Id("rate"):    Id("rate"),
+			Id("burst"):   Float64().Call(Id("burst")),
+			Id("recent"):  Qual(packageList, "New").Call(),
+			Id("buckets"): Make(Map(String()).Op("*").Qual(packageList, "Element")),
+		})),
+	)
+
+	srcFile.Line().Add(tr.rateAllowFunc())
+
+	srcFile.Line().Type().Id("concurrencyLimiter").Struct(
+		Id("lock").Qual(packageSync, "Mutex"),
+		Id("max").Int(),
+		Id("active").Map(String()).Int(),
+	)
+
+	srcFile.Line().Func().Id("newConcurrencyLimiter").Params(Id("max").Int()).Params(Op("*").Id("concurrencyLimiter")).Block(
+		Return(Op("&").Id("concurrencyLimiter").Values(Dict{
+			Id("max"):    Id("max"),
+			Id("active"): Make(Map(String()).Int()),
+		})),
+	)
+
+	srcFile.Line().Func().Params(Id("l").Op("*").Id("concurrencyLimiter")).Id("acquire").Params(Id("key").String()).Bool().Block(
+		Line().If(Id("l").Op("==").Nil()).Block(
+			Return(True()),
+		),
+		Id("l").Dot("lock").Dot("Lock").Call(),
+		Defer().Id("l").Dot("lock").Dot("Unlock").Call(),
+		If(Id("l").Dot("active").Index(Id("key")).Op(">=").Id("l").Dot("max")).Block(
+			Return(False()),
+		),
+		Id("l").Dot("active").Index(Id("key")).Op("++"),
+		Return(True()),
+	)
+
+	srcFile.Line().Func().Params(Id("l").Op("*").Id("concurrencyLimiter")).Id("release").Params(Id("key").String()).Block(
+		Line().If(Id("l").Op("==").Nil()).Block(
+			Return(),
+		),
+		Id("l").Dot("lock").Dot("Lock").Call(),
+		Defer().Id("l").Dot("lock").Dot("Unlock").Call(),
+		If(Id("l").Dot("active").Index(Id("key")).Op("--").Op(";").Id("l").Dot("active").Index(Id("key")).Op("<=").Lit(0)).Block(
+			Delete(Id("l").Dot("active"), Id("key")),
+		),
+	)
+
+	return srcFile.Save(path.Join(outDir, "limiter.go"))
+}
+
+// rateAllowFunc renders taking of token from bucket of key, bucket of least recently used key is dropped when there are too many keys.
+func (tr Transport) rateAllowFunc() Code {
+
+	return Func().Params(Id("l").Op("*").Id("rateLimiter")).Id("allow").Params(Id("key").String()).Bool().Block(
+
+		Line().If(Id("l").Op("==").Nil()).Block(
+			Return(True()),
+		),
+		Id("l").Dot("lock").Dot("Lock").Call(),
+		Defer().Id("l").Dot("lock").Dot("Unlock").Call(),
+
+		Line().Id("now").Op(":=").Qual(packageTime, "Now").Call(),
+		List(Id("element"), Id("found")).Op(":=").Id("l").Dot("buckets").Index(Id("key")),
+		If(Id("found")).Block(
+			Id("l").Dot("recent").Dot("MoveToFront").Call(Id("element")),
+		).Else().Block(
+			If(Id("l").Dot("recent").Dot("Len").Call().Op(">=").Id("limiterMaxKeys")).Block(
+				Id("oldest").Op(":=").Id("l").Dot("recent").Dot("Back").Call(),
+				Id("l").Dot("recent").Dot("Remove").Call(Id("oldest")),
+				Delete(Id("l").Dot("buckets"), Id("oldest").Dot("Value").Op(".").Call(Op("*").Id("tokenBucket")).Dot("key")),
+			),
+			Id("element").Op("=").Id("l").Dot("recent").Dot("PushFront").Call(Op("&").Id("tokenBucket").Values(Dict{Id("key"): Id("key"), Id("tokens"): Id("l").Dot("burst"), Id("updated"): Id("now")})),
+			Id("l").Dot("buckets").Index(Id("key")).Op("=").Id("element"),
+		),
+		Id("bucket").Op(":=").Id("element").Dot("Value").Op(".").Call(Op("*").Id("tokenBucket")),
+		Id("bucket").Dot("tokens").Op("=").Qual(packageMath, "Min").Call(Id("l").Dot("burst"), Id("bucket").Dot("tokens").Op("+").Id("now").Dot("Sub").Call(Id("bucket").Dot("updated")).Dot("Seconds").Call().Op("*").Id("l").Dot("rate")),
+		Id("bucket").Dot("updated").Op("=").Id("now"),
+		If(Id("bucket").Dot("tokens").Op("<").Lit(1)).Block(
+			Return(False()),
+		),
+		Id("bucket").Dot("tokens").Op("--"),
+		Return(True()),
+	)
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-limiter_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const limiterServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files
+	// @tg rate-limit=1/m burst=2
+	// @tg limit-key=header:X-Client-Id
+	Get(ctx context.Context) (name string, err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/slow
+	// @tg max-concurrent=1
+	Slow(ctx context.Context) (err error)
+}
+
+// @tg jsonRPC-server
+type Calc interface {
+	// @tg rate-limit=1/h burst=1
+	Add(ctx context.Context, first int, second int) (sum int, err error)
+}
+`
+
+const limiterCheck = `package gentest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/transport"
+)
+
+type files struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (files) Get(ctx context.Context) (string, error) {
+	return "file", nil
+}
+
+func (f files) Slow(ctx context.Context) error {
+	f.entered <- struct{}{}
+	<-f.release
+	return nil
+}
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, first int, second int) (int, error) {
+	return first + second, nil
+}
+
+func get(t *testing.T, url, client string) int {
+
+	t.Helper()
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("X-Client-Id", client)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	return response.StatusCode
+}
+
+func TestLimits(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := files{entered: make(chan struct{}), release: make(chan struct{})}
+	address := freeAddress(t)
+	srv := transport.New(log,
+		transport.Files(transport.NewFiles(log, svc)),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+	url := "http://" + address
+
+	var statuses []int
+	for _, client := range []string{"first", "first", "first", "second"} {
+		statuses = append(statuses, get(t, url+"/files", client))
+	}
+	if statuses[0] != http.StatusOK || statuses[1] != http.StatusOK || statuses[2] != http.StatusTooManyRequests || statuses[3] != http.StatusOK {
+		t.Errorf("unexpected statuses of rate limit %v", statuses)
+	}
+
+	done := make(chan int)
+	go func() { done <- get(t, url+"/slow", "") }()
+	<-svc.entered
+	if status := get(t, url+"/slow", ""); status != http.StatusTooManyRequests {
+		t.Errorf("unexpected status of concurrent call %d", status)
+	}
+	close(svc.release)
+	if status := <-done; status != http.StatusOK {
+		t.Errorf("unexpected status of slow call %d", status)
+	}
+	go func() { <-svc.entered }()
+	if status := get(t, url+"/slow", ""); status != http.StatusOK {
+		t.Errorf("concurrency is not released, status %d", status)
+	}
+
+	add := ` + "`" + `{"jsonrpc":"2.0","id":1,"method":"calc.add","params":{"first":1,"second":2}}` + "`" + `
+	var bodies []string
+	for i := 0; i < 2; i++ {
+		response, err := http.Post(url+"/", "application/json", strings.NewReader("["+add+"]"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(response.Body)
+		_ = response.Body.Close()
+		bodies = append(bodies, body.String())
+	}
+	if !strings.Contains(bodies[0], ` + "`" + `"sum":3` + "`" + `) || !strings.Contains(bodies[1], ` + "`" + `"code":-32004` + "`" + `) {
+		t.Errorf("unexpected responses of rate limit %q", bodies)
+	}
+}
+`
+
+// limiterEviction checks, that rate limiter keeps limited number of recent keys
+const limiterEviction = `package transport
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestLimiterEviction(t *testing.T) {
+
+	limiter := newRateLimiter(0, 1)
+	if !limiter.allow("first") || limiter.allow("first") {
+		t.Fatal("rate of key is not limited")
+	}
+	for i := 0; i < limiterMaxKeys-1; i++ {
+		limiter.allow(strconv.Itoa(i))
+	}
+	if limiter.allow("first") {
+		t.Fatal("recent key is evicted")
+	}
+	limiter.allow("next")
+	if len(limiter.buckets) != limiterMaxKeys || limiter.recent.Len() != limiterMaxKeys {
+		t.Fatalf("limiter keeps %d keys", len(limiter.buckets))
+	}
+	if !limiter.allow("0") {
+		t.Error("oldest key is not evicted")
+	}
+	if limiter.allow("first") {
+		t.Error("recently used key is evicted")
+	}
+}
+`
+
+// TestLimiter checks responses to calls over limits and eviction of limiter keys.
+func TestLimiter(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go":         limiterServices,
+		"limiter_test.go":                 limiterCheck,
+		"transport/limiter_evict_test.go": limiterEviction,
+	}, nil, WithTracer(TracerNone))
+}
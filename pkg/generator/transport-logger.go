@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-logger.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	logMaxBytes  = 64
+	logMaxString = 1024
+	logMaxDepth  = 16
+)
+
+var logLevels = map[string]string{
+	"debug": "Debug",
+	"info":  "Info",
+	"warn":  "Warn",
+	"error": "Error",
+}
+
+func (tr Transport) hasLogger() bool {
+
+	for _, serviceName := range tr.serviceKeys() {
+		if tr.services[serviceName].tags.Contains(tagLogger) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderLogger renders copying of logged values, which hides fields tagged `log:"-"` and truncates large strings and bytes.
+func (tr Transport) renderLogger(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.Const().Defs(
+		Id("logMask").Op("=").Lit("*****"),
+		Id("logMaxBytes").Op("=").Lit(logMaxBytes),
+		Id("logMaxString").Op("=").Lit(logMaxString),
+		Id("logMaxDepth").Op("=").Lit(logMaxDepth),
+	)
+
+	srcFile.Line().Func().Id("redactLog").Params(Id("value").Interface()).Params(Interface()).Block(
+		If(Id("value").Op("==").Nil()).Block(
+			Return(Nil()),
+		),
+		Return(Id("redactValue").Call(Qual(packageReflect, "ValueOf").Call(Id("value")), Lit(0)).Dot("Interface").Call()),
+	)
+
+	srcFile.Line().Add(tr.redactValueFunc())
+	srcFile.Line().Add(tr.redactStructFunc())
+
+	return srcFile.Save(path.Join(outDir, "logger.go"))
+}
+
+func (tr Transport) redactValueFunc() Code {
+
+	return Func().Id("redactValue").Params(Id("value").Qual(packageReflect, "Value"), Id("depth").Int()).Params(Qual(packageReflect, "Value")).Block(
+
+		Line().If(Id("depth").Op(">").Id("logMaxDepth")).Block(
+			Return(Id("value")),
+		),
+		Switch(Id("value").Dot("Kind").Call()).Block(
+
+			Case(Qual(packageReflect, "Ptr"), Qual(packageReflect, "Interface")).Block(
+				If(Id("value").Dot("IsNil").Call()).Block(
+					Return(Id("value")),
+				),
+				Id("elem").Op(":=").Id("redactValue").Call(Id("value").Dot("Elem").Call(), Id("depth").Op("+").Lit(1)),
+				If(Id("value").Dot("Kind").Call().Op("==").Qual(packageReflect, "Ptr")).Block(
+					Id("result").Op(":=").Qual(packageReflect, "New").Call(Id("elem").Dot("Type").Call()),
+					Id("result").Dot("Elem").Call().Dot("Set").Call(Id("elem")),
+					Return(Id("result")),
+				),
+				Id("result").Op(":=").Qual(packageReflect, "New").Call(Id("value").Dot("Type").Call()).Dot("Elem").Call(),
+				Id("result").Dot("Set").Call(Id("elem")),
+				Return(Id("result")),
+			),
+			Case(Qual(packageReflect, "String")).Block(
+				If(Id("value").Dot("Len").Call().Op("<=").Id("logMaxString")).Block(
+					Return(Id("value")),
+				),
+				Id("result").Op(":=").Qual(packageReflect, "New").Call(Id("value").Dot("Type").Call()).Dot("Elem").Call(),
+				Id("result").Dot("SetString").Call(Qual(packageFmt, "Sprintf").Call(Lit("%s...(%d bytes)"), Id("value").Dot("String").Call().Index(Op(":").Id("logMaxString")), Id("value").Dot("Len").Call())),
+				Return(Id("result")),
+			),
+			Case(Qual(packageReflect, "Slice")).Block(
+				If(Id("value").Dot("IsNil").Call()).Block(
+					Return(Id("value")),
+				),
+				If(Id("value").Dot("Type").Call().Dot("Elem").Call().Dot("Kind").Call().Op("==").Qual(packageReflect, "Uint8")).Block(
+					If(Id("value").Dot("Len").Call().Op(">").Id("logMaxBytes")).Block(
+						Return(Id("value").Dot("Slice").Call(Lit(0), Id("logMaxBytes"))),
+					),
+					Return(Id("value")),
+				),
+				Id("result").Op(":=").Qual(packageReflect, "MakeSlice").Call(Id("value").Dot("Type").Call(), Id("value").Dot("Len").Call(), Id("value").Dot("Len").Call()),
+				For(Id("i").Op(":=").Lit(0).Op(";").Id("i").Op("<").Id("value").Dot("Len").Call().Op(";").Id("i").Op("++")).Block(
+					Id("result").Dot("Index").Call(Id("i")).Dot("Set").Call(Id("redactValue").Call(Id("value").Dot("Index").Call(Id("i")), Id("depth").Op("+").Lit(1))),
+				),
+				Return(Id("result")),
+			),
+			Case(Qual(packageReflect, "Map")).Block(
+				If(Id("value").Dot("IsNil").Call()).Block(
+					Return(Id("value")),
+				),
+				Id("result").Op(":=").Qual(packageReflect, "MakeMapWithSize").Call(Id("value").Dot("Type").Call(), Id("value").Dot("Len").Call()),
+				Id("iter").Op(":=").Id("value").Dot("MapRange").Call(),
+				For(Id("iter").Dot("Next").Call()).Block(
+					Id("result").Dot("SetMapIndex").Call(Id("iter").Dot("Key").Call(), Id("redactValue").Call(Id("iter").Dot("Value").Call(), Id("depth").Op("+").Lit(1))),
+				),
+				Return(Id("result")),
+			),
+			Case(Qual(packageReflect, "Struct")).Block(
+				Return(Id("redactStruct").Call(Id("value"), Id("depth"))),
+			),
+		),
+		Return(Id("value")),
+	)
+}
+
+// redactStructFunc renders copying of struct, unexported fields are copied as is.
+func (tr Transport) redactStructFunc() Code {
+
+	return Func().Id("redactStruct").Params(Id("value").Qual(packageReflect, "Value"), Id("depth").Int()).Params(Qual(packageReflect, "Value")).Block(
+
+		Line().Id("result").Op(":=").Qual(packageReflect, "New").Call(Id("value").Dot("Type").Call()).Dot("Elem").Call(),
+		Id("result").Dot("Set").Call(Id("value")),
+
+		Line().For(Id("i").Op(":=").Lit(0).Op(";").Id("i").Op("<").Id("value").Dot("NumField").Call().Op(";").Id("i").Op("++")).Block(
+
+			Line().Id("field").Op(":=").Id("value").Dot("Type").Call().Dot("Field").Call(Id("i")),
+			If(Id("field").Dot("PkgPath").Op("!=").Lit("")).Block(
+				Continue(),
+			),
+			If(Id("field").Dot("Tag").Dot("Get").Call(Lit("log")).Op("==").Lit("-")).Block(
+				If(Id("field").Dot("Type").Dot("Kind").Call().Op("==").Qual(packageReflect, "String")).Block(
+					Id("result").Dot("Field").Call(Id("i")).Dot("SetString").Call(Id("logMask")),
+					Continue(),
+				),
+				Id("result").Dot("Field").Call(Id("i")).Dot("Set").Call(Qual(packageReflect, "Zero").Call(Id("field").Dot("Type"))),
+				Continue(),
+			),
+			Id("result").Dot("Field").Call(Id("i")).Dot("Set").Call(Id("redactValue").Call(Id("value").Dot("Field").Call(Id("i")), Id("depth").Op("+").Lit(1))),
+		),
+		Return(Id("result")),
+	)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-logger_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const loggerTypes = `package types
+
+type Credentials struct {
+	Login    string ` + "`json:\"login\"`" + `
+	Password string ` + "`json:\"password\" log:\"-\"`" + `
+}
+`
+
+const loggerServices = `package interfaces
+
+import (
+	"context"
+
+	"gentest/types"
+)
+
+// @tg jsonRPC-server log
+type Vault interface {
+	// @tg log-skip=token log-level=debug log-error-level=warn
+	Login(ctx context.Context, credentials types.Credentials, note string) (token string, err error)
+}
+`
+
+const loggerCheck = `package gentest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"gentest/clients"
+	"gentest/transport"
+	"gentest/types"
+)
+
+type vault struct{}
+
+func (vault) Login(ctx context.Context, credentials types.Credentials, note string) (token string, err error) {
+	if credentials.Login == "" {
+		return "", errors.New("login is empty")
+	}
+	return "token-value", nil
+}
+
+func TestLogger(t *testing.T) {
+
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.DebugLevel)
+	address := freeAddress(t)
+	srv := transport.New(log, transport.Vault(transport.NewVault(log, vault{}))).WithLog(log)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+
+	cli := clients.New("vault", log, "http://"+address).Vault()
+	note := strings.Repeat("n", 2000)
+	hook.Reset()
+	if _, err := cli.Login(context.Background(), types.Credentials{Login: "admin", Password: "secret-password"}, note); err != nil {
+		t.Fatal(err)
+	}
+	var entry *logrus.Entry
+	for _, logged := range hook.AllEntries() {
+		if logged.Message == "call login" {
+			entry = logged
+		}
+	}
+	if entry == nil {
+		t.Fatal("call is not logged")
+	}
+	if entry.Level != logrus.DebugLevel {
+		t.Errorf("successful call is logged with level %s", entry.Level)
+	}
+	request, response := fmt.Sprint(entry.Data["request"]), fmt.Sprint(entry.Data["response"])
+	if strings.Contains(request, "secret-password") || !strings.Contains(request, "*****") || !strings.Contains(request, "admin") {
+		t.Errorf("password is not masked: %s", request)
+	}
+	if strings.Contains(request, note) || !strings.Contains(request, "(2000 bytes)") {
+		t.Errorf("long string is not truncated: %.100s", request)
+	}
+	if strings.Contains(response, "token-value") {
+		t.Errorf("skipped result is logged: %s", response)
+	}
+
+	hook.Reset()
+	if _, err := cli.Login(context.Background(), types.Credentials{}, ""); err == nil {
+		t.Fatal("error of method is not returned")
+	}
+	for _, logged := range hook.AllEntries() {
+		if logged.Message == "call login" && logged.Level != logrus.WarnLevel {
+			t.Errorf("failed call is logged with level %s", logged.Level)
+		}
+	}
+}
+`
+
+// TestLogger checks masked fields, truncated values, skipped results and levels of logging middleware.
+func TestLogger(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": loggerServices,
+		"logger_test.go":          loggerCheck,
+		"types/types.go":          loggerTypes,
+	}, nil, WithTracer(TracerNone))
+}
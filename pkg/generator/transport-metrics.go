@@ -10,6 +10,28 @@ import (
 	. "github.com/dave/jennifer/jen"
 )
 
+type metricDef struct {
+	field   string
+	kind    string
+	name    string
+	help    string
+	buckets string
+	labels  []string
+}
+
+var metricDefs = []metricDef{
+	{field: "requestCount", kind: "Counter", name: "count", help: "Number of requests received", labels: []string{"method", "service", "success"}},
+	{field: "requestCountAll", kind: "Counter", name: "all_count", help: "Number of all requests received", labels: []string{"method", "service"}},
+	{field: "requestLatency", kind: "Histogram", name: "latency_seconds", help: "Duration of requests in seconds", buckets: "Buckets", labels: []string{"method", "service", "success"}},
+	{field: "requestsInFlight", kind: "Gauge", name: "in_flight", help: "Number of requests in progress", labels: []string{"method", "service"}},
+	{field: "requestSize", kind: "Histogram", name: "request_size_bytes", help: "Size of HTTP request bodies in bytes", buckets: "SizeBuckets", labels: []string{"method", "service"}},
+	{field: "responseSize", kind: "Histogram", name: "response_size_bytes", help: "Size of HTTP response bodies in bytes", buckets: "SizeBuckets", labels: []string{"method", "service"}},
+	{field: "httpResponses", kind: "Counter", name: "http_responses_count", help: "Number of HTTP responses by status code", labels: []string{"method", "service", "code"}},
+	{field: "jsonRPCResponses", kind: "Counter", name: "jsonrpc_responses_count", help: "Number of jsonRPC responses by error code", labels: []string{"method", "service", "code"}},
+	{field: "panics", kind: "Counter", name: "panics_count", help: "Number of recovered panics of methods", labels: []string{"method", "service"}},
+	{field: "rejections", kind: "Counter", name: "rejections_count", help: "Number of calls rejected by rate or concurrency limits", labels: []string{"method", "service"}},
+}
+
 func (tr Transport) renderMetrics(outDir string) (err error) {
 
 	srcFile := newSrc(filepath.Base(outDir))
@@ -19,71 +41,147 @@ func (tr Transport) renderMetrics(outDir string) (err error) {
 	srcFile.ImportAlias(packageKitPrometheus, "kitPrometheus")
 	srcFile.ImportAlias(packageStdPrometheus, "stdPrometheus")
 
-	srcFile.ImportName(packageFastHttp, "fasthttp")
 	srcFile.ImportName(packageGoKitMetrics, "metrics")
-	srcFile.ImportName(packageGoKitEndpoint, "endpoint")
 	srcFile.ImportName(packagePrometheusHttp, "promhttp")
-	srcFile.ImportName(packageFastHttpAdapt, "fasthttpadaptor")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+		srcFile.ImportName(packageFastHttpAdapt, "fasthttpadaptor")
+	}
+
+	srcFile.Line().Add(tr.metricsConfigType())
+	srcFile.Line().Add(tr.metricsType())
+	srcFile.Line().Add(tr.newMetricsFunc())
+	srcFile.Line().Add(tr.registerCollectorFunc())
+	srcFile.Line().Add(tr.configureMetricsFunc())
+	srcFile.Line().Add(tr.serveMetricsFunc())
+	if tr.hasJsonRPC {
+		srcFile.Line().Add(tr.observeBatchFunc())
+	}
 
-	srcFile.Line().Var().Id("srvMetrics").Op("*").Qual(packageFastHttp, "Server")
+	return srcFile.Save(path.Join(outDir, "metrics.go"))
+}
 
-	srcFile.Line().Add(prometheusCounterRequestCount())
-	srcFile.Line().Add(prometheusCounterRequestCountAll())
-	srcFile.Line().Add(prometheusSummaryRequestCount())
+func (tr Transport) metricsConfigType() Code {
 
-	srcFile.Line().Add(tr.serveMetricsFunc())
+	return Type().Id("MetricsConfig").Struct(
+		Id("Namespace").String(),
+		Id("Subsystem").String(),
+		Id("ConstLabels").Qual(packageStdPrometheus, "Labels"),
+		Id("Buckets").Op("[]").Float64(),
+		Id("SizeBuckets").Op("[]").Float64(),
+		Id("Registerer").Qual(packageStdPrometheus, "Registerer"),
+	)
+}
 
-	return srcFile.Save(path.Join(outDir, "metrics.go"))
+func (tr Transport) metricsType() Code {
+
+	return Type().Id("Metrics").StructFunc(func(g *Group) {
+		g.Id("gatherer").Qual(packageStdPrometheus, "Gatherer")
+		g.Line()
+		for _, def := range metricDefs {
+			g.Id(def.field).Qual(packageGoKitMetrics, def.kind)
+		}
+	})
 }
 
-func (tr Transport) serveMetricsFunc() Code {
+// newMetricsFunc renders constructor of collectors, which are registered in registerer of config.
+// Collectors registered before with the same options are reused, so servers of one process share them.
+func (tr Transport) newMetricsFunc() Code {
+
+	return Func().Id("NewMetrics").Params(Id("config").Id("MetricsConfig")).Params(Id("m").Op("*").Id("Metrics"), Err().Error()).BlockFunc(func(bg *Group) {
+
+		bg.Line().If(Id("config").Dot("Namespace").Op("==").Lit("")).Block(
+			Id("config").Dot("Namespace").Op("=").Lit("service"),
+		)
+		bg.If(Id("config").Dot("Subsystem").Op("==").Lit("")).Block(
+			Id("config").Dot("Subsystem").Op("=").Lit("requests"),
+		)
+		bg.If(Len(Id("config").Dot("Buckets")).Op("==").Lit(0)).Block(
+			Id("config").Dot("Buckets").Op("=").Qual(packageStdPrometheus, "DefBuckets"),
+		)
+		bg.If(Len(Id("config").Dot("SizeBuckets")).Op("==").Lit(0)).Block(
+			Id("config").Dot("SizeBuckets").Op("=").Qual(packageStdPrometheus, "ExponentialBuckets").Call(Lit(64), Lit(4), Lit(8)),
+		)
+		bg.If(Id("config").Dot("Registerer").Op("==").Nil()).Block(
+			Id("config").Dot("Registerer").Op("=").Qual(packageStdPrometheus, "DefaultRegisterer"),
+		)
+
+		bg.Line().Id("m").Op("=").Op("&").Id("Metrics").Values(Dict{Id("gatherer"): Qual(packageStdPrometheus, "DefaultGatherer")})
+		bg.If(List(Id("gatherer"), Id("ok")).Op(":=").Id("config").Dot("Registerer").Assert(Qual(packageStdPrometheus, "Gatherer")).Op(";").Id("ok")).Block(
+			Id("m").Dot("gatherer").Op("=").Id("gatherer"),
+		)
+
+		bg.Line().Var().Id("collector").Qual(packageStdPrometheus, "Collector")
+		for _, def := range metricDefs {
+			opts := Dict{
+				Id("Name"):        Lit(def.name),
+				Id("Namespace"):   Id("config").Dot("Namespace"),
+				Id("Subsystem"):   Id("config").Dot("Subsystem"),
+				Id("Help"):        Lit(def.help),
+				Id("ConstLabels"): Id("config").Dot("ConstLabels"),
+			}
+			if def.buckets != "" {
+				opts[Id("Buckets")] = Id("config").Dot(def.buckets)
+			}
+			var labels []Code
+			for _, label := range def.labels {
+				labels = append(labels, Lit(label))
+			}
+			vec := Qual(packageStdPrometheus, "New"+def.kind+"Vec").Call(Qual(packageStdPrometheus, def.kind+"Opts").Values(opts), Index().String().Values(labels...))
+			bg.If(List(Id("collector"), Err()).Op("=").Id("registerCollector").Call(Id("config").Dot("Registerer"), vec).Op(";").Err().Op("!=").Nil()).Block(
+				Return(Nil(), Err()),
+			)
+			bg.Id("m").Dot(def.field).Op("=").Qual(packageKitPrometheus, "New"+def.kind).Call(Id("collector").Assert(Op("*").Qual(packageStdPrometheus, def.kind+"Vec")))
+		}
+		bg.Return()
+	})
+}
 
-	return Func().Id("ServeMetrics").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("address").String()).Block(
+func (tr Transport) registerCollectorFunc() Code {
 
-		Line().Id("srvMetrics").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
-			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
-			Id("Handler"):     Qual(packageFastHttpAdapt, "NewFastHTTPHandler").Call(Qual(packagePrometheusHttp, "Handler").Call()),
-		}),
+	return Func().Id("registerCollector").Params(Id("registerer").Qual(packageStdPrometheus, "Registerer"), Id("collector").Qual(packageStdPrometheus, "Collector")).Params(Qual(packageStdPrometheus, "Collector"), Error()).Block(
 
-		Line().Go().Func().Params().Block(
-			Err().Op(":=").Id("srvMetrics").Dot("ListenAndServe").Call(Id("address")),
-			Id("ExitOnError").Call(Id("log"), Err(), Lit("serve metrics on ").Op("+").Id("address")),
-		).Call(),
+		Line().If(Err().Op(":=").Id("registerer").Dot("Register").Call(Id("collector")).Op(";").Err().Op("!=").Nil()).Block(
+			Var().Id("registered").Qual(packageStdPrometheus, "AlreadyRegisteredError"),
+			If(Qual(packageErrors, "As").Call(Err(), Op("&").Id("registered"))).Block(
+				Return(Id("registered").Dot("ExistingCollector"), Nil()),
+			),
+			Return(Nil(), Err()),
+		),
+		Return(Id("collector"), Nil()),
 	)
 }
 
-func prometheusCounterRequestCount() (code *Statement) {
+func (tr Transport) configureMetricsFunc() Code {
 
-	return Var().Id("RequestCount").Op("=").Qual(packageKitPrometheus, "NewCounterFrom").Call(Qual(packageStdPrometheus, "CounterOpts").Values(
-		DictFunc(func(d Dict) {
-			d[Id("Name")] = Lit("count")
-			d[Id("Namespace")] = Lit("service")
-			d[Id("Subsystem")] = Lit("requests")
-			d[Id("Help")] = Lit("Number of requests received")
-		}),
-	), Index().String().Values(Lit("method"), Lit("service"), Lit("success")))
+	return Func().Id("ConfigureMetrics").Params(Id("config").Id("MetricsConfig")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("metricsConfig").Op("=").Id("config"),
+		)),
+	)
 }
 
-func prometheusCounterRequestCountAll() (code *Statement) {
+func (tr Transport) serveMetricsFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeMetrics").Params(Id("address").String()).Params(Err().Error()).Block(
+
+		Line().If(Id("srv").Dot("metrics").Op("==").Nil()).Block(
+			If(List(Id("srv").Dot("metrics"), Err()).Op("=").Id("NewMetrics").Call(Id("srv").Dot("metricsConfig")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			),
+		),
+		Id("srv").Dot("srvMetrics").Op("=").Add(tr.utilityServer(Qual(packagePrometheusHttp, "HandlerFor").Call(Id("srv").Dot("metrics").Dot("gatherer"), Qual(packagePrometheusHttp, "HandlerOpts").Values()))),
 
-	return Var().Id("RequestCountAll").Op("=").Qual(packageKitPrometheus, "NewCounterFrom").Call(Qual(packageStdPrometheus, "CounterOpts").Values(
-		DictFunc(func(d Dict) {
-			d[Id("Name")] = Lit("all_count")
-			d[Id("Namespace")] = Lit("service")
-			d[Id("Subsystem")] = Lit("requests")
-			d[Id("Help")] = Lit("Number of all requests received")
-		}),
-	), Index().String().Values(Lit("method"), Lit("service")))
+		Line().Return(Id("srv").Dot("serve").Call(Id("address"), Lit("metrics"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+			Return(Id("srv").Dot("srvMetrics").Dot("Serve").Call(Id("listener"))),
+		))),
+	)
 }
 
-func prometheusSummaryRequestCount() (code *Statement) {
+// observeBatchFunc renders collecting of HTTP metrics for batch of server, which may contain methods of several services.
+func (tr Transport) observeBatchFunc() Code {
 
-	return Var().Id("RequestLatency").Op("=").Qual(packageKitPrometheus, "NewSummaryFrom").Call(Qual(packageStdPrometheus, "SummaryOpts").Values(
-		DictFunc(func(d Dict) {
-			d[Id("Name")] = Lit("latency_microseconds")
-			d[Id("Namespace")] = Lit("service")
-			d[Id("Subsystem")] = Lit("requests")
-			d[Id("Help")] = Lit("Total duration of requests in microseconds")
-		}),
-	), Index().String().Values(Lit("method"), Lit("service"), Lit("success")))
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("observeBatch").Params(tr.engine.handlerParams()...).Block(
+		observeHTTPCode(tr.engine, Id("srv").Dot("metrics"), Lit("batch"), Lit(""))...,
+	)
 }
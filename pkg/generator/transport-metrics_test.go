@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-metrics_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const metricsServices = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server metrics
+type Calc interface {
+	Add(ctx context.Context, a int, b int) (c int, err error)
+}
+`
+
+const metricsCheck = `package gentest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/transport"
+)
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, a int, b int) (c int, err error) {
+	return a + b, nil
+}
+
+func TestMetrics(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	registry := prometheus.NewRegistry()
+	// WithMetrics is chained to constructor of server
+	srv := transport.New(log,
+		transport.ConfigureMetrics(transport.MetricsConfig{Registerer: registry}),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	).WithMetrics()
+	address, metricsAddress := freeAddress(t), freeAddress(t)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.ServeMetrics(metricsAddress); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+	waitServing(t, metricsAddress)
+
+	cli := clients.New("calc", log, "http://"+address).Calc()
+	for i := 0; i < 2; i++ {
+		if _, err := cli.Add(context.Background(), 1, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	response, err := http.Get("http://" + metricsAddress + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	for _, want := range []string{"service_requests_count{", "service_requests_latency_seconds_bucket{", "service_requests_in_flight{"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsRegistrationError(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "service_requests_count", Help: "other metric"}))
+
+	srv := transport.New(log,
+		transport.ConfigureMetrics(transport.MetricsConfig{Registerer: registry}),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	).WithMetrics()
+	if srv == nil {
+		t.Fatal("WithMetrics does not return server")
+	}
+	if err := srv.ServeMetrics(freeAddress(t)); err == nil {
+		t.Error("ServeMetrics does not return error of registration")
+	}
+}
+`
+
+// TestMetrics checks, that chained WithMetrics collects metrics of methods and ServeMetrics reports error of registration.
+func TestMetrics(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": metricsServices,
+		"metrics_test.go":         metricsCheck,
+	}, nil, WithTracer(TracerNone))
+}
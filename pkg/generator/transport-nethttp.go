@@ -0,0 +1,401 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-nethttp.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+const (
+	EngineFastHTTP = "fasthttp"
+	EngineNetHTTP  = "nethttp"
+)
+
+func isKnownEngine(engine string) bool {
+	return engine == EngineFastHTTP || engine == EngineNetHTTP
+}
+
+// httpEngine renders engine specific statements of handlers and clients.
+// Handlers of fasthttp take ctx, handlers of net/http take w and r.
+type httpEngine string
+
+func (e httpEngine) isNetHTTP() bool {
+	return e == EngineNetHTTP
+}
+
+func (tr Transport) isNetHTTP() bool {
+	return tr.engine.isNetHTTP()
+}
+
+func (tr Transport) engineError() error {
+
+	if !isKnownEngine(string(tr.engine)) {
+		return fmt.Errorf("unknown HTTP engine '%s'", tr.engine)
+	}
+	return nil
+}
+
+// importHttp renames net/http in files of services, because receiver of handlers is named 'http'.
+func (e httpEngine) importHttp(srcFile srcFile) {
+
+	if e.isNetHTTP() {
+		srcFile.ImportAlias(packageHttp, "nethttp")
+	}
+}
+
+func (e httpEngine) handlerParams() []Code {
+
+	if e.isNetHTTP() {
+		return []Code{Id("w").Qual(packageHttp, "ResponseWriter"), Id("r").Op("*").Qual(packageHttp, "Request")}
+	}
+	return []Code{Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")}
+}
+
+func (e httpEngine) handlerArgs() []Code {
+
+	if e.isNetHTTP() {
+		return []Code{Id("w"), Id("r")}
+	}
+	return []Code{Id(_ctx_)}
+}
+
+// request renders request of handler, which is passed to extraction of span and auth.
+func (e httpEngine) request() Code {
+
+	if e.isNetHTTP() {
+		return Id("r")
+	}
+	return Id(_ctx_)
+}
+
+func (e httpEngine) requestParam() Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Op("*").Qual(packageHttp, "Request")
+	}
+	return Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")
+}
+
+// cancelRequest renders value of CtxCancelRequest, which is set by before hooks to stop handling of request.
+func (e httpEngine) cancelRequest() Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("Context").Call().Dot("Value").Call(Id("CtxCancelRequest"))
+	}
+	return Id(_ctx_).Dot("Value").Call(Id("CtxCancelRequest"))
+}
+
+func (e httpEngine) sendResponse(log, resp Code) Code {
+	return Id("sendResponse").Call(append(append([]Code{log}, e.handlerArgs()...), resp)...)
+}
+
+func (e httpEngine) handlerType() Code {
+
+	if e.isNetHTTP() {
+		return Qual(packageHttp, "HandlerFunc")
+	}
+	return Qual(packageFastHttp, "RequestHandler")
+}
+
+func (e httpEngine) status(name string) Code {
+
+	if e.isNetHTTP() {
+		return Qual(packageHttp, name)
+	}
+	return Qual(packageFastHttp, name)
+}
+
+func (e httpEngine) statusText(code Code) Code {
+
+	if e.isNetHTTP() {
+		return Qual(packageHttp, "StatusText").Call(code)
+	}
+	return Qual(packageFastHttp, "StatusMessage").Call(code)
+}
+
+func (e httpEngine) requestMethod() Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("Method")
+	}
+	return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Method").Call())
+}
+
+func (e httpEngine) requestPath() Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("URL").Dot("Path")
+	}
+	return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("URI").Call().Dot("Path").Call())
+}
+
+func (e httpEngine) requestHeader(name Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("Header").Dot("Get").Call(name)
+	}
+	return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(name))
+}
+
+func (e httpEngine) requestCookie(name Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("cookieValue").Call(Id("r"), name)
+	}
+	return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Cookie").Call(name))
+}
+
+func (e httpEngine) queryArg(name Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("URL").Dot("Query").Call().Dot("Get").Call(name)
+	}
+	return Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("QueryArgs").Call().Dot("Peek").Call(name))
+}
+
+func (e httpEngine) pathValue(name Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("r").Dot("PathValue").Call(name)
+	}
+	return Id(_ctx_).Dot("UserValue").Call(name).Op(".").Call(String())
+}
+
+func (e httpEngine) responseStatus() Code {
+
+	if e.isNetHTTP() {
+		return Id("statusOf").Call(Id("w"))
+	}
+	return Id(_ctx_).Dot("Response").Dot("StatusCode").Call()
+}
+
+func (e httpEngine) setStatus(code Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("w").Dot("WriteHeader").Call(code)
+	}
+	return Id(_ctx_).Dot("SetStatusCode").Call(code)
+}
+
+// responseHeader renders call of method of response header, like Set or Add.
+func (e httpEngine) responseHeader(op string, args ...Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("w").Dot("Header").Call().Dot(op).Call(args...)
+	}
+	return Id(_ctx_).Dot("Response").Dot("Header").Dot(op).Call(args...)
+}
+
+func (e httpEngine) writeString(text Code) Code {
+
+	if e.isNetHTTP() {
+		return Qual(packageIO, "WriteString").Call(Id("w"), text)
+	}
+	return Id(_ctx_).Dot("WriteString").Call(text)
+}
+
+// bodyErrorStatus renders status of error of request body, body of net/http request is limited by reader,
+// so only its error means body over limit.
+func (e httpEngine) bodyErrorStatus() Code {
+
+	if e.isNetHTTP() {
+		return Id("bodyErrorStatus").Call(Err())
+	}
+	return Qual(packageFastHttp, "StatusBadRequest")
+}
+
+func (e httpEngine) contentType(value Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Content-Type"), value)
+	}
+	return Id(_ctx_).Dot("SetContentType").Call(value)
+}
+
+// decodeBody renders decoding of JSON body of request, body of net/http request is decoded from stream.
+func (e httpEngine) decodeBody(target Code) Code {
+
+	if e.isNetHTTP() {
+		return Qual(packageJson, "NewDecoder").Call(Id("r").Dot("Body")).Dot("Decode").Call(target)
+	}
+	return Qual(packageJson, "Unmarshal").Call(Id(_ctx_).Dot("PostBody").Call(), target)
+}
+
+// bodyTooLarge renders status of response to jsonRPC request with body over limit,
+// other decoding errors are replied by jsonRPC error.
+func (e httpEngine) bodyTooLarge() Code {
+
+	if !e.isNetHTTP() {
+		return Null()
+	}
+	return If(Id("bodyErrorStatus").Call(Err()).Op("==").Qual(packageHttp, "StatusRequestEntityTooLarge")).Block(
+		Id("w").Dot("WriteHeader").Call(Qual(packageHttp, "StatusRequestEntityTooLarge")),
+	)
+}
+
+// requestBody renders arguments of decodeBatch.
+func (e httpEngine) requestBody() []Code {
+
+	if e.isNetHTTP() {
+		return e.handlerArgs()
+	}
+	return []Code{Id(_ctx_).Dot("PostBody").Call()}
+}
+
+// replyError renders replacing of response by error, like ctx.Error of fasthttp.
+func (e httpEngine) replyError(text, code Code) Code {
+
+	if e.isNetHTTP() {
+		return Id("replyError").Call(Id("w"), text, code)
+	}
+	return Id(_ctx_).Dot("Error").Call(text, code)
+}
+
+// route renders registration of handler, routes of net/http are patterns of http.ServeMux,
+// responses of routes are buffered, so they may be changed by hooks of server.
+func (e httpEngine) route(router Code, httpMethod, urlPath string, handler Code) Code {
+
+	if e.isNetHTTP() {
+		// pattern with trailing slash matches subtree of path
+		if strings.HasSuffix(urlPath, "/") {
+			urlPath += "{$}"
+		}
+		return Add(router).Dot("HandleFunc").Call(Lit(httpMethod+" "+urlPath), Id("buffered").Call(handler))
+	}
+	return Add(router).Dot(httpMethod).Call(Lit(urlPath), handler)
+}
+
+func (e httpEngine) routerType() Code {
+
+	if e.isNetHTTP() {
+		return Op("*").Qual(packageHttp, "ServeMux")
+	}
+	return Op("*").Qual(packageFastHttpRouter, "Router")
+}
+
+// renderNetHTTP renders helpers of net/http handlers: buffered response, which is sent after hooks of server
+// like response of fasthttp, and errors of request body.
+func (tr Transport) renderNetHTTP(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.Line().Comment("Handler returns routes of server as http.Handler, so it may be wrapped by net/http middlewares or tested by httptest")
+	srcFile.Func().Params(Id("srv").Op("*").Id("Server")).Id("Handler").Params().Params(Qual(packageHttp, "Handler")).Block(
+		Return(Id("srv").Dot("httpHandler").Call()),
+	)
+
+	srcFile.Line().Comment("httpServer returns server of handler, contexts of requests are canceled by client disconnect or by deadline of Shutdown")
+	srcFile.Func().Params(Id("srv").Op("*").Id("Server")).Id("httpServer").Params(Id("handler").Qual(packageHttp, "Handler")).Params(Op("*").Qual(packageHttp, "Server")).Block(
+		Return(Op("&").Qual(packageHttp, "Server").Values(Dict{
+			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
+			Id("Handler"):     Id("handler"),
+			Id("BaseContext"): Func().Params(Qual(packageNet, "Listener")).Params(Qual(packageContext, "Context")).Block(
+				Return(Id("srv").Dot("ctx")),
+			),
+		})),
+	)
+
+	srcFile.Line().Comment("responseWriter buffers response, so status, headers and body may be changed after handler.")
+	srcFile.Comment("Flush sends buffered response and switches writer to streaming.")
+	srcFile.Type().Id("responseWriter").Struct(
+		Qual(packageHttp, "ResponseWriter"),
+		Id("statusCode").Int(),
+		Id("body").Qual(packageBytes, "Buffer"),
+		Id("streamed").Bool(),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("WriteHeader").Params(Id("statusCode").Int()).Block(
+		If(Op("!").Id("w").Dot("streamed")).Block(
+			Id("w").Dot("statusCode").Op("=").Id("statusCode"),
+		),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("Write").Params(Id("data").Op("[]").Byte()).Params(Int(), Error()).Block(
+		If(Id("w").Dot("streamed")).Block(
+			Return(Id("w").Dot("ResponseWriter").Dot("Write").Call(Id("data"))),
+		),
+		Return(Id("w").Dot("body").Dot("Write").Call(Id("data"))),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("Flush").Params().Block(
+		Id("w").Dot("send").Call(),
+		If(List(Id("flusher"), Id("ok")).Op(":=").Id("w").Dot("ResponseWriter").Op(".").Call(Qual(packageHttp, "Flusher")).Op(";").Id("ok")).Block(
+			Id("flusher").Dot("Flush").Call(),
+		),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("Unwrap").Params().Params(Qual(packageHttp, "ResponseWriter")).Block(
+		Return(Id("w").Dot("ResponseWriter")),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("status").Params().Params(Int()).Block(
+		If(Id("w").Dot("statusCode").Op("==").Lit(0)).Block(
+			Return(Qual(packageHttp, "StatusOK")),
+		),
+		Return(Id("w").Dot("statusCode")),
+	)
+
+	srcFile.Line().Func().Params(Id("w").Op("*").Id("responseWriter")).Id("send").Params().Block(
+		If(Id("w").Dot("streamed")).Block(
+			Return(),
+		),
+		Id("w").Dot("streamed").Op("=").True(),
+		Id("w").Dot("ResponseWriter").Dot("WriteHeader").Call(Id("w").Dot("status").Call()),
+		List(Id("_"), Id("_")).Op("=").Id("w").Dot("ResponseWriter").Dot("Write").Call(Id("w").Dot("body").Dot("Bytes").Call()),
+		Id("w").Dot("body").Dot("Reset").Call(),
+	)
+
+	srcFile.Line().Comment("statusOf returns status of response, which is set by handler")
+	srcFile.Func().Id("statusOf").Params(Id("w").Qual(packageHttp, "ResponseWriter")).Params(Int()).Block(
+		If(List(Id("response"), Id("ok")).Op(":=").Id("w").Op(".").Call(Op("*").Id("responseWriter")).Op(";").Id("ok")).Block(
+			Return(Id("response").Dot("status").Call()),
+		),
+		Return(Qual(packageHttp, "StatusOK")),
+	)
+
+	srcFile.Line().Comment("buffered sends response of handler after it, so routes may be served by other http.ServeMux too")
+	srcFile.Func().Id("buffered").Params(Id("handler").Qual(packageHttp, "HandlerFunc")).Params(Qual(packageHttp, "HandlerFunc")).Block(
+		Return(Func().Params(Id("w").Qual(packageHttp, "ResponseWriter"), Id("r").Op("*").Qual(packageHttp, "Request")).Block(
+			If(List(Id("_"), Id("ok")).Op(":=").Id("w").Op(".").Call(Op("*").Id("responseWriter")).Op(";").Id("ok")).Block(
+				Id("handler").Call(Id("w"), Id("r")),
+				Return(),
+			),
+			Id("response").Op(":=").Op("&").Id("responseWriter").Values(Dict{Id("ResponseWriter"): Id("w")}),
+			Id("handler").Call(Id("response"), Id("r")),
+			Id("response").Dot("send").Call(),
+		)),
+	)
+
+	srcFile.Line().Comment("replyError replaces buffered response by error")
+	srcFile.Func().Id("replyError").Params(Id("w").Qual(packageHttp, "ResponseWriter"), Id("text").String(), Id("statusCode").Int()).Block(
+		If(List(Id("response"), Id("ok")).Op(":=").Id("w").Op(".").Call(Op("*").Id("responseWriter")).Op(";").Id("ok")).Block(
+			Id("response").Dot("body").Dot("Reset").Call(),
+		),
+		Qual(packageHttp, "Error").Call(Id("w"), Id("text"), Id("statusCode")),
+	)
+
+	srcFile.Line().Comment("bodyErrorStatus returns 413 for body of request over limit of server, 400 for other errors of body")
+	srcFile.Func().Id("bodyErrorStatus").Params(Err().Error()).Params(Int()).Block(
+		Var().Id("maxBytesErr").Op("*").Qual(packageHttp, "MaxBytesError"),
+		If(Qual(packageErrors, "As").Call(Err(), Op("&").Id("maxBytesErr"))).Block(
+			Return(Qual(packageHttp, "StatusRequestEntityTooLarge")),
+		),
+		Return(Qual(packageHttp, "StatusBadRequest")),
+	)
+
+	srcFile.Line().Func().Id("cookieValue").Params(Id("r").Op("*").Qual(packageHttp, "Request"), Id("name").String()).Params(String()).Block(
+		If(List(Id("cookie"), Err()).Op(":=").Id("r").Dot("Cookie").Call(Id("name")).Op(";").Err().Op("==").Nil()).Block(
+			Return(Id("cookie").Dot("Value")),
+		),
+		Return(Lit("")),
+	)
+
+	return srcFile.Save(path.Join(outDir, "nethttp.go"))
+}
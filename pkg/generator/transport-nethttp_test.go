@@ -0,0 +1,146 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-nethttp_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const netHTTPServices = `package interfaces
+
+import "context"
+
+// @tg http-server
+type Files interface {
+	// @tg http-method=GET
+	// @tg http-path=/files/{id}
+	// @tg http-headers=token|X-Token
+	Get(ctx context.Context, id string, token string) (name string, err error)
+
+	// @tg http-method=POST
+	// @tg http-path=/files
+	Put(ctx context.Context, data string) (size int, err error)
+}
+
+// @tg jsonRPC-server
+type Calc interface {
+	Add(ctx context.Context, first int, second int) (sum int, err error)
+}
+`
+
+const netHTTPCheck = `package gentest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/transport"
+)
+
+type files struct{}
+
+func (files) Get(ctx context.Context, id string, token string) (string, error) {
+	return id + "/" + token, nil
+}
+
+func (files) Put(ctx context.Context, data string) (int, error) {
+	return len(data), nil
+}
+
+type calc struct{}
+
+func (calc) Add(ctx context.Context, first int, second int) (int, error) {
+	return first + second, nil
+}
+
+func newServer(log logrus.FieldLogger) *transport.Server {
+	return transport.New(log,
+		transport.MaxBodySize(256),
+		transport.Files(transport.NewFiles(log, files{})),
+		transport.Calc(transport.NewCalc(log, calc{})),
+	)
+}
+
+func TestNetHTTP(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	address := freeAddress(t)
+	srv := newServer(log)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+	waitServing(t, address)
+	url := "http://" + address
+
+	name, err := clients.NewHTTP("files", log, url).Files().Get(context.Background(), "42", "secret")
+	if err != nil || name != "42/secret" {
+		t.Errorf("unexpected result of REST client %s %v", name, err)
+	}
+	sum, err := clients.New("calc", log, url).Calc().Add(context.Background(), 1, 2)
+	if err != nil || sum != 3 {
+		t.Errorf("unexpected result of jsonRPC client %d %v", sum, err)
+	}
+
+	for _, test := range []struct {
+		data   string
+		status int
+	}{
+		{strings.Repeat("a", 16), http.StatusOK},
+		{strings.Repeat("a", 512), http.StatusRequestEntityTooLarge},
+	} {
+		response, err := http.Post(url+"/files", "application/json", strings.NewReader(` + "`" + `{"data":"` + "`" + `+test.data+` + "`" + `"}` + "`" + `))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+		if response.StatusCode != test.status {
+			t.Errorf("unexpected status of body with %d bytes %d", len(test.data), response.StatusCode)
+		}
+	}
+	batch := ` + "`" + `[{"jsonrpc":"2.0","id":1,"method":"calc.add","params":{"first":1,"second":2,"pad":"` + "`" + ` + strings.Repeat("a", 512) + ` + "`" + `"}}]` + "`" + `
+	response, err := http.Post(url+"/", "application/json", strings.NewReader(batch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("unexpected status of large batch %d", response.StatusCode)
+	}
+}
+
+func TestHandler(t *testing.T) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	var handler http.Handler = newServer(log).Handler()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	name, err := clients.NewHTTP("files", log, server.URL).Files().Get(context.Background(), "13", "token")
+	if err != nil || name != "13/token" {
+		t.Errorf("unexpected result of handler %s %v", name, err)
+	}
+	request := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("unexpected status of unknown path %d", recorder.Code)
+	}
+}
+`
+
+// TestNetHTTP renders transport on net/http and checks its clients, handler and limit of request body.
+func TestNetHTTP(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": netHTTPServices,
+		"nethttp_test.go":         netHTTPCheck,
+	}, nil, WithTracer(TracerNone), WithEngine("nethttp"))
+}
@@ -15,15 +15,18 @@ func (tr Transport) renderOptions(outDir string) (err error) {
 	srcFile := newSrc(filepath.Base(outDir))
 	srcFile.PackageComment(doNotEdit)
 
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 
 	srcFile.Line().Type().Id("ServiceRoute").Interface(
-		Id("SetRoutes").Params(Id("route").Op("*").Qual(packageFastHttpRouter, "Router")),
+		Id("SetRoutes").Params(Id("route").Add(tr.engine.routerType())),
 	)
 
 	srcFile.Line().Type().Id("Option").Func().Params(Id("srv").Op("*").Id("Server"))
-	srcFile.Type().Id("Handler").Op("=").Qual(packageFastHttp, "RequestHandler")
+	srcFile.Type().Id("Handler").Op("=").Add(tr.engine.handlerType())
 	srcFile.Type().Id("ErrorHandler").Func().Params(Err().Error()).Params(Error())
+	srcFile.Type().Id("PanicHandler").Func().Params(append(tr.engine.handlerParams(), Id("method").String(), Id("recovered").Interface(), Id("stack").Op("[]").Byte())...)
 
 	srcFile.Line().Func().Id("Service").Params(Id("svc").Id("ServiceRoute")).Id("Option").Block(
 		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
@@ -31,15 +34,27 @@ func (tr Transport) renderOptions(outDir string) (err error) {
 		)),
 	)
 
-	for serviceName := range tr.services {
+	for _, serviceName := range tr.serviceKeys() {
 		srcFile.Line().Func().Id(serviceName).Params(Id("svc").Op("*").Id("http" + serviceName)).Id("Option").Block(
-			Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
-				Id("srv").Dot("http"+serviceName).Op("=").Id("svc"),
-				Id("svc").Dot("SetRoutes").Call(Id("srv").Dot("Router").Call()),
-			)),
+			Return(Func().Params(Id("srv").Op("*").Id("Server")).BlockFunc(func(g *Group) {
+				g.Id("srv").Dot("http" + serviceName).Op("=").Id("svc")
+				if !tr.isNetHTTP() {
+					g.Id("svc").Dot("baseCtx").Op("=").Id("srv").Dot("ctx")
+				}
+				g.If(Id("srv").Dot("timeout").Op("!=").Lit(0)).Block(
+					Id("svc").Dot("timeout").Op("=").Id("srv").Dot("timeout"),
+				)
+				g.Id("svc").Dot("SetRoutes").Call(Id("srv").Dot("Router").Call())
+			})),
 		)
 	}
 
+	srcFile.Line().Func().Id("OnPanic").Params(Id("handler").Id("PanicHandler")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("panicHandler").Op("=").Id("handler"),
+		)),
+	)
+
 	srcFile.Line().Func().Id("AfterHTTP").Params(Id("handler").Id("Handler")).Id("Option").Block(
 		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
 			Id("srv").Dot("httpAfter").Op("=").Append(Id("srv").Dot("httpAfter"), Id("handler")),
@@ -57,5 +72,46 @@ func (tr Transport) renderOptions(outDir string) (err error) {
 			Id("srv").Dot("maxRequestBodySize").Op("=").Id("max"),
 		)),
 	)
+	srcFile.Line().Comment("ShutdownDelay sets time between readiness reports 503 and closing of listeners by Shutdown,")
+	srcFile.Comment("so balancers stop sending new requests before connections are refused")
+	srcFile.Func().Id("ShutdownDelay").Params(Id("delay").Qual(packageTime, "Duration")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+			Id("srv").Dot("shutdownDelay").Op("=").Id("delay"),
+		)),
+	)
+	srcFile.Line().Comment("Timeout sets default timeout of methods calls, timeout annotation of method or interface has priority")
+	srcFile.Func().Id("Timeout").Params(Id("timeout").Qual(packageTime, "Duration")).Id("Option").Block(
+		Return(Func().Params(Id("srv").Op("*").Id("Server")).BlockFunc(func(bg *Group) {
+			bg.Id("srv").Dot("timeout").Op("=").Id("timeout")
+			for _, serviceName := range tr.serviceKeys() {
+				bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
+					Id("srv").Dot("http" + serviceName).Dot("timeout").Op("=").Id("timeout"),
+				)
+			}
+		})),
+	)
+
+	if tr.tracer.isOTel() {
+		srcFile.Line().Comment("TracerProvider sets provider of server spans, global provider is used by default")
+		srcFile.Func().Id("TracerProvider").Params(Id("provider").Qual(packageOTelTrace, "TracerProvider")).Id("Option").Block(
+			Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+				Id("srv").Dot("tracerProvider").Op("=").Id("provider"),
+			)),
+		)
+	}
+	if tr.hasJsonRPC {
+		srcFile.Line().Comment("MaxBatchSize limits count of requests in jsonRPC batch, zero value means no limit")
+		srcFile.Func().Id("MaxBatchSize").Params(Id("size").Int()).Id("Option").Block(
+			Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+				Id("srv").Dot("maxBatchSize").Op("=").Id("size"),
+			)),
+		)
+		srcFile.Line().Comment("MaxParallelBatch limits count of jsonRPC batch requests executed in parallel")
+		srcFile.Func().Id("MaxParallelBatch").Params(Id("workers").Int()).Id("Option").Block(
+			Return(Func().Params(Id("srv").Op("*").Id("Server")).Block(
+				Id("srv").Dot("maxParallelBatch").Op("=").Id("workers"),
+			)),
+		)
+	}
 	return srcFile.Save(path.Join(outDir, "options.go"))
 }
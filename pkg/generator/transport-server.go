@@ -18,25 +18,36 @@ func (tr Transport) renderServer(outDir string) (err error) {
 	srcFile.Anon(packagePPROF)
 
 	srcFile.ImportName(packageIO, "io")
-	srcFile.ImportName(packageGotils, "gotils")
 	srcFile.ImportName(packageLogrus, "logrus")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
-	srcFile.ImportName(packageFastHttpRouter, "router")
 	srcFile.ImportName(packagePrometheusHttp, "promhttp")
-	srcFile.ImportName(packageFastHttpAdapt, "fasthttpadaptor")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageGotils, "gotils")
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+		srcFile.ImportName(packageFastHttpRouter, "router")
+		srcFile.ImportName(packageFastHttpAdapt, "fasthttpadaptor")
+	}
 
 	srcFile.Line().Const().Id("maxRequestBodySize").Op("=").Lit(100 * 1024 * 1024)
-	srcFile.Line().Type().Id("middleware").Func().Params(Qual(packageFastHttp, "RequestHandler")).Params(Qual(packageFastHttp, "RequestHandler"))
+	if tr.isNetHTTP() {
+		srcFile.Line().Type().Id("middleware").Func().Params(Qual(packageHttp, "Handler")).Params(Qual(packageHttp, "Handler"))
+	} else {
+		srcFile.Line().Type().Id("middleware").Func().Params(Qual(packageFastHttp, "RequestHandler")).Params(Qual(packageFastHttp, "RequestHandler"))
+	}
 
-	for _, service := range tr.services {
+	for _, serviceName := range tr.serviceKeys() {
+		service := tr.services[serviceName]
 		srcFile.ImportName(service.pkgPath, filepath.Base(service.pkgPath))
 	}
 
-	srcFile.Line().Add(tr.serverType())
+	srcFile.Line().Add(tr.serverStruct())
 	srcFile.Line().Add(tr.serverNewFunc())
 
 	srcFile.Line().Add(tr.serveHTTP())
 	srcFile.Line().Add(tr.serveHTTPS())
+	if tr.hasGRPC {
+		srcFile.ImportName(packageGRPC, "grpc")
+		srcFile.Line().Add(tr.serveGRPC(newGRPC(&tr, outDir).pbPkg))
+	}
 	srcFile.Line().Add(tr.httpHandler())
 
 	srcFile.Line().Add(tr.routerFunc())
@@ -45,14 +56,18 @@ func (tr Transport) renderServer(outDir string) (err error) {
 	srcFile.Line().Add(tr.withMetricsFunc())
 
 	srcFile.Line().Add(tr.serveProfileFunc())
-	srcFile.Line().Add(tr.serveHealthFunc())
 
+	srcFile.Line().Add(tr.serveFunc())
 	srcFile.Line().Add(tr.shutdownFunc())
+	srcFile.Line().Add(tr.shutdownServerFunc())
+	if tr.hasGRPC {
+		srcFile.Line().Add(tr.stopGRPCFunc())
+	}
 
 	srcFile.Line().Add(tr.sendResponseFunc())
 
-	for serviceName := range tr.services {
-		srcFile.Line().Add(Func().Params(Id("srv").Id("Server")).Id(serviceName).Params().Params(Op("*").Id("http" + serviceName)).Block(
+	for _, serviceName := range tr.serviceKeys() {
+		srcFile.Line().Add(Func().Params(Id("srv").Op("*").Id("Server")).Id(serviceName).Params().Params(Op("*").Id("http" + serviceName)).Block(
 			Return(Id("srv").Dot("http" + serviceName)),
 		))
 	}
@@ -61,7 +76,7 @@ func (tr Transport) renderServer(outDir string) (err error) {
 }
 
 func (tr Transport) routerFunc() Code {
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("Router").Params().Params(Op("*").Qual(packageFastHttpRouter, "Router")).Block(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("Router").Params().Params(tr.engine.routerType()).Block(
 		Return(Id("srv").Dot("router")),
 	)
 }
@@ -70,7 +85,10 @@ func (tr Transport) withLogFunc() Code {
 
 	return Func().Params(Id("srv").Op("*").Id("Server")).Id("WithLog").Params(Id("log").Qual(packageLogrus, "FieldLogger")).Params(Op("*").Id("Server")).BlockFunc(func(bg *Group) {
 
-		for serviceName := range tr.services {
+		for _, serviceName := range tr.serviceKeys() {
+			if !tr.services[serviceName].tags.Contains(tagLogger) {
+				continue
+			}
 			bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
 				Id("srv").Dot("http" + serviceName).Op("=").Id("srv").Dot(serviceName).Call().Dot("WithLog").Call(Id("log")),
 			)
@@ -83,7 +101,10 @@ func (tr Transport) withTraceFunc() Code {
 
 	return Func().Params(Id("srv").Op("*").Id("Server")).Id("WithTrace").Params().Params(Op("*").Id("Server")).BlockFunc(func(bg *Group) {
 
-		for serviceName := range tr.services {
+		for _, serviceName := range tr.serviceKeys() {
+			if !tr.services[serviceName].tags.Contains(tagTrace) {
+				continue
+			}
 			bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
 				Id("srv").Dot("http" + serviceName).Op("=").Id("srv").Dot(serviceName).Call().Dot("WithTrace").Call(),
 			)
@@ -96,16 +117,27 @@ func (tr Transport) withMetricsFunc() Code {
 
 	return Func().Params(Id("srv").Op("*").Id("Server")).Id("WithMetrics").Params().Params(Op("*").Id("Server")).BlockFunc(func(bg *Group) {
 
-		for serviceName := range tr.services {
+		bg.Line().If(Id("srv").Dot("metrics").Op("==").Nil()).Block(
+			Var().Err().Error(),
+			If(List(Id("srv").Dot("metrics"), Err()).Op("=").Id("NewMetrics").Call(Id("srv").Dot("metricsConfig")).Op(";").Err().Op("!=").Nil()).Block(
+				Comment("ServeMetrics returns error of registration"),
+				Id("srv").Dot("log").Dot("WithError").Call(Err()).Dot("Error").Call(Lit("could not register metrics")),
+				Return(Id("srv")),
+			),
+		)
+		for _, serviceName := range tr.serviceKeys() {
+			if !tr.services[serviceName].tags.Contains(tagMetrics) {
+				continue
+			}
 			bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
-				Id("srv").Dot("http" + serviceName).Op("=").Id("srv").Dot(serviceName).Call().Dot("WithMetrics").Call(),
+				Id("srv").Dot("http" + serviceName).Op("=").Id("srv").Dot(serviceName).Call().Dot("WithMetrics").Call(Id("srv").Dot("metrics")),
 			)
 		}
 		bg.Return(Id("srv"))
 	})
 }
 
-func (tr Transport) serverType() Code {
+func (tr Transport) serverStruct() Code {
 
 	return Type().Id("Server").StructFunc(func(g *Group) {
 
@@ -114,16 +146,46 @@ func (tr Transport) serverType() Code {
 		g.Line().Id("httpAfter").Op("[]").Id("Handler")
 		g.Id("httpBefore").Op("[]").Id("Handler")
 		g.Line().Id("maxRequestBodySize").Int()
+		g.Id("timeout").Qual(packageTime, "Duration")
+		g.Id("shutdownDelay").Qual(packageTime, "Duration")
+		if tr.hasJsonRPC {
+			g.Id("maxBatchSize").Int()
+			g.Id("maxParallelBatch").Int()
+		}
 
-		g.Line().Id("srvHTTP").Op("*").Qual(packageFastHttp, "Server")
-		g.Id("srvHealth").Op("*").Qual(packageFastHttp, "Server")
-		g.Id("srvPPROF").Op("*").Qual(packageFastHttp, "Server")
+		g.Line().Id("srvHTTP").Add(tr.serverType())
+		g.Id("srvHealth").Add(tr.serverType())
+		g.Id("srvPPROF").Add(tr.serverType())
+		g.Id("srvMetrics").Add(tr.serverType())
+		if tr.hasGRPC {
+			g.Id("srvGRPC").Op("*").Qual(packageGRPC, "Server")
+		}
 
 		g.Line().Id("reporterCloser").Qual(packageIO, "Closer")
+		if tr.tracer.isOTel() {
+			g.Id("tracerProvider").Qual(packageOTelTrace, "TracerProvider")
+		}
+		g.Id("healthMtx").Qual(packageSync, "RWMutex")
+		g.Id("healthChecks").Op("[]").Id("healthCheck")
+		g.Id("panicHandler").Id("PanicHandler")
+		g.Id("compressMinSize").Int()
+		if tr.hasAuth() {
+			g.Id("authenticator").Id("Authenticator")
+		}
+		if tr.hasCORS() {
+			g.Id("cors").Op("*").Id("CORSConfig")
+		}
 
-		g.Line().Id("router").Op("*").Qual(packageFastHttpRouter, "Router").Line()
+		g.Line().Id("metrics").Op("*").Id("Metrics")
+		g.Id("metricsConfig").Id("MetricsConfig")
 
-		for serviceName := range tr.services {
+		g.Line().Id("ctx").Qual(packageContext, "Context")
+		g.Id("cancel").Qual(packageContext, "CancelFunc")
+		g.Id("stopping").Int32()
+
+		g.Line().Id("router").Add(tr.engine.routerType()).Line()
+
+		for _, serviceName := range tr.serviceKeys() {
 			g.Id("http" + serviceName).Op("*").Id("http" + serviceName)
 		}
 	})
@@ -133,24 +195,60 @@ func (tr Transport) serverNewFunc() Code {
 
 	return Func().Id("New").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("options").Op("...").Id("Option")).Params(Id("srv").Op("*").Id("Server")).
 		BlockFunc(func(bg *Group) {
-			bg.Line().Id("srv").Op("=").Op("&").Id("Server").Values(Dict{
+			values := Dict{
 				Id("log"):                Id("log"),
-				Id("router"):             Qual(packageFastHttpRouter, "New").Call(),
+				Id("router"):             tr.newRouter(),
 				Id("maxRequestBodySize"): Id("maxRequestBodySize"),
-			})
+				Id("compressMinSize"):    Id("defaultCompressMinSize"),
+			}
 			if tr.hasJsonRPC {
-				bg.Id("srv").Dot("router").Dot("POST").Call(Lit("/"), Id("srv").Dot("serveBatch"))
+				values[Id("maxParallelBatch")] = Id("defaultMaxParallelBatch")
+			}
+			bg.Line().Id("srv").Op("=").Op("&").Id("Server").Values(values)
+			bg.List(Id("srv").Dot("ctx"), Id("srv").Dot("cancel")).Op("=").Qual(packageContext, "WithCancel").Call(Qual(packageContext, "Background").Call())
+			if tr.hasJsonRPC && tr.hasCORS() {
+				bg.Add(tr.engine.route(Id("srv").Dot("router"), "POST", "/", Id("srv").Dot("withCORS").Call(Id("srv").Dot("compress").Call(Id("srv").Dot("serveBatch")))))
+				bg.Add(tr.engine.route(Id("srv").Dot("router"), "OPTIONS", "/", Id("srv").Dot("preflight").Call(Lit("POST"))))
+			} else if tr.hasJsonRPC {
+				bg.Add(tr.engine.route(Id("srv").Dot("router"), "POST", "/", Id("srv").Dot("compress").Call(Id("srv").Dot("serveBatch"))))
 			}
 			bg.For(List(Id("_"), Id("option")).Op(":=").Range().Id("options")).Block(
 				Id("option").Call(Id("srv")),
 			)
+			for _, serviceName := range tr.serviceKeys() {
+				bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).BlockFunc(func(g *Group) {
+					g.Id("srv").Dot("http" + serviceName).Dot("panicHandler").Op("=").Id("srv").Dot("panicHandler")
+					g.Id("srv").Dot("http" + serviceName).Dot("compressMinSize").Op("=").Id("srv").Dot("compressMinSize")
+					if tr.tracer.isOTel() {
+						g.Id("srv").Dot("http" + serviceName).Dot("tracerProvider").Op("=").Id("srv").Dot("tracerProvider")
+					}
+					if tr.services[serviceName].tags.Contains(tagServerJsonRPC) {
+						g.Id("srv").Dot("http" + serviceName).Dot("maxBatchSize").Op("=").Id("srv").Dot("maxBatchSize")
+						g.Id("srv").Dot("http" + serviceName).Dot("maxParallelBatch").Op("=").Id("srv").Dot("maxParallelBatch")
+					}
+					if tr.services[serviceName].hasAuth() {
+						g.Id("srv").Dot("http" + serviceName).Dot("authenticator").Op("=").Id("srv").Dot("authenticator")
+					}
+					if tr.hasCORS() {
+						g.If(Id("srv").Dot("cors").Op("!=").Nil()).Block(
+							Id("srv").Dot("http" + serviceName).Dot("cors").Op("=").Id("srv").Dot("cors"),
+						)
+					}
+				})
+			}
+			if tr.hasCORS() && tr.tags.IsSet(tagCorsOrigins) {
+				bg.If(Id("srv").Dot("cors").Op("==").Nil()).Block(
+					Id("srv").Dot("cors").Op("=").Add(corsConfigCode(corsTags(tr.tags, nil))),
+				)
+			}
+			bg.Id("srv").Dot("registerHealthCheckers").Call()
 			bg.Return()
 		})
 }
 
 func (tr Transport) serveHTTP() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHTTP").Params(Id("address").String(), Id("wraps").Op("...").Id("middleware")).BlockFunc(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHTTP").Params(Id("address").String(), Id("wraps").Op("...").Id("middleware")).Params(Err().Error()).BlockFunc(
 
 		func(bg *Group) {
 
@@ -161,22 +259,35 @@ func (tr Transport) serveHTTP() Code {
 			bg.Line().For(List(Id("_"), Id("wrap")).Op(":=").Range().Id("wraps")).Block(
 				Id("handler").Op("=").Id("wrap").Call(Id("handler")),
 			)
-			bg.Id("srv").Dot("srvHTTP").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
-				Id("ReadTimeout"):        Qual(packageTime, "Second").Op("*").Lit(10),
-				Id("Handler"):            Id("handler"),
-				Id("MaxRequestBodySize"): Id("srv").Dot("maxRequestBodySize"),
-			})
-			bg.Go().Func().Params().Block(
-				Err().Op(":=").Id("srv").Dot("srvHTTP").Dot("ListenAndServe").Call(Id("address")),
-				Id("ExitOnError").Call(Id("srv").Dot("log"), Err(), Lit("serve http on ").Op("+").Id("address")),
-			).Call()
+			if tr.isNetHTTP() {
+				bg.Id("srv").Dot("srvHTTP").Op("=").Id("srv").Dot("httpServer").Call(Id("handler"))
+			} else {
+				bg.Id("srv").Dot("srvHTTP").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
+					Id("ReadTimeout"):        Qual(packageTime, "Second").Op("*").Lit(10),
+					Id("CloseOnShutdown"):    True(),
+					Id("Handler"):            Id("handler"),
+					Id("MaxRequestBodySize"): Id("srv").Dot("maxRequestBodySize"),
+				})
+			}
+			bg.Return(Id("srv").Dot("serve").Call(Id("address"), Lit("http"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+				Return(Id("srv").Dot("srvHTTP").Dot("Serve").Call(tr.closeNotify(Id("listener")))),
+			)))
 		},
 	)
 }
 
+// closeNotify wraps listener of fasthttp server, so contexts of requests are canceled by close of connection.
+func (tr Transport) closeNotify(listener Code) Code {
+
+	if tr.isNetHTTP() {
+		return listener
+	}
+	return Id("closeNotifyListener").Values(Dict{Id("Listener"): listener})
+}
+
 func (tr Transport) serveHTTPS() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHTTPS").Params(Id("address"), Id("certFile"), Id("keyFile").String(), Id("wraps").Op("...").Id("middleware")).BlockFunc(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHTTPS").Params(Id("address"), Id("certFile"), Id("keyFile").String(), Id("wraps").Op("...").Id("middleware")).Params(Err().Error()).BlockFunc(
 
 		func(bg *Group) {
 
@@ -187,25 +298,97 @@ func (tr Transport) serveHTTPS() Code {
 			bg.Line().For(List(Id("_"), Id("wrap")).Op(":=").Range().Id("wraps")).Block(
 				Id("handler").Op("=").Id("wrap").Call(Id("handler")),
 			)
+			if tr.isNetHTTP() {
+				bg.Id("srv").Dot("srvHTTP").Op("=").Id("srv").Dot("httpServer").Call(Id("handler"))
+				bg.Return(Id("srv").Dot("serve").Call(Id("address"), Lit("https"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+					Return(Id("srv").Dot("srvHTTP").Dot("ServeTLS").Call(Id("listener"), Id("certFile"), Id("keyFile"))),
+				)))
+				return
+			}
 			bg.Id("srv").Dot("srvHTTP").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
 				Id("ReadTimeout"):        Qual(packageTime, "Second").Op("*").Lit(10),
 				Id("Handler"):            Id("handler"),
+				Id("CloseOnShutdown"):    True(),
 				Id("MaxRequestBodySize"): Id("srv").Dot("maxRequestBodySize"),
 			})
-			bg.Go().Func().Params().Block(
-				Err().Op(":=").Id("srv").Dot("srvHTTP").Dot("ListenAndServeTLS").Call(Id("address"), Id("certFile"), Id("keyFile")),
-				Id("ExitOnError").Call(Id("srv").Dot("log"), Err(), Lit("serve http on ").Op("+").Id("address")),
-			).Call()
+			// TLS is served above connections, which notify about close, so close_notify of client ends connection
+			bg.Var().Id("cert").Qual(packageTLS, "Certificate")
+			bg.If(List(Id("cert"), Err()).Op("=").Qual(packageTLS, "LoadX509KeyPair").Call(Id("certFile"), Id("keyFile")).Op(";").Err().Op("!=").Nil()).Block(
+				Return(),
+			)
+			bg.Id("config").Op(":=").Op("&").Qual(packageTLS, "Config").Values(Dict{
+				Id("Certificates"): Index().Qual(packageTLS, "Certificate").Values(Id("cert")),
+			})
+			bg.Return(Id("srv").Dot("serve").Call(Id("address"), Lit("https"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+				Return(Id("srv").Dot("srvHTTP").Dot("Serve").Call(tr.closeNotify(Qual(packageTLS, "NewListener").Call(Id("listener"), Id("config"))))),
+			)))
+		},
+	)
+}
+
+func (tr Transport) serveGRPC(pbPkg string) Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeGRPC").Params(Id("address").String(), Id("options").Op("...").Qual(packageGRPC, "ServerOption")).Params(Err().Error()).BlockFunc(
+
+		func(bg *Group) {
+
+			bg.Line().Id("srv").Dot("log").Dot("WithField").Call(Lit("address"), Id("address")).Dot("Info").Call(Lit("enable gRPC transport"))
+
+			bg.Id("srv").Dot("srvGRPC").Op("=").Qual(packageGRPC, "NewServer").Call(Id("options").Op("..."))
+
+			bg.Line()
+			for _, serviceName := range tr.serviceKeys() {
+				if tr.services[serviceName].tags.Contains(tagServerGRPC) {
+					bg.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
+						Qual(pbPkg, "Register"+serviceName+"Server").Call(Id("srv").Dot("srvGRPC"), Op("&").Id("grpc"+serviceName).Values(Dict{
+							Id("http"): Id("srv").Dot("http" + serviceName),
+						})),
+					)
+				}
+			}
+			bg.Line().Return(Id("srv").Dot("serve").Call(Id("address"), Lit("grpc"), Id("srv").Dot("srvGRPC").Dot("Serve")))
 		},
 	)
 }
 
 func (tr Transport) httpHandler() Code {
 
+	if tr.isNetHTTP() {
+		return Func().Params(Id("srv").Op("*").Id("Server")).Id("httpHandler").Params().Params(Qual(packageHttp, "Handler")).Block(
+
+			Return(Qual(packageHttp, "HandlerFunc").Call(Func().Params(Id("w").Qual(packageHttp, "ResponseWriter"), Id("r").Op("*").Qual(packageHttp, "Request")).Block(
+				Line().Id("response").Op(":=").Op("&").Id("responseWriter").Values(Dict{Id("ResponseWriter"): Id("w")}),
+				Id("r").Dot("Body").Op("=").Qual(packageHttp, "MaxBytesReader").Call(Id("w"), Id("r").Dot("Body"), Int64().Call(Id("srv").Dot("maxRequestBodySize"))),
+				If(List(Id("statusCode"), Err()).Op(":=").Id("decompressRequest").Call(Id("w"), Id("r"), Id("srv").Dot("maxRequestBodySize")).Op(";").Err().Op("!=").Nil()).Block(
+					Qual(packageHttp, "Error").Call(Id("w"), Err().Dot("Error").Call(), Id("statusCode")),
+					Return(),
+				),
+				For(List(Id("_"), Id("before")).Op(":=").Range().Id("srv").Dot("httpBefore")).Block(
+					Id("before").Call(Id("response"), Id("r")),
+				),
+				Id("srv").Dot("router").Dot("ServeHTTP").Call(Id("response"), Id("r")),
+				Line().For(List(Id("_"), Id("after")).Op(":=").Range().Id("srv").Dot("httpAfter")).Block(
+					Id("after").Call(Id("response"), Id("r")),
+				),
+				Id("response").Dot("send").Call(),
+			))),
+		)
+	}
 	return Func().Params(Id("srv").Op("*").Id("Server")).Id("httpHandler").Params().Params(Qual(packageFastHttp, "RequestHandler")).Block(
 
 		Return().Func().Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
-			Line().For(List(Id("_"), Id("before")).Op(":=").Range().Id("srv").Dot("httpBefore")).Block(
+
+			Line().List(Id("requestCtx"), Id("cancel")).Op(":=").Qual(packageContext, "WithCancel").Call(Id("srv").Dot("ctx")),
+			Defer().Id("cancel").Call(),
+			If(List(Id("conn"), Id("ok")).Op(":=").Id(_ctx_).Dot("Conn").Call().Op(".").Call(Id("closeNotifier")).Op(";").Id("ok")).Block(
+				Id("conn").Dot("notifyClose").Call(Id("cancel")),
+			),
+			Id(_ctx_).Dot("SetUserValue").Call(Id("userValueRequestContext"), Id("requestCtx")),
+			Line().If(List(Id("statusCode"), Err()).Op(":=").Id("decompressRequest").Call(Id(_ctx_), Id("srv").Dot("maxRequestBodySize")).Op(";").Err().Op("!=").Nil()).Block(
+				Id(_ctx_).Dot("Error").Call(Err().Dot("Error").Call(), Id("statusCode")),
+				Return(),
+			),
+			For(List(Id("_"), Id("before")).Op(":=").Range().Id("srv").Dot("httpBefore")).Block(
 				Id("before").Call(Id("ctx")),
 			),
 			Id("srv").Dot("router").Dot("Handler").Call(Id(_ctx_)),
@@ -216,67 +399,191 @@ func (tr Transport) httpHandler() Code {
 	)
 }
 
-func (tr Transport) serveHealthFunc() Code {
+func (tr Transport) serverType() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServeHealth").Params(Id("address").String()).Block(
+	if tr.isNetHTTP() {
+		return Op("*").Qual(packageHttp, "Server")
+	}
+	return Op("*").Qual(packageFastHttp, "Server")
+}
 
-		Line().Id("srv").Dot("srvHealth").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
-			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
-			Id("Handler"): Func().Params(Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
-				Id(_ctx_).Dot("SetStatusCode").Call(Qual(packageFastHttp, "StatusOK")),
-				List(Id("_"), Id("_")).Op("=").Id(_ctx_).Dot("WriteString").Call(Lit("ok")),
-			),
-		}),
+func (tr Transport) newRouter() Code {
+
+	if tr.isNetHTTP() {
+		return Qual(packageHttp, "NewServeMux").Call()
+	}
+	return Qual(packageFastHttpRouter, "New").Call()
+}
+
+// serveFunc renders listener, which reports bind errors to caller and logs errors of serving in background.
+func (tr Transport) serveFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("serve").
+		Params(Id("address"), Id("name").String(), Id("serve").Func().Params(Qual(packageNet, "Listener")).Params(Error())).Params(Err().Error()).Block(
+
+		Line().Var().Id("listener").Qual(packageNet, "Listener"),
+		If(List(Id("listener"), Err()).Op("=").Qual(packageNet, "Listen").Call(Lit("tcp4"), Id("address")).Op(";").Err().Op("!=").Nil()).Block(
+			Return(Qual(packageFmt, "Errorf").Call(Lit("listen %s on %s: %w"), Id("name"), Id("address"), Err())),
+		),
 		Go().Func().Params().Block(
-			Err().Op(":=").Id("srv").Dot("srvHealth").Dot("ListenAndServe").Call(Id("address")),
-			Id("ExitOnError").Call(Id("srv").Dot("log"), Err(), Lit("serve health on ").Op("+").Id("address")),
+			If(Err().Op(":=").Id("serve").Call(Id("listener")).Op(";").Err().Op("!=").Nil()).Block(
+				Id("srv").Dot("log").Dot("WithError").Call(Err()).Dot("Error").Call(Lit("serve ").Op("+").Id("name").Op("+").Lit(" on ").Op("+").Id("address")),
+			),
 		).Call(),
+		Return(),
 	)
 }
 
+// shutdownFunc renders graceful stop: health reports not ready, listeners are closed after delay, in-flight requests
+// are drained until deadline of context, after that contexts of methods are canceled and tracer is flushed.
 func (tr Transport) shutdownFunc() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("Shutdown").Params().Block(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("Shutdown").Params(Id(_ctx_).Qual(packageContext, "Context")).Params(Err().Error()).BlockFunc(func(bg *Group) {
 
-		Line().If(Id("srv").Dot("srvHTTP").Op("!=").Id("nil")).Block(
-			Id("_").Op("=").Id("srv").Dot("srvHTTP").Dot("Shutdown").Call(),
-		),
+		bg.Line().Qual(packageAtomic, "StoreInt32").Call(Op("&").Id("srv").Dot("stopping"), Lit(1))
+		bg.If(Id("srv").Dot("shutdownDelay").Op(">").Lit(0)).Block(
+			Select().Block(
+				Case(Op("<-").Qual(packageTime, "After").Call(Id("srv").Dot("shutdownDelay"))),
+				Case(Op("<-").Id(_ctx_).Dot("Done").Call()),
+			),
+		)
+
+		bg.Line().Var().Id("errs").Id("shutdownErrors")
+		bg.Var().Id("mtx").Qual(packageSync, "Mutex")
+		bg.Id("collect").Op(":=").Func().Params(Id("name").String(), Err().Error()).Block(
+			If(Err().Op("!=").Nil()).Block(
+				Id("mtx").Dot("Lock").Call(),
+				Id("errs").Op("=").Append(Id("errs"), Qual(packageFmt, "Errorf").Call(Lit("%s: %w"), Id("name"), Err())),
+				Id("mtx").Dot("Unlock").Call(),
+			),
+		)
 
-		Line().If(Id("srv").Dot("srvHealth").Op("!=").Id("nil")).Block(
-			Id("_").Op("=").Id("srv").Dot("srvHealth").Dot("Shutdown").Call(),
-		),
+		if tr.hasGRPC {
+			bg.Line().Var().Id("wg").Qual(packageSync, "WaitGroup")
+			bg.Id("wg").Dot("Add").Call(Lit(1))
+			bg.Go().Func().Params().Block(
+				Defer().Id("wg").Dot("Done").Call(),
+				Id("collect").Call(Lit("grpc"), Id("stopGRPC").Call(Id(_ctx_), Id("srv").Dot("srvGRPC"))),
+			).Call()
+			bg.Id("collect").Call(Lit("http"), Id("shutdownServer").Call(Id(_ctx_), Id("srv").Dot("srvHTTP")))
+			bg.Id("wg").Dot("Wait").Call()
+		} else {
+			bg.Line().Id("collect").Call(Lit("http"), Id("shutdownServer").Call(Id(_ctx_), Id("srv").Dot("srvHTTP")))
+		}
+		bg.Id("srv").Dot("cancel").Call()
+
+		bg.Line().Id("collect").Call(Lit("health"), Id("shutdownServer").Call(Id(_ctx_), Id("srv").Dot("srvHealth")))
+		bg.Id("collect").Call(Lit("PPROF"), Id("shutdownServer").Call(Id(_ctx_), Id("srv").Dot("srvPPROF")))
+		bg.Id("collect").Call(Lit("metrics"), Id("shutdownServer").Call(Id(_ctx_), Id("srv").Dot("srvMetrics")))
+
+		bg.Line().If(Id("srv").Dot("reporterCloser").Op("!=").Nil()).Block(
+			Id("collect").Call(Lit("tracer"), Id("srv").Dot("reporterCloser").Dot("Close").Call()),
+		)
+		bg.Line().If(Len(Id("errs")).Op("!=").Lit(0)).Block(
+			Return(Id("errs")),
+		)
+		bg.Return()
+	})
+}
 
-		Line().If(Id("srvMetrics").Op("!=").Id("nil")).Block(
-			Id("_").Op("=").Id("srvMetrics").Dot("Shutdown").Call(),
+// shutdownServerFunc renders shutdown limited by context, fasthttp waits for open connections without deadline.
+func (tr Transport) shutdownServerFunc() Code {
+
+	if tr.isNetHTTP() {
+		return Func().Id("shutdownServer").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("server").Op("*").Qual(packageHttp, "Server")).Params(Err().Error()).Block(
+
+			Line().If(Id("server").Op("==").Nil()).Block(
+				Return(),
+			),
+			Return(Id("server").Dot("Shutdown").Call(Id(_ctx_))),
+		)
+	}
+	return Func().Id("shutdownServer").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("server").Op("*").Qual(packageFastHttp, "Server")).Params(Err().Error()).Block(
+
+		Line().If(Id("server").Op("==").Nil()).Block(
+			Return(),
 		),
+		Id("done").Op(":=").Make(Chan().Error(), Lit(1)),
+		Go().Func().Params().Block(
+			Id("done").Op("<-").Id("server").Dot("Shutdown").Call(),
+		).Call(),
+		Select().Block(
+			Case(Err().Op("=").Op("<-").Id("done")).Block(
+				Return(),
+			),
+			Case(Op("<-").Id(_ctx_).Dot("Done").Call()).Block(
+				Return(Id(_ctx_).Dot("Err").Call()),
+			),
+		),
+	)
+}
+
+func (tr Transport) stopGRPCFunc() Code {
 
-		Line().If(Id("srv").Dot("srvPPROF").Op("!=").Id("nil")).Block(
-			Id("_").Op("=").Id("srv").Dot("srvPPROF").Dot("Shutdown").Call(),
+	return Func().Id("stopGRPC").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("server").Op("*").Qual(packageGRPC, "Server")).Params(Err().Error()).Block(
+
+		Line().If(Id("server").Op("==").Nil()).Block(
+			Return(),
+		),
+		Id("done").Op(":=").Make(Chan().Struct()),
+		Go().Func().Params().Block(
+			Id("server").Dot("GracefulStop").Call(),
+			Close(Id("done")),
+		).Call(),
+		Select().Block(
+			Case(Op("<-").Id("done")).Block(
+				Return(),
+			),
+			Case(Op("<-").Id(_ctx_).Dot("Done").Call()).Block(
+				Id("server").Dot("Stop").Call(),
+				Return(Id(_ctx_).Dot("Err").Call()),
+			),
 		),
 	)
 }
 
 func (tr Transport) serveProfileFunc() Code {
 
-	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServePPROF").Params(Id("address").String()).Block(
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("ServePPROF").Params(Id("address").String()).Params(Err().Error()).Block(
 
 		Line().Qual(packageRuntime, "SetBlockProfileRate").Call(Lit(1)),
 		Qual(packageRuntime, "SetMutexProfileFraction").Call(Lit(5)),
 
-		Line().Id("srv").Dot("srvPPROF").Op("=").Op("&").Qual(packageFastHttp, "Server").Values(Dict{
-			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
-			Id("Handler"):     Qual(packageFastHttpAdapt, "NewFastHTTPHandler").Call(Qual(packageHttp, "DefaultServeMux")),
-		}),
+		Line().Id("srv").Dot("srvPPROF").Op("=").Add(tr.utilityServer(Qual(packageHttp, "DefaultServeMux"))),
 
-		Line().Go().Func().Params().Block(
-			Err().Op(":=").Id("srv").Dot("srvPPROF").Dot("ListenAndServe").Call(Id("address")),
-			Id("ExitOnError").Call(Id("srv").Dot("log"), Err(), Lit("serve PPROF on ").Op("+").Id("address")),
-		).Call(),
+		Line().Return(Id("srv").Dot("serve").Call(Id("address"), Lit("PPROF"), Func().Params(Id("listener").Qual(packageNet, "Listener")).Params(Error()).Block(
+			Return(Id("srv").Dot("srvPPROF").Dot("Serve").Call(Id("listener"))),
+		))),
 	)
 }
 
+// utilityServer renders server of health, metrics and profile endpoints.
+func (tr Transport) utilityServer(handler Code) Code {
+
+	if tr.isNetHTTP() {
+		return Op("&").Qual(packageHttp, "Server").Values(Dict{
+			Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
+			Id("Handler"):     handler,
+		})
+	}
+	return Op("&").Qual(packageFastHttp, "Server").Values(Dict{
+		Id("ReadTimeout"): Qual(packageTime, "Second").Op("*").Lit(10),
+		Id("Handler"):     Qual(packageFastHttpAdapt, "NewFastHTTPHandler").Call(handler),
+	})
+}
+
 func (tr Transport) sendResponseFunc() Code {
 
+	if tr.isNetHTTP() {
+		return Func().Id("sendResponse").Params(append(append([]Code{Id("log").Qual(packageLogrus, "FieldLogger")}, tr.engine.handlerParams()...), Id("resp").Interface())...).Block(
+
+			Line().Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Content-Type"), Lit("application/json")),
+
+			Line().If(Err().Op(":=").Qual(packageJson, "NewEncoder").Call(Id("w")).Dot("Encode").Call(Id("resp")).Op(";").Err().Op("!=").Nil()).Block(
+				Id("log").Dot("WithError").Call(Err()).Dot("Error").Call(Lit("response write error")),
+			),
+		)
+	}
 	return Func().Id("sendResponse").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx"), Id("resp").Interface()).Block(
 
 		Line().Id(_ctx_).Dot("SetContentType").Call(Lit("application/json")),
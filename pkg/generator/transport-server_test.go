@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-server_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const shutdownServices = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server
+type Sleeper interface {
+	Sleep(ctx context.Context, ms int) (slept int, err error)
+}
+`
+
+const shutdownCheck = `package gentest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"gentest/clients"
+	"gentest/transport"
+)
+
+type sleeper struct {
+	started chan struct{}
+}
+
+func (s sleeper) Sleep(ctx context.Context, ms int) (slept int, err error) {
+	s.started <- struct{}{}
+	time.Sleep(time.Duration(ms) * time.Millisecond)
+	return ms, nil
+}
+
+func serveSleeper(t *testing.T, options ...transport.Option) (srv *transport.Server, svc sleeper, address string) {
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc = sleeper{started: make(chan struct{}, 1)}
+	address = freeAddress(t)
+	srv = transport.New(log, append(options, transport.Sleeper(transport.NewSleeper(log, svc)))...)
+	if err := srv.ServeHTTP(address); err != nil {
+		t.Fatal(err)
+	}
+	waitServing(t, address)
+	return
+}
+
+func TestShutdownDrains(t *testing.T) {
+
+	srv, svc, address := serveSleeper(t)
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	cli := clients.New("sleeper", log, "http://"+address).Sleeper()
+
+	type result struct {
+		slept int
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		slept, err := cli.Sleep(context.Background(), 300)
+		done <- result{slept: slept, err: err}
+	}()
+	<-svc.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	select {
+	case res := <-done:
+		if res.err != nil || res.slept != 300 {
+			t.Errorf("in-flight request is not drained: %d %v", res.slept, res.err)
+		}
+	default:
+		t.Error("shutdown returned before in-flight request is done")
+	}
+	if _, err := cli.Sleep(context.Background(), 0); err == nil {
+		t.Error("request is accepted after shutdown")
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+
+	srv, svc, address := serveSleeper(t)
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	cli := clients.New("sleeper", log, "http://"+address).Sleeper()
+
+	go func() { _, _ = cli.Sleep(context.Background(), 1000) }()
+	<-svc.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unexpected error of shutdown by deadline: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("shutdown returned after %s", elapsed)
+	}
+}
+
+func TestShutdownDelay(t *testing.T) {
+
+	srv, _, address := serveSleeper(t, transport.ShutdownDelay(300*time.Millisecond))
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	cli := clients.New("sleeper", log, "http://"+address).Sleeper()
+
+	go func() { _ = srv.Shutdown(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+	// listeners are open during delay
+	if slept, err := cli.Sleep(context.Background(), 0); err != nil || slept != 0 {
+		t.Errorf("request is refused during shutdown delay: %v", err)
+	}
+}
+`
+
+// TestShutdown checks, that Shutdown drains in-flight requests, keeps listeners during delay and is bounded by context.
+func TestShutdown(t *testing.T) {
+
+	testGenerated(t, map[string]string{
+		"interfaces/interface.go": shutdownServices,
+		"shutdown_test.go":        shutdownCheck,
+	}, nil, WithTracer(TracerNone))
+}
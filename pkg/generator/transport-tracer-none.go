@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-tracer-none.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+// renderTracerNone renders stubs of span functions, which keep only request ID handling.
+func (tr Transport) renderTracerNone(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageLogrus, "logrus")
+	srcFile.ImportName(packageGotils, "gotils")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+
+	srcFile.Const().Id("headerRequestID").Op("=").Lit("X-Request-Id")
+
+	srcFile.Line().Type().Id("noopSpan").Struct()
+
+	srcFile.Line().Func().Id("injectSpan").Params(append([]Code{Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Id("noopSpan")}, tr.engine.handlerParams()...)...).Block(
+		tr.replyRequestIDCode(),
+	)
+
+	srcFile.Line().Func().Id("extractSpan").
+		Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("opName").String(), tr.engine.requestParam()).
+		Params(Id("span").Id("noopSpan")).Block(
+
+		Line().Add(tr.requestIDCode()),
+		tr.setRequestIDCode(),
+		Return(),
+	)
+
+	srcFile.Line().Add(tr.toStringFunc())
+
+	return srcFile.Save(path.Join(outDir, "tracer.go"))
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-tracer-otel.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+func (tr Transport) renderTracerOTel(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.ImportName(packageHttp, "http")
+	srcFile.ImportName(packageOTel, "otel")
+	srcFile.ImportName(packageLogrus, "logrus")
+	srcFile.ImportName(packageGotils, "gotils")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
+	srcFile.ImportName(packageOTelTrace, "trace")
+	srcFile.ImportName(packageOTelCodes, "codes")
+	srcFile.ImportAlias(packageOTelSDK, "sdktrace")
+	srcFile.ImportName(packageOTelResource, "resource")
+	srcFile.ImportName(packageOTelAttribute, "attribute")
+	srcFile.ImportName(packageOTelPropagation, "propagation")
+	srcFile.ImportName(packageOTelOTLPHttp, "otlptracehttp")
+
+	srcFile.Const().Defs(
+		Id("tracerName").Op("=").Lit(otelScope),
+		Id("headerRequestID").Op("=").Lit("X-Request-Id"),
+	)
+
+	srcFile.Line().Var().Id("tracePropagator").Op("=").Qual(packageOTelPropagation, "NewCompositeTextMapPropagator").Call(
+		Qual(packageOTelPropagation, "TraceContext").Values(),
+		Qual(packageOTelPropagation, "Baggage").Values(),
+	)
+
+	srcFile.Line().Type().Id("tracerProviderCloser").Struct(
+		Id("provider").Op("*").Qual(packageOTelSDK, "TracerProvider"),
+	)
+
+	srcFile.Line().Func().Params(Id("closer").Id("tracerProviderCloser")).Id("Close").Params().Params(Error()).Block(
+		Return(Id("closer").Dot("provider").Dot("Shutdown").Call(Qual(packageContext, "Background").Call())),
+	)
+
+	srcFile.Line().Add(tr.traceOTLPFunc())
+
+	srcFile.Line().Add(tr.injectSpanOTelFunc())
+	srcFile.Line().Add(tr.tracerProviderFunc())
+	srcFile.Line().Add(tr.extractSpanOTelFunc())
+	srcFile.Line().Add(tr.startSpanFunc())
+	srcFile.Line().Add(tr.spanAttributeFunc())
+	if tr.isNetHTTP() {
+		srcFile.Line().Add(tr.requestURLFunc())
+		srcFile.Line().Add(tr.remoteIPFunc())
+	}
+
+	srcFile.Line().Add(tr.toStringFunc())
+
+	return srcFile.Save(path.Join(outDir, "tracer.go"))
+}
+
+// traceOTLPFunc renders setup of tracer provider of server, which exports spans by OTLP over HTTP.
+// Empty endpoint means exporter is configured by OTEL_EXPORTER_OTLP_* environment variables.
+func (tr Transport) traceOTLPFunc() Code {
+
+	return Func().Params(Id("srv").Op("*").Id("Server")).Id("TraceOTLP").Params(Id("serviceName").String(), Id("endpoint").String()).Params(Op("*").Id("Server")).BlockFunc(func(g *Group) {
+
+		g.Line().Var().Id("opts").Op("[]").Qual(packageOTelOTLPHttp, "Option")
+		g.If(Id("endpoint").Op("!=").Lit("")).Block(
+			Id("opts").Op("=").Append(Id("opts"), Qual(packageOTelOTLPHttp, "WithEndpointURL").Call(Id("endpoint"))),
+		)
+		g.List(Id("exporter"), Err()).Op(":=").Qual(packageOTelOTLPHttp, "New").Call(Qual(packageContext, "Background").Call(), Id("opts").Op("..."))
+		g.Id("ExitOnError").Call(Id("srv").Dot("log"), Err(), Lit("could not create OTLP exporter"))
+
+		g.Line().List(Id("environment"), Id("envExists")).Op(":=").Qual(packageOS, "LookupEnv").Call(Lit("ENV"))
+
+		g.Line().If(Id("envExists")).Block(Id("serviceName").Op("=").Id("environment").Op("+").Id("serviceName"))
+
+		g.Line().Id("provider").Op(":=").Qual(packageOTelSDK, "NewTracerProvider").Call(
+			Qual(packageOTelSDK, "WithBatcher").Call(Id("exporter")),
+			Qual(packageOTelSDK, "WithResource").Call(Qual(packageOTelResource, "NewSchemaless").Call(Qual(packageOTelAttribute, "String").Call(Lit("service.name"), Id("serviceName")))),
+		)
+		g.Id("srv").Dot("reporterCloser").Op("=").Id("tracerProviderCloser").Values(Dict{Id("provider"): Id("provider")})
+		g.Id("srv").Dot("tracerProvider").Op("=").Id("provider")
+		for _, serviceName := range tr.serviceKeys() {
+			g.If(Id("srv").Dot("http" + serviceName).Op("!=").Nil()).Block(
+				Id("srv").Dot("http" + serviceName).Dot("tracerProvider").Op("=").Id("provider"),
+			)
+		}
+		g.Line().Return(Id("srv"))
+	})
+}
+
+// tracerProviderFunc renders fallback to global provider, when provider is not set by option.
+func (tr Transport) tracerProviderFunc() Code {
+
+	return Func().Id("tracerOf").Params(Id("provider").Qual(packageOTelTrace, "TracerProvider")).Params(Qual(packageOTelTrace, "Tracer")).Block(
+		If(Id("provider").Op("==").Nil()).Block(
+			Id("provider").Op("=").Qual(packageOTel, "GetTracerProvider").Call(),
+		),
+		Return(Id("provider").Dot("Tracer").Call(Id("tracerName"))),
+	)
+}
+
+func (tr Transport) extractSpanOTelFunc() Code {
+
+	return Func().Id("extractSpan").
+		Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("provider").Qual(packageOTelTrace, "TracerProvider"), Id("opName").String(), tr.engine.requestParam()).
+		Params(Id("span").Qual(packageOTelTrace, "Span")).Block(
+
+		Line().Add(tr.requestHeadersCode()),
+
+		Line().Id("parent").Op(":=").Id("tracePropagator").Dot("Extract").Call(Qual(packageContext, "Background").Call(), Qual(packageOTelPropagation, "HeaderCarrier").Call(Id("headers"))),
+		List(Id("_"), Id("span")).Op("=").Id("tracerOf").Call(Id("provider")).Dot("Start").Call(Id("parent"), Id("opName"),
+			Qual(packageOTelTrace, "WithSpanKind").Call(Qual(packageOTelTrace, "SpanKindServer")),
+			Qual(packageOTelTrace, "WithAttributes").Call(
+				Qual(packageOTelAttribute, "String").Call(Lit("http.request.method"), tr.otelRequestAttr("method")),
+				Qual(packageOTelAttribute, "String").Call(Lit("url.full"), tr.requestURLCode()),
+				Qual(packageOTelAttribute, "String").Call(Lit("url.path"), tr.otelRequestAttr("path")),
+				Qual(packageOTelAttribute, "String").Call(Lit("user_agent.original"), tr.otelRequestAttr("userAgent")),
+				Qual(packageOTelAttribute, "String").Call(Lit("client.address"), tr.otelRequestAttr("clientAddress")),
+				Qual(packageOTelAttribute, "String").Call(Lit("requestID"), Id("requestID")),
+			),
+		),
+		tr.setRequestIDCode(),
+		Line().Return(),
+	)
+}
+
+func (tr Transport) injectSpanOTelFunc() Code {
+
+	return Func().Id("injectSpan").Params(append([]Code{Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOTelTrace, "Span")}, tr.engine.handlerParams()...)...).Block(
+
+		Line().Id("statusCode").Op(":=").Add(tr.engine.responseStatus()),
+		Id("span").Dot("SetAttributes").Call(Qual(packageOTelAttribute, "Int").Call(Lit("http.response.status_code"), Id("statusCode"))),
+		If(Id("statusCode").Op(">=").Add(tr.engine.status("StatusInternalServerError"))).Block(
+			Id("span").Dot("SetStatus").Call(Qual(packageOTelCodes, "Error"), tr.engine.statusText(Id("statusCode"))),
+		),
+
+		Line().Id("headers").Op(":=").Make(Qual(packageHttp, "Header")),
+		Id("tracePropagator").Dot("Inject").Call(Qual(packageOTelTrace, "ContextWithSpan").Call(Qual(packageContext, "Background").Call(), Id("span")), Qual(packageOTelPropagation, "HeaderCarrier").Call(Id("headers"))),
+		For(List(Id("key"), Id("values")).Op(":=").Range().Id("headers")).Block(
+			tr.engine.responseHeader("Del", Id("key")),
+			For(List(Id("_"), Id("value")).Op(":=").Range().Id("values")).Block(
+				tr.engine.responseHeader("Add", Id("key"), Id("value")),
+			),
+		),
+		tr.replyRequestIDCode(),
+	)
+}
+
+// otelRequestAttr renders value of span attribute of request.
+func (tr Transport) otelRequestAttr(name string) Code {
+
+	switch name {
+	case "method":
+		if tr.isNetHTTP() {
+			return Id("r").Dot("Method")
+		}
+		return String().Call(Id(_ctx_).Dot("Method").Call())
+	case "path":
+		if tr.isNetHTTP() {
+			return Id("r").Dot("URL").Dot("Path")
+		}
+		return String().Call(Id(_ctx_).Dot("Path").Call())
+	case "userAgent":
+		if tr.isNetHTTP() {
+			return Id("r").Dot("UserAgent").Call()
+		}
+		return String().Call(Id(_ctx_).Dot("UserAgent").Call())
+	}
+	if tr.isNetHTTP() {
+		return Id("remoteIP").Call(Id("r"))
+	}
+	return Id(_ctx_).Dot("RemoteIP").Call().Dot("String").Call()
+}
+
+// remoteIPFunc renders host of remote address of net/http request.
+func (tr Transport) remoteIPFunc() Code {
+
+	return Func().Id("remoteIP").Params(Id("r").Op("*").Qual(packageHttp, "Request")).String().Block(
+		List(Id("host"), Id("_"), Err()).Op(":=").Qual(packageNet, "SplitHostPort").Call(Id("r").Dot("RemoteAddr")),
+		If(Err().Op("!=").Nil()).Block(
+			Return(Id("r").Dot("RemoteAddr")),
+		),
+		Return(Id("host")),
+	)
+}
+
+// startSpanFunc renders start of child span by provider of parent span.
+func (tr Transport) startSpanFunc() Code {
+
+	return Func().Id("startSpan").Params(Id(_ctx_).Qual(packageContext, "Context"), Id("opName").String()).Params(Id("span").Qual(packageOTelTrace, "Span")).Block(
+		List(Id("_"), Id("span")).Op("=").Qual(packageOTelTrace, "SpanFromContext").Call(Id(_ctx_)).Dot("TracerProvider").Call().Dot("Tracer").Call(Id("tracerName")).Dot("Start").Call(Id(_ctx_), Id("opName")),
+		Return(),
+	)
+}
+
+// spanAttributeFunc renders conversion of tag value to typed attribute, like opentracing does for SetTag.
+func (tr Transport) spanAttributeFunc() Code {
+
+	return Func().Id("spanAttribute").Params(Id("key").String(), Id("value").Interface()).Params(Qual(packageOTelAttribute, "KeyValue")).Block(
+
+		Line().Switch(Id("v").Op(":=").Id("value").Assert(Type())).Block(
+			Case(String()).Block(Return(Qual(packageOTelAttribute, "String").Call(Id("key"), Id("v")))),
+			Case(Bool()).Block(Return(Qual(packageOTelAttribute, "Bool").Call(Id("key"), Id("v")))),
+			Case(Int()).Block(Return(Qual(packageOTelAttribute, "Int").Call(Id("key"), Id("v")))),
+			Case(Int64()).Block(Return(Qual(packageOTelAttribute, "Int64").Call(Id("key"), Id("v")))),
+			Case(Float64()).Block(Return(Qual(packageOTelAttribute, "Float64").Call(Id("key"), Id("v")))),
+			Case(Qual(packageFmt, "Stringer")).Block(Return(Qual(packageOTelAttribute, "String").Call(Id("key"), Id("v").Dot("String").Call()))),
+		),
+		Return(Qual(packageOTelAttribute, "String").Call(Id("key"), Qual(packageFmt, "Sprint").Call(Id("value")))),
+	)
+}
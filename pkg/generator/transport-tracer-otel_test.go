@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-tracer-otel_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import "testing"
+
+const otelVersion = "v1.35.0"
+
+// otelServices repeats services of example, JsonRPC interface is renamed,
+// because its client type collides with ClientJsonRPC.
+const otelServices = `package interfaces
+
+import (
+	"context"
+)
+
+// @tg jsonRPC-server log trace metrics
+type Calc interface {
+
+	// @tg arg1.type=string
+	// @tg arg1.format=uuid
+	// @tg arg0.min=1 arg0.max=100 arg1.required
+	Test(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (ret1 int, ret2 string, err error)
+}
+
+// @tg http-prefix=api/v2
+// @tg http-server log trace metrics
+type User interface {
+
+	// @tg http-method=PATCH
+	// @tg http-path=/user/custom/response
+	CustomResponse(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (err error)
+}
+`
+
+// otelCheck runs generated server and clients in one process,
+// spans of both are recorded by tracetest.SpanRecorder of options.
+const otelCheck = `package gentest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"gentest/clients"
+	"gentest/transport"
+)
+
+type calc struct{}
+
+func (calc) Test(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (ret1 int, ret2 string, err error) {
+	return arg0 * 2, arg1, nil
+}
+
+type user struct{}
+
+func (user) CustomResponse(ctx context.Context, arg0 int, arg1 string, opts ...interface{}) (err error) {
+	return nil
+}
+
+func TestTracing(t *testing.T) {
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	log := logrus.New()
+	log.SetLevel(logrus.ErrorLevel)
+	srv := transport.New(log, transport.TracerProvider(provider), 
+		transport.Calc(transport.NewCalc(log, calc{})),
+		transport.User(transport.NewUser(log, user{})),
+	)
+	go func() { _ = srv.ServeHTTP(address) }()
+	defer srv.Shutdown(context.Background())
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", address); err == nil {
+			_ = conn.Close()
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	ctx, root := provider.Tracer("test").Start(context.Background(), "root")
+	ret1, ret2, err := clients.New("client", log, "http://"+address, clients.TracerProvider(provider)).Calc().Test(ctx, 21, "6d9f2c2e-9b5a-4c1e-9d1a-111111111111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret1 != 42 || ret2 != "6d9f2c2e-9b5a-4c1e-9d1a-111111111111" {
+		t.Fatalf("unexpected result %d %s", ret1, ret2)
+	}
+	client, server := expectLinked(t, recorder, root, "calc.test")
+	expectAttributes(t, client, attribute.String("http.request.method", "POST"), attribute.String("url.full", "http://"+address+"/"))
+	expectAttributes(t, server, attribute.String("http.request.method", "POST"), attribute.String("url.path", "/"), attribute.Int("http.response.status_code", 200))
+
+	recorder.Reset()
+	if err = clients.NewHTTP("client", log, "http://"+address, clients.TracerProvider(provider)).User().CustomResponse(ctx, 1, "asc"); err != nil {
+		t.Fatal(err)
+	}
+	client, server = expectLinked(t, recorder, root)
+	root.End()
+	expectAttributes(t, client, attribute.String("http.request.method", "PATCH"), attribute.String("url.full", "http://"+address+"/api/v2/user/custom/response"))
+	expectAttributes(t, server, attribute.String("http.request.method", "PATCH"), attribute.String("url.path", "/api/v2/user/custom/response"), attribute.Int("http.response.status_code", 200))
+
+	request, err := http.NewRequest(http.MethodPost, "http://"+address+"/", strings.NewReader(` + "`" + `{"jsonrpc":"2.0","id":1,"method":"calc.test","params":{"arg0":1,"arg1":"6d9f2c2e-9b5a-4c1e-9d1a-111111111111"}}` + "`" + `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Close = true
+	request.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if values := response.Header.Values("traceparent"); len(values) != 1 || !strings.HasPrefix(values[0], "00-0af7651916cd43dd8448eb211c80319c-") {
+		t.Errorf("unexpected traceparent of response: %q", values)
+	}
+}
+
+// expectLinked returns client and server spans of call, which are linked with root span by traceparent.
+// Internal spans of call, like span of jsonRPC method, are children of server span.
+func expectLinked(t *testing.T, recorder *tracetest.SpanRecorder, root trace.Span, internal ...string) (client, server sdktrace.ReadOnlySpan) {
+
+	t.Helper()
+
+	var names []string
+	var children []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		names = append(names, span.SpanKind().String()+":"+span.Name())
+		if span.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("span %s has trace %s, want %s", span.Name(), span.SpanContext().TraceID(), root.SpanContext().TraceID())
+		}
+		switch span.SpanKind() {
+		case trace.SpanKindClient:
+			client = span
+		case trace.SpanKindServer:
+			server = span
+		default:
+			children = append(children, span)
+		}
+	}
+	if client == nil || server == nil || len(children) != len(internal) {
+		t.Fatalf("spans of call are not recorded: %q", names)
+	}
+	if client.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("client span %s is not child of root span", client.Name())
+	}
+	if !server.Parent().IsRemote() || server.Parent().SpanID() != client.SpanContext().SpanID() {
+		t.Errorf("server span %s is not child of client span by traceparent", server.Name())
+	}
+	for i, span := range children {
+		if span.Name() != internal[i] || span.Parent().SpanID() != server.SpanContext().SpanID() {
+			t.Errorf("span %s is not child %s of server span", span.Name(), internal[i])
+		}
+	}
+	return
+}
+
+func expectAttributes(t *testing.T, span sdktrace.ReadOnlySpan, attributes ...attribute.KeyValue) {
+
+	t.Helper()
+
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	for _, kv := range attributes {
+		if value, found := values[kv.Key]; !found || value != kv.Value {
+			t.Errorf("span %s has %s=%s, want %s", span.Name(), kv.Key, value.Emit(), kv.Value.Emit())
+		}
+	}
+}
+`
+
+// TestTracerOTel renders services with OpenTelemetry tracer and checks linkage of client and server spans.
+func TestTracerOTel(t *testing.T) {
+
+	var requires []string
+	for _, modPath := range []string{packageOTel, packageOTelTrace, packageOTelOTLPHttp, "go.opentelemetry.io/otel/sdk"} {
+		requires = append(requires, modPath+" "+otelVersion)
+	}
+	testGenerated(t, map[string]string{
+		"otel_test.go":       otelCheck,
+		"interfaces/calc.go": otelServices,
+	}, requires, WithTracer(TracerOpenTelemetry))
+}
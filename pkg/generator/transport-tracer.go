@@ -12,6 +12,13 @@ import (
 
 func (tr Transport) renderTracer(outDir string) (err error) {
 
+	if tr.tracer.isNone() {
+		return tr.renderTracerNone(outDir)
+	}
+	if tr.tracer.isOTel() {
+		return tr.renderTracerOTel(outDir)
+	}
+
 	srcFile := newSrc(filepath.Base(outDir))
 	srcFile.PackageComment(doNotEdit)
 
@@ -20,7 +27,9 @@ func (tr Transport) renderTracer(outDir string) (err error) {
 	srcFile.ImportName(packageLogrus, "logrus")
 	srcFile.ImportName(packageGotils, "gotils")
 	srcFile.ImportName(packageOpenZipkin, "zipkin")
-	srcFile.ImportName(packageFastHttp, "fasthttp")
+	if !tr.isNetHTTP() {
+		srcFile.ImportName(packageFastHttp, "fasthttp")
+	}
 	srcFile.ImportName(packageOpentracingExt, "ext")
 	srcFile.ImportAlias(packageOpentracing, "otg")
 	srcFile.ImportName(packageJaegerConfig, "config")
@@ -31,11 +40,18 @@ func (tr Transport) renderTracer(outDir string) (err error) {
 
 	srcFile.Const().Id("headerRequestID").Op("=").Lit("X-Request-Id")
 
-	srcFile.Line().Add(tr.traceJaegerFunc())
-	srcFile.Line().Add(tr.traceZipkinFunc())
+	if tr.tracer.withJaeger() {
+		srcFile.Line().Add(tr.traceJaegerFunc())
+	}
+	if tr.tracer.withZipkin() {
+		srcFile.Line().Add(tr.traceZipkinFunc())
+	}
 
 	srcFile.Line().Add(tr.injectSpanFunc())
 	srcFile.Line().Add(tr.extractSpanFunc())
+	if tr.isNetHTTP() {
+		srcFile.Line().Add(tr.requestURLFunc())
+	}
 
 	srcFile.Line().Add(tr.toStringFunc())
 
@@ -45,17 +61,10 @@ func (tr Transport) renderTracer(outDir string) (err error) {
 func (tr Transport) extractSpanFunc() Code {
 
 	return Func().Id("extractSpan").
-		Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("opName").String(), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).
+		Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("opName").String(), tr.engine.requestParam()).
 		Params(Id("span").Qual(packageOpentracing, "Span")).Block(
 
-		Line().Id("headers").Op(":=").Make(Qual(packageHttp, "Header")),
-		Id("requestID").Op(":=").Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Id("headerRequestID"))),
-		If(Id("requestID").Op("==").Lit("")).Block(
-			Id("requestID").Op("=").Qual(packageUUID, "NewV4").Call().Dot("String").Call(),
-		),
-		Id(_ctx_).Dot("Request").Dot("Header").Dot("VisitAll").Call(Func().Params(Id("key"), Id("value").Op("[]").Byte()).Block(
-			Id("headers").Dot("Set").Call(Qual(packageGotils, "B2S").Call(Id("key")), Qual(packageGotils, "B2S").Call(Id("value"))),
-		)),
+		Line().Add(tr.requestHeadersCode()),
 
 		Var().Id("opts").Op("[]").Qual(packageOpentracing, "StartSpanOption"),
 		List(Id("wireContext"), Err()).Op(":=").Qual(packageOpentracing, "GlobalTracer").Call().Dot("Extract").Call(Qual(packageOpentracing, "HTTPHeaders"), Qual(packageOpentracing, "HTTPHeadersCarrier").Call(Id("headers"))),
@@ -66,15 +75,76 @@ func (tr Transport) extractSpanFunc() Code {
 			Id("opts").Op("=").Append(Id("opts"), Qual(packageOpentracing, "ChildOf").Call(Id("wireContext"))),
 		),
 		Id("span").Op("=").Qual(packageOpentracing, "GlobalTracer").Call().Dot("StartSpan").Call(Id("opName"), Id("opts").Op("...")),
-		Line().Qual(packageOpentracingExt, "HTTPUrl").Dot("Set").Call(Id("span"), Id(_ctx_).Dot("URI").Call().Dot("String").Call()),
-		Qual(packageOpentracingExt, "HTTPMethod").Dot("Set").Call(Id("span"), Qual(packageGotils, "B2S").Call(Id(_ctx_).Dot("Method").Call())),
+		Line().Qual(packageOpentracingExt, "HTTPUrl").Dot("Set").Call(Id("span"), tr.requestURLCode()),
+		Qual(packageOpentracingExt, "HTTPMethod").Dot("Set").Call(Id("span"), tr.engine.requestMethod()),
 		Id("span").Dot("SetTag").Call(Lit("requestID"), Id("requestID")),
-		Id(_ctx_).Dot("Request").Dot("Header").Dot("Set").Call(Id("headerRequestID"), Id("requestID")),
-		Id(_ctx_).Dot("SetUserValue").Call(Id("headerRequestID"), Id("requestID")),
+		tr.setRequestIDCode(),
 		Line().Return(),
 	)
 }
 
+// requestIDCode renders request ID of request, new ID is generated for request without it.
+func (tr Transport) requestIDCode() Code {
+
+	return Id("requestID").Op(":=").Add(tr.engine.requestHeader(Id("headerRequestID"))).Line().
+		If(Id("requestID").Op("==").Lit("")).Block(
+		Id("requestID").Op("=").Qual(packageUUID, "NewV4").Call().Dot("String").Call(),
+	)
+}
+
+// setRequestIDCode renders saving of request ID to request, so handlers and logs use same ID.
+func (tr Transport) setRequestIDCode() Code {
+
+	if tr.isNetHTTP() {
+		return Id("r").Dot("Header").Dot("Set").Call(Id("headerRequestID"), Id("requestID"))
+	}
+	return Id(_ctx_).Dot("Request").Dot("Header").Dot("Set").Call(Id("headerRequestID"), Id("requestID")).Line().
+		Id(_ctx_).Dot("SetUserValue").Call(Id("headerRequestID"), Id("requestID"))
+}
+
+// replyRequestIDCode renders copy of request ID to response.
+func (tr Transport) replyRequestIDCode() Code {
+
+	if tr.isNetHTTP() {
+		return Id("w").Dot("Header").Call().Dot("Set").Call(Id("headerRequestID"), Id("r").Dot("Header").Dot("Get").Call(Id("headerRequestID")))
+	}
+	return Id(_ctx_).Dot("Response").Dot("Header").Dot("SetBytesV").Call(Id("headerRequestID"), Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Id("headerRequestID")))
+}
+
+// requestHeadersCode renders headers of request, which carry context of span, and request ID.
+func (tr Transport) requestHeadersCode() Code {
+
+	if tr.isNetHTTP() {
+		return Id("headers").Op(":=").Id("r").Dot("Header").Line().Add(tr.requestIDCode())
+	}
+	return Id("headers").Op(":=").Make(Qual(packageHttp, "Header")).Line().
+		Add(tr.requestIDCode()).Line().
+		Id(_ctx_).Dot("Request").Dot("Header").Dot("VisitAll").Call(Func().Params(Id("key"), Id("value").Op("[]").Byte()).Block(
+		Id("headers").Dot("Set").Call(Qual(packageGotils, "B2S").Call(Id("key")), Qual(packageGotils, "B2S").Call(Id("value"))),
+	))
+}
+
+// requestURLCode renders full URL of request, net/http server keeps only path and query in URL of request.
+func (tr Transport) requestURLCode() Code {
+
+	if tr.isNetHTTP() {
+		return Id("requestURL").Call(Id("r"))
+	}
+	return Id(_ctx_).Dot("URI").Call().Dot("String").Call()
+}
+
+// requestURLFunc renders restore of full URL of net/http request.
+func (tr Transport) requestURLFunc() Code {
+
+	return Func().Id("requestURL").Params(Id("r").Op("*").Qual(packageHttp, "Request")).String().Block(
+		Id("scheme").Op(":=").Lit("http"),
+		If(Id("r").Dot("TLS").Op("!=").Nil()).Block(
+			Id("scheme").Op("=").Lit("https"),
+		),
+		Return(Id("scheme").Op("+").Lit("://").Op("+").Id("r").Dot("Host").Op("+").Id("r").Dot("URL").Dot("RequestURI").Call()),
+	)
+}
+
 func (tr Transport) toStringFunc() Code {
 
 	return Func().Id("toString").Params(Id("value").Interface()).String().Block(
@@ -85,7 +155,7 @@ func (tr Transport) toStringFunc() Code {
 
 func (tr Transport) injectSpanFunc() Code {
 
-	return Func().Id("injectSpan").Params(Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOpentracing, "Span"), Id(_ctx_).Op("*").Qual(packageFastHttp, "RequestCtx")).Block(
+	return Func().Id("injectSpan").Params(append([]Code{Id("log").Qual(packageLogrus, "FieldLogger"), Id("span").Qual(packageOpentracing, "Span")}, tr.engine.handlerParams()...)...).Block(
 
 		Line().Id("headers").Op(":=").Make(Qual(packageHttp, "Header")),
 		If(Err().Op(":=").Qual(packageOpentracing, "GlobalTracer").Call().
@@ -97,9 +167,9 @@ func (tr Transport) injectSpanFunc() Code {
 			Id("log").Dot("WithError").Call(Err()).Dot("Debug").Call(Lit("inject span to HTTP headers")),
 		),
 		For(List(Id("key"), Id("values")).Op(":=").Range().Id("headers")).Block(
-			Id(_ctx_).Dot("Response").Dot("Header").Dot("Set").Call(Id("key"), Qual(packageStrings, "Join").Call(Id("values"), Lit(";"))),
+			tr.engine.responseHeader("Set", Id("key"), Qual(packageStrings, "Join").Call(Id("values"), Lit(";"))),
 		),
-		Id(_ctx_).Dot("Response").Dot("Header").Dot("SetBytesV").Call(Id("headerRequestID"), Id(_ctx_).Dot("Request").Dot("Header").Dot("Peek").Call(Id("headerRequestID"))),
+		tr.replyRequestIDCode(),
 	)
 }
 
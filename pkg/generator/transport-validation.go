@@ -0,0 +1,474 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-validation.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/sirupsen/logrus"
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/tags"
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+const tagValidate = "validate"
+
+var numberTypes = utils.SliceStringToMap([]string{
+	"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune", "float32", "float64",
+})
+
+type constraints struct {
+	required bool
+	min      string
+	max      string
+	pattern  string
+	enum     []string
+}
+
+// varConstraints merges constraints from annotations of variable and 'validate' tag of struct field.
+func varConstraints(varTags tags.DocTags, structTags map[string][]string) (c constraints) {
+
+	c.required = varTags.IsSet(tagRequired)
+	c.min = varTags.Value(tagMin)
+	c.max = varTags.Value(tagMax)
+	c.pattern = varTags.Value(tagPattern)
+	if enum := varTags.Value(tagEnum); enum != "" {
+		for _, item := range strings.Split(enum, ",") {
+			c.enum = append(c.enum, strings.TrimSpace(item))
+		}
+	}
+
+	for _, rule := range structTags[tagValidate] {
+
+		tokens := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+		if len(tokens) == 1 {
+			tokens = append(tokens, "")
+		}
+		switch tokens[0] {
+		case tagRequired:
+			c.required = true
+		case tagMin:
+			c.min = tokens[1]
+		case tagMax:
+			c.max = tokens[1]
+		case "oneof":
+			c.enum = strings.Fields(tokens[1])
+		}
+	}
+	return
+}
+
+type valueKind int
+
+const (
+	valueOther valueKind = iota
+	valueString
+	valueNumber
+	valueBool
+	valueList
+	valueNil
+	valuePointer
+	valueStruct
+)
+
+type valueInfo struct {
+	kind     valueKind
+	pkgPath  string
+	typeName string
+	next     types.Type
+	strct    types.Struct
+}
+
+type validator struct {
+	log logrus.FieldLogger
+
+	depth    int
+	patterns []string
+	named    map[string]valueInfo
+	structs  map[string]string
+	fnNames  map[string]struct{}
+	funcs    []Code
+}
+
+func newValidator(log logrus.FieldLogger) *validator {
+
+	return &validator{
+		log:     log,
+		named:   make(map[string]valueInfo),
+		structs: make(map[string]string),
+		fnNames: make(map[string]struct{}),
+	}
+}
+
+// renderValidation renders validate methods of request types and marks methods which have it.
+func (tr Transport) renderValidation(outDir string) (err error) {
+
+	srcFile := newSrc(filepath.Base(outDir))
+	srcFile.PackageComment(doNotEdit)
+
+	v := newValidator(tr.log)
+
+	srcFile.Line().Type().Id("validationError").Struct(
+		Id("Field").String().Tag(map[string]string{"json": "field"}),
+		Id("Reason").String().Tag(map[string]string{"json": "reason"}),
+	)
+	srcFile.Line().Func().Params(Id("e").Id("validationError")).Id("Error").Params().String().Block(
+		Return(Id("e").Dot("Field").Op("+").Lit(": ").Op("+").Id("e").Dot("Reason")),
+	)
+
+	for _, serviceName := range tr.serviceKeys() {
+		for _, method := range tr.services[serviceName].methods {
+
+			var body []Code
+			for _, field := range method.fieldsArgument() {
+				c := varConstraints(method.tags.Sub(field.Name), field.Tags)
+				body = append(body, v.check(v.valueOf(method.svc.pkgPath, field.Type), Id("request").Dot(utils.ToCamel(field.Name)), Lit(field.Name), c)...)
+			}
+			if method.hasValidation = len(body) != 0; method.hasValidation {
+				srcFile.Line().Func().Params(Id("request").Id(method.requestStructName())).Id("validate").Params().Params(Err().Error()).Block(
+					append(body, Return())...,
+				)
+			}
+		}
+	}
+	for _, fn := range v.funcs {
+		srcFile.Line().Add(fn)
+	}
+	if len(v.patterns) != 0 {
+		srcFile.Line().Var().DefsFunc(func(dg *Group) {
+			for i, pattern := range v.patterns {
+				dg.Id(fmt.Sprintf("validatePattern%d", i)).Op("=").Qual(packageRegexp, "MustCompile").Call(Lit(pattern))
+			}
+		})
+	}
+	return srcFile.Save(path.Join(outDir, "validation.go"))
+}
+
+// valueOf resolves kind of value, named types are resolved to its underlying types.
+func (v *validator) valueOf(pkgPath string, vType types.Type) (info valueInfo) {
+
+	info = valueInfo{kind: valueOther, pkgPath: pkgPath}
+
+	switch vType := vType.(type) {
+
+	case types.TName:
+
+		if _, found := numberTypes[vType.TypeName]; found {
+			info.kind = valueNumber
+			info.typeName = vType.TypeName
+			return
+		}
+		switch vType.TypeName {
+		case "string":
+			info.kind = valueString
+		case "bool":
+			info.kind = valueBool
+		case "error":
+			info.kind = valueNil
+		default:
+			if !types.IsBuiltin(vType) {
+				return v.namedValue(pkgPath, vType.TypeName)
+			}
+		}
+
+	case types.TImport:
+
+		if next, ok := vType.Next.(types.TName); ok {
+			return v.namedValue(vType.Import.Package, next.TypeName)
+		}
+
+	case types.TPointer:
+
+		info.kind = valuePointer
+		info.next = vType.Next
+
+	case types.TArray:
+
+		if vType.IsSlice {
+			info.kind = valueList
+			info.next = vType.Next
+		}
+
+	case types.TEllipsis:
+
+		info.kind = valueList
+		info.next = vType.Next
+
+	case types.TMap:
+
+		info.kind = valueList
+		info.next = vType.Value
+
+	case types.TInterface:
+
+		info.kind = valueNil
+	}
+	return
+}
+
+func (v *validator) namedValue(pkgPath, typeName string) (info valueInfo) {
+
+	key := pkgPath + "." + typeName
+	if info, found := v.named[key]; found {
+		return info
+	}
+	defer func() { v.named[key] = info }()
+
+	// prevents endless recursion on types like 'type List []List'
+	v.named[key] = valueInfo{kind: valueOther, pkgPath: pkgPath}
+
+	switch nextType := searchType(pkgPath, typeName).(type) {
+	case nil:
+		return valueInfo{kind: valueOther, pkgPath: pkgPath}
+	case types.Struct:
+		return valueInfo{kind: valueStruct, pkgPath: pkgPath, typeName: typeName, strct: nextType}
+	case types.TInterface:
+		return valueInfo{kind: valueNil, pkgPath: pkgPath}
+	default:
+		return v.valueOf(pkgPath, nextType)
+	}
+}
+
+// check returns statements which validate value by constraints and walk into nested structures.
+func (v *validator) check(info valueInfo, value *Statement, field Code, c constraints) (code []Code) {
+
+	val := func() *Statement { return value.Clone() }
+	invalid := func(format string, args ...interface{}) Code {
+		return Return(Id("validationError").Values(Dict{Id("Field"): field, Id("Reason"): Lit(fmt.Sprintf(format, args...))}))
+	}
+	ifInvalid := func(cond *Statement, format string, args ...interface{}) {
+		code = append(code, If(cond).Block(invalid(format, args...)))
+	}
+
+	if info.kind == valuePointer {
+
+		if c.required {
+			ifInvalid(val().Op("==").Nil(), "is required")
+		}
+		c.required = false
+		// absent optional value is valid, constraints are checked for present value only
+		if inner := v.check(v.valueOf(info.pkgPath, info.next), Op("*").Add(val()), field, c); len(inner) != 0 {
+			code = append(code, If(val().Op("!=").Nil()).Block(inner...))
+		}
+		return
+	}
+
+	if c.required {
+		switch info.kind {
+		case valueString:
+			ifInvalid(val().Op("==").Lit(""), "is required")
+		case valueNumber:
+			ifInvalid(val().Op("==").Lit(0), "is required")
+		case valueBool:
+			ifInvalid(Op("!").Add(val()), "is required")
+		case valueList:
+			ifInvalid(Len(val()).Op("==").Lit(0), "is required")
+		case valueNil:
+			ifInvalid(val().Op("==").Nil(), "is required")
+		default:
+			ifInvalid(Qual(packageReflect, "ValueOf").Call(val()).Dot("IsZero").Call(), "is required")
+		}
+	}
+
+	switch info.kind {
+
+	case valueString, valueList:
+
+		if limit, ok := v.length(c.min); ok {
+			ifInvalid(Len(val()).Op("<").Lit(limit), "length must be at least %d", limit)
+		}
+		if limit, ok := v.length(c.max); ok {
+			ifInvalid(Len(val()).Op(">").Lit(limit), "length must be at most %d", limit)
+		}
+
+	case valueNumber:
+
+		if v.number(info.typeName, c.min) {
+			ifInvalid(val().Op("<").Op(c.min), "must be at least %s", c.min)
+		}
+		if v.number(info.typeName, c.max) {
+			ifInvalid(val().Op(">").Op(c.max), "must be at most %s", c.max)
+		}
+	}
+
+	if c.pattern != "" && info.kind == valueString {
+		if pattern := v.pattern(c.pattern); pattern != "" {
+			ifInvalid(Op("!").Id(pattern).Dot("MatchString").Call(String().Call(val())), "must match pattern %s", c.pattern)
+		}
+	}
+
+	if len(c.enum) != 0 && (info.kind == valueString || info.kind == valueNumber) {
+
+		cond := &Statement{}
+		for i, item := range c.enum {
+
+			if i > 0 {
+				cond.Op("&&")
+			}
+			if info.kind == valueString {
+				cond.Add(val().Op("!=").Lit(item))
+			} else if v.number(info.typeName, item) {
+				cond.Add(val().Op("!=").Op(item))
+			} else {
+				cond = nil
+				break
+			}
+		}
+		if cond != nil {
+			ifInvalid(cond, "must be one of %s", strings.Join(c.enum, ", "))
+		}
+	}
+
+	switch info.kind {
+
+	case valueStruct:
+
+		if fn := v.structValidator(info); fn != "" {
+			code = append(code, If(Err().Op("=").Id(fn).Call(field, val()).Op(";").Err().Op("!=").Nil()).Block(Return()))
+		}
+
+	case valueList:
+
+		v.depth++
+		defer func() { v.depth-- }()
+
+		key, item := fmt.Sprintf("i%d", v.depth), fmt.Sprintf("item%d", v.depth)
+		itemField := Qual(packageFmt, "Sprintf").Call(Lit("%s[%v]"), field, Id(key))
+		if inner := v.check(v.valueOf(info.pkgPath, info.next), Id(item), itemField, constraints{}); len(inner) != 0 {
+			code = append(code, For(List(Id(key), Id(item)).Op(":=").Range().Add(val())).Block(inner...))
+		}
+	}
+	return
+}
+
+// structValidator returns name of function which validates fields of structure, empty name means nothing to validate.
+func (v *validator) structValidator(info valueInfo) (fnName string) {
+
+	key := info.pkgPath + "." + info.typeName
+	if fnName, found := v.structs[key]; found {
+		return fnName
+	}
+
+	fnName = "validate" + utils.ToCamel(path.Base(info.pkgPath)) + info.typeName
+	if _, found := v.fnNames[fnName]; found {
+		// same type name in other package with same name
+		fnName += pkgHash(info.pkgPath)
+	}
+	v.fnNames[fnName] = struct{}{}
+	v.structs[key] = fnName
+
+	depth := v.depth
+	v.depth = 0
+	defer func() { v.depth = depth }()
+
+	var body []Code
+	for _, field := range info.strct.Fields {
+
+		if field.Name == "" || !isExported(field.Name) {
+			continue
+		}
+		fieldName := jsonName(field)
+		if fieldName == "-" {
+			fieldName = field.Name
+		}
+		c := varConstraints(tags.ParseTags(field.Docs), field.Tags)
+		body = append(body, v.check(v.valueOf(info.pkgPath, field.Type), Id("v").Dot(field.Name), Id("field").Op("+").Lit("."+fieldName), c)...)
+	}
+	if len(body) == 0 {
+		v.structs[key] = ""
+		return ""
+	}
+	v.funcs = append(v.funcs, Func().Id(fnName).Params(Id("field").String(), Id("v").Qual(info.pkgPath, info.typeName)).Params(Err().Error()).Block(
+		append(body, Return())...,
+	))
+	return
+}
+
+func (v *validator) pattern(pattern string) string {
+
+	if _, err := regexp.Compile(pattern); err != nil {
+		v.log.WithError(err).Warningf("skip invalid pattern '%s'", pattern)
+		return ""
+	}
+	for i, known := range v.patterns {
+		if known == pattern {
+			return fmt.Sprintf("validatePattern%d", i)
+		}
+	}
+	v.patterns = append(v.patterns, pattern)
+	return fmt.Sprintf("validatePattern%d", len(v.patterns)-1)
+}
+
+func (v *validator) length(value string) (length int, ok bool) {
+
+	if value == "" {
+		return
+	}
+	var err error
+	if length, err = strconv.Atoi(value); err != nil || length < 0 {
+		v.log.Warningf("skip invalid length '%s'", value)
+		return 0, false
+	}
+	return length, true
+}
+
+func (v *validator) number(typeName, value string) bool {
+
+	if value == "" {
+		return false
+	}
+	if err := numberError(typeName, value); err != nil {
+		v.log.WithError(err).Warning("skip invalid number")
+		return false
+	}
+	return true
+}
+
+// numberError checks that value is literal of number type, otherwise comparison with it does not compile.
+func numberError(typeName, value string) (err error) {
+
+	// zero size of int, uint and uintptr means size of int
+	bitSize, _ := strconv.Atoi(strings.TrimLeft(typeName, "abcdefghijklmnopqrstuvwxyz"))
+	switch typeName {
+	case "byte":
+		_, err = strconv.ParseUint(value, 10, 8)
+	case "rune":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "float32", "float64":
+		_, err = strconv.ParseFloat(value, bitSize)
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr":
+		_, err = strconv.ParseUint(value, 10, bitSize)
+	default:
+		_, err = strconv.ParseInt(value, 10, bitSize)
+	}
+	if err != nil {
+		return fmt.Errorf("'%s' is not %s value", value, typeName)
+	}
+	return
+}
+
+// pkgHash returns short hash of package path, which distinguishes packages with same name.
+func pkgHash(pkgPath string) string {
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(pkgPath))
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// validateRequest returns validation of request variable or nothing if method has no constraints.
+func (m method) validateRequest(onError ...Code) Code {
+
+	if !m.hasValidation {
+		return Null()
+	}
+	return Line().If(Err().Op("=").Id("request").Dot("validate").Call().Op(";").Err().Op("!=").Nil()).Block(onError...)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport-validation_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNumberError(t *testing.T) {
+
+	tests := []struct {
+		typeName string
+		value    string
+		valid    bool
+	}{
+		{typeName: "int", value: "-1", valid: true},
+		{typeName: "int", value: "1.5"},
+		{typeName: "int8", value: "127", valid: true},
+		{typeName: "int8", value: "128"},
+		{typeName: "uint", value: "0", valid: true},
+		{typeName: "uint", value: "-1"},
+		{typeName: "uint16", value: "65536"},
+		{typeName: "byte", value: "255", valid: true},
+		{typeName: "byte", value: "256"},
+		{typeName: "rune", value: "65", valid: true},
+		{typeName: "uintptr", value: "1", valid: true},
+		{typeName: "float32", value: "1.5", valid: true},
+		{typeName: "float32", value: "1e39"},
+		{typeName: "float64", value: "-1e300", valid: true},
+		{typeName: "float64", value: "abc"},
+	}
+	for _, test := range tests {
+		if err := numberError(test.typeName, test.value); (err == nil) != test.valid {
+			t.Errorf("numberError(%s, %s) = %v, want valid %v", test.typeName, test.value, err, test.valid)
+		}
+	}
+}
+
+func TestRenderValidation(t *testing.T) {
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module validtest\n",
+		"a/types/filter.go": `package types
+
+type Filter struct {
+	// @tg enum=x,y
+	Kind string ` + "`json:\"kind\"`" + `
+}
+`,
+		"b/types/filter.go": `package types
+
+type Filter struct {
+	Size int ` + "`json:\"size\" validate:\"max=10\"`" + `
+}
+`,
+		"interfaces/valid.go": `package interfaces
+
+import (
+	"context"
+
+	a "validtest/a/types"
+	b "validtest/b/types"
+)
+
+// @tg jsonRPC-server
+type Valid interface {
+	// @tg count.min=1.5 size.min=-1 limit.max=100 sort.enum=asc,desc
+	Find(ctx context.Context, count int, size uint, limit *int, sort *string, filterA a.Filter, filterB b.Filter) (err error)
+}
+`,
+	})
+
+	// local packages are resolved from root of module
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	tr, err := NewTransport(log, filepath.Join(dir, "interfaces"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outDir := filepath.Join(dir, "transport")
+	if err = os.MkdirAll(outDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.renderValidation(outDir); err != nil {
+		t.Fatal(err)
+	}
+	generated, err := ioutil.ReadFile(filepath.Join(outDir, "validation.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(generated)
+
+	for _, want := range []string{
+		"if request.Limit != nil {\n\t\tif *request.Limit > 100 {",
+		"if request.Sort != nil {\n\t\tif *request.Sort != \"asc\" && *request.Sort != \"desc\" {",
+		"func validateTypesFilter(",
+		"func validateTypesFilter" + pkgHash("validtest/b/types") + "(",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("validation.go does not contain %q:\n%s", want, src)
+		}
+	}
+	for _, unwanted := range []string{"request.Count <", "request.Size <"} {
+		if strings.Contains(src, unwanted) {
+			t.Errorf("validation.go contains invalid constraint %q:\n%s", unwanted, src)
+		}
+	}
+
+	diagnostics, err := tr.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, diagnostic.Message)
+	}
+	for _, want := range []string{"invalid 'count.min' value: '1.5' is not int value", "invalid 'size.min' value: '-1' is not uint value"} {
+		if !strings.Contains(strings.Join(messages, "\n"), want) {
+			t.Errorf("check does not report %q: %q", want, messages)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+
+	t.Helper()
+
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package generator
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
@@ -47,11 +48,47 @@ const (
 	tagHttpResponse  = "http-response"
 	tagPackageUUID   = "uuidPackage"
 	tagSwaggerTags   = "swaggerTags"
+	tagServerGRPC    = "grpc-server"
+	tagGRPCPackage   = "grpc-package"
+	tagTimeout       = "timeout"
+	tagRequired      = "required"
+	tagMin           = "min"
+	tagMax           = "max"
+	tagPattern       = "pattern"
+	tagEnum          = "enum"
+	tagLogSkip       = "log-skip"
+	tagLogLevel      = "log-level"
+	tagLogErrorLevel = "log-error-level"
+	tagAudience      = "swagger-audience"
+	tagOpenAPI       = "openapi"
+	tagRPCDiscover   = "rpc-discover"
+	tagAuth          = "auth"
+	tagRoles         = "roles"
+	tagRateLimit     = "rate-limit"
+	tagBurst         = "burst"
+	tagMaxConcurrent = "max-concurrent"
+	tagLimitKey      = "limit-key"
+	tagEngine        = "http-engine"
+	tagCorsOrigins   = "cors-origins"
+	tagCorsMethods   = "cors-methods"
+	tagCorsHeaders   = "cors-headers"
+	tagCorsExpose    = "cors-expose"
+	tagCorsCreds     = "cors-credentials"
+	tagCorsMaxAge    = "cors-max-age"
+	tagCompress      = "compress"
 )
 
 type Transport struct {
+	hasHTTP    bool
+	hasGRPC    bool
 	hasJsonRPC bool
+	svcDir     string
+	pkgDir     string
 	tags       tags.DocTags
+	tracer     tracing
+	openAPI    string
+	json       bool
+	engine     httpEngine
 	log        logrus.FieldLogger
 	services   map[string]*service
 }
@@ -60,6 +97,16 @@ func NewTransport(log logrus.FieldLogger, svcDir string, options ...Option) (tr
 
 	tr.log = log
 	tr.services = make(map[string]*service)
+	tr.svcDir, _ = filepath.Abs(svcDir)
+
+	var defaults service
+	for _, option := range options {
+		option(&defaults)
+	}
+	tr.tracer = defaults.tracer
+	tr.openAPI = defaults.openAPI
+	tr.json = defaults.swaggerJSON
+	tr.engine = defaults.engine
 
 	var files []os.FileInfo
 	if files, err = ioutil.ReadDir(svcDir); err != nil {
@@ -91,14 +138,64 @@ func NewTransport(log logrus.FieldLogger, svcDir string, options ...Option) (tr
 				if service.tags.Contains(tagServerJsonRPC) {
 					tr.hasJsonRPC = true
 				}
+				if service.tags.Contains(tagServerHTTP) {
+					tr.hasHTTP = true
+				}
+				if service.tags.Contains(tagServerGRPC) {
+					tr.hasGRPC = true
+				}
 			}
 		}
 	}
+
+	// tracer of command line has priority over package annotation
+	if tr.tracer == "" {
+		tr.tracer = tracing(tr.tags.Value(tagTracer, TracerOpentracing))
+	}
+	for _, svc := range tr.services {
+		svc.tracer = tr.tracer
+	}
+	if tr.openAPI == "" {
+		tr.openAPI = tr.tags.Value(tagOpenAPI, OpenAPI30)
+	}
+	if tr.engine == "" {
+		tr.engine = httpEngine(tr.tags.Value(tagEngine, EngineFastHTTP))
+	}
+	for _, svc := range tr.services {
+		svc.engine = tr.engine
+	}
+	if tr.hasCORS() {
+		for _, svc := range tr.services {
+			svc.cors = corsTags(tr.tags, svc.tags)
+		}
+	}
 	return
 }
 
-func (tr Transport) RenderSwagger(outDir string) (err error) {
-	return newSwagger(&tr).render(outDir)
+// RenderSwagger renders documentation of interfaces, all interfaces are documented if ifaces is empty.
+func (tr Transport) RenderSwagger(outFile string, ifaces ...string) (err error) {
+	return tr.RenderSwaggerAudience(outFile, "", ifaces...)
+}
+
+// RenderSwaggerAudience renders documentation of methods, which are annotated by audience or have no audience.
+func (tr Transport) RenderSwaggerAudience(outFile, audience string, ifaces ...string) (err error) {
+
+	if _, found := openAPIVersions[tr.openAPI]; !found {
+		return fmt.Errorf("unknown OpenAPI version '%s'", tr.openAPI)
+	}
+	doc := newSwagger(&tr)
+	doc.audience = audience
+	for _, iface := range ifaces {
+		if _, found := tr.services[iface]; !found {
+			return fmt.Errorf("interface '%s' not found", iface)
+		}
+		doc.ifaces[iface] = struct{}{}
+	}
+	return doc.render(outFile)
+}
+
+func (tr Transport) RenderTypeScript(outDir string) (err error) {
+	return newTypescript(&tr).render(outDir)
 }
 
 func (tr Transport) serviceKeys() (keys []string) {
@@ -112,45 +209,96 @@ func (tr Transport) serviceKeys() (keys []string) {
 
 func (tr Transport) RenderClient(outDir string) (err error) {
 
+	if err = tr.tracerError(); err != nil {
+		return
+	}
+	if err = tr.engineError(); err != nil {
+		return
+	}
 	tr.cleanup(outDir)
+
+	var errs renderErrors
+
 	if err = os.MkdirAll(outDir, 0777); err != nil {
 		return
 	}
-	showError(tr.log, tr.renderClientTracer(outDir), "renderHTTP")
-	showError(tr.log, tr.renderClientOptions(outDir), "renderHTTP")
+	errs.add(tr.log, tr.renderClientTracer(outDir), "renderClientTracer")
+	errs.add(tr.log, tr.renderClientOptions(outDir), "renderClientOptions")
+	errs.add(tr.log, tr.renderClientCompress(outDir), "renderClientCompress")
 	if tr.hasJsonRPC {
-		showError(tr.log, tr.renderClientJsonRPC(outDir), "renderHTTP")
+		errs.add(tr.log, tr.renderClientJsonRPC(outDir), "renderClientJsonRPC")
 	}
-	for _, svc := range tr.services {
-		showError(tr.log, svc.renderClient(outDir), "renderHTTP")
+	if tr.hasHTTP {
+		errs.add(tr.log, tr.renderClientHTTP(outDir), "renderClientHTTP")
 	}
-	return
+	for _, serviceName := range tr.serviceKeys() {
+		if err = tr.services[serviceName].renderClient(outDir); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", serviceName, err))
+		}
+	}
+	return errs.err()
 }
 
 func (tr Transport) RenderServer(outDir string) (err error) {
 
+	if err = tr.tracerError(); err != nil {
+		return
+	}
+	if err = tr.engineError(); err != nil {
+		return
+	}
 	tr.cleanup(outDir)
 
+	var errs renderErrors
+
 	if err = os.MkdirAll(outDir, 0777); err != nil {
 		return
 	}
 
-	showError(tr.log, tr.renderHTTP(outDir), "renderHTTP")
-	showError(tr.log, tr.renderErrors(outDir), "renderErrors")
-	showError(tr.log, tr.renderServer(outDir), "renderServer")
-	showError(tr.log, tr.renderTracer(outDir), "renderTracer")
-	showError(tr.log, tr.renderContext(outDir), "renderContext")
-	showError(tr.log, tr.renderMetrics(outDir), "renderMetrics")
-	showError(tr.log, tr.renderOptions(outDir), "renderOptions")
+	// marks methods with constraints, so must be rendered before handlers
+	errs.add(tr.log, tr.renderValidation(outDir), "renderValidation")
+	errs.add(tr.log, tr.renderHTTP(outDir), "renderHTTP")
+	errs.add(tr.log, tr.renderErrors(outDir), "renderErrors")
+	errs.add(tr.log, tr.renderServer(outDir), "renderServer")
+	errs.add(tr.log, tr.renderHealth(outDir), "renderHealth")
+	errs.add(tr.log, tr.renderTracer(outDir), "renderTracer")
+	errs.add(tr.log, tr.renderContext(outDir), "renderContext")
+	errs.add(tr.log, tr.renderMetrics(outDir), "renderMetrics")
+	errs.add(tr.log, tr.renderOptions(outDir), "renderOptions")
+	errs.add(tr.log, tr.renderCompress(outDir), "renderCompress")
+
+	if tr.hasLogger() {
+		errs.add(tr.log, tr.renderLogger(outDir), "renderLogger")
+	}
 
 	if tr.hasJsonRPC {
-		showError(tr.log, tr.renderJsonRPC(outDir), "renderJsonRPC")
+		errs.add(tr.log, tr.renderJsonRPC(outDir), "renderJsonRPC")
+	}
+	if tr.hasAuth() {
+		errs.add(tr.log, tr.renderAuth(outDir), "renderAuth")
+	}
+	if tr.hasLimits() {
+		errs.add(tr.log, tr.renderLimiter(outDir), "renderLimiter")
+	}
+	if tr.hasCORS() {
+		errs.add(tr.log, tr.renderCORS(outDir), "renderCORS")
+	}
+	if tr.isNetHTTP() {
+		errs.add(tr.log, tr.renderNetHTTP(outDir), "renderNetHTTP")
+	}
+	if tr.hasDiscover() {
+		errs.add(tr.log, tr.renderDiscover(outDir), "renderDiscover")
+	}
+	if tr.hasGRPC {
+		errs.add(tr.log, tr.renderGRPC(outDir), "renderGRPC")
 	}
 
-	for _, svc := range tr.services {
-		err = svc.render(outDir)
+	for _, serviceName := range tr.serviceKeys() {
+		if err = tr.services[serviceName].render(outDir); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", serviceName, err))
+		}
 	}
-	return
+	return errs.err()
 }
 
 func showError(log logrus.FieldLogger, err error, msg string) {
@@ -158,3 +306,19 @@ func showError(log logrus.FieldLogger, err error, msg string) {
 		log.WithError(err).Error(msg)
 	}
 }
+
+type renderErrors []string
+
+func (errs *renderErrors) add(log logrus.FieldLogger, err error, msg string) {
+	if err != nil {
+		showError(log, err, msg)
+		*errs = append(*errs, fmt.Sprintf("%s: %s", msg, err))
+	}
+}
+
+func (errs renderErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("render failed: %s", strings.Join(errs, "; "))
+}
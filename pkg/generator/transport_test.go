@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (transport_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/modfile"
+	"github.com/sirupsen/logrus"
+)
+
+// genprotoVersion splits googleapis out of genproto, old genproto of dependencies makes their imports ambiguous
+const genprotoVersion = "v0.0.0-20250603155806-513f23925822"
+
+// serveCheck helps tests of generated module to serve transport on free local port
+const serveCheck = `package gentest
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// freeAddress returns address of free local port
+func freeAddress(t *testing.T) string {
+
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+// waitServing waits until server accepts connections on address
+func waitServing(t *testing.T, address string) {
+
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", address); err == nil {
+			_ = conn.Close()
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("server is not serving on %s", address)
+}
+`
+
+// testGenerated renders transport and clients of interfaces into module 'gentest' and runs tests of module.
+// Files are paths relative to root of module, interfaces are expected in 'interfaces' directory,
+// requires are added to dependencies of tg, which generated code is built with.
+func testGenerated(t *testing.T, files map[string]string, requires []string, options ...Option) {
+
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not found")
+	}
+	modBytes, err := ioutil.ReadFile(filepath.Join("..", "..", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modFile, err := modfile.Parse("go.mod", modBytes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var modRequires strings.Builder
+	for _, require := range modFile.Require {
+		fmt.Fprintf(&modRequires, "\t%s %s\n", require.Mod.Path, require.Mod.Version)
+	}
+	fmt.Fprintf(&modRequires, "\tgoogle.golang.org/genproto %s\n", genprotoVersion)
+	for _, require := range requires {
+		fmt.Fprintf(&modRequires, "\t%s\n", require)
+	}
+	dir := t.TempDir()
+	files["serve_test.go"] = serveCheck
+	files["go.mod"] = fmt.Sprintf("module gentest\n\ngo 1.23\n\nrequire (\n%s)\n", modRequires.String())
+	writeFiles(t, dir, files)
+
+	// local packages are resolved from root of module
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	tr, err := NewTransport(log, filepath.Join(dir, "interfaces"), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.RenderServer(filepath.Join(dir, "transport")); err != nil {
+		t.Fatal(err)
+	}
+	if err = tr.RenderClient(filepath.Join(dir, "clients")); err != nil {
+		t.Fatal(err)
+	}
+
+	tidy := exec.Command(goBin, "mod", "tidy", "-e")
+	tidy.Dir = dir
+	if output, err := tidy.CombinedOutput(); err != nil {
+		t.Skipf("could not resolve dependencies of generated code: %s", output)
+	}
+	test := exec.Command(goBin, "test", "-count=1", "./...")
+	test.Dir = dir
+	if output, err := test.CombinedOutput(); err != nil {
+		t.Fatalf("generated code: %s\n%s", err, output)
+	}
+}
+
+// testTransport parses interfaces of module 'gentest' written from files, working directory is root of module until end of test.
+func testTransport(t *testing.T, files map[string]string, options ...Option) (tr Transport, dir string) {
+
+	t.Helper()
+
+	dir = t.TempDir()
+	if _, found := files["go.mod"]; !found {
+		files["go.mod"] = "module gentest\n"
+	}
+	writeFiles(t, dir, files)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	if tr, err = NewTransport(log, filepath.Join(dir, "interfaces"), options...); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
@@ -0,0 +1,401 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (typescript-client.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vetcher/go-astra/types"
+
+	ts "github.com/seniorGolang/tg/pkg/typescript"
+)
+
+func (gen *typescript) renderClient(outDir string) (err error) {
+
+	srcFile := ts.NewFile()
+	srcFile.PackageComment(doNotEdit)
+
+	srcFile.Line().NewLine().Export().Type().Id("Options").E().Block(
+		ts.Id("headers").Op("?").T().Id("Record<string, string>").Op(";"),
+		ts.Id("credentials").Op("?").T().Id("RequestCredentials").Op(";"),
+		ts.Id("fetch").Op("?").T().Typeof().Id("fetch").Op(";"),
+	).Op(";")
+
+	srcFile.Line().NewLine().Export().Type().Id("JsonRPCRequest").Generic("T = any").E().Block(
+		ts.Id("id").Op("?").T().Id("string").Op(";"),
+		ts.Id("jsonrpc").T().Id("string").Op(";"),
+		ts.Id("method").T().Id("string").Op(";"),
+		ts.Id("params").T().Id("T").Op(";"),
+	).Op(";")
+
+	srcFile.Line().NewLine().Export().Type().Id("JsonRPCErrorData").E().Block(
+		ts.Id("code").T().Id("number").Op(";"),
+		ts.Id("message").T().Id("string").Op(";"),
+		ts.Id("data").Op("?").T().Id("any").Op(";"),
+	).Op(";")
+
+	srcFile.Line().NewLine().Export().Type().Id("JsonRPCResponse").Generic("T = any").E().Block(
+		ts.Id("id").Op("?").T().Id("string").Op(";"),
+		ts.Id("jsonrpc").T().Id("string").Op(";"),
+		ts.Id("result").Op("?").T().Id("T").Op(";"),
+		ts.Id("error").Op("?").T().Id("JsonRPCErrorData").Op(";"),
+	).Op(";")
+
+	srcFile.Line().NewLine().Export().Class().Id("JsonRPCError").Op(" ").Extends().Id("Error").Op(" ").Block(
+		ts.Id("code").T().Id("number").Op(";"),
+		ts.Id("data").Op("?").T().Id("any").Op(";"),
+		ts.Line(),
+		ts.Id("constructor").Params(ts.Id("error").T().Id("JsonRPCErrorData")).Op(" ").Block(
+			ts.Id("super").Call(ts.Id("error").Dot("message")).Op(";"),
+			ts.Id("this").Dot("code").E().Id("error").Dot("code").Op(";"),
+			ts.Id("this").Dot("data").E().Id("error").Dot("data").Op(";"),
+		),
+	)
+
+	srcFile.Line().NewLine().Export().Class().Id("HTTPError").Op(" ").Extends().Id("Error").Op(" ").Block(
+		ts.Id("status").T().Id("number").Op(";"),
+		ts.Id("body").T().Id("string").Op(";"),
+		ts.Line(),
+		ts.Id("constructor").Params(ts.Id("status").T().Id("number"), ts.Id("body").T().Id("string")).Op(" ").Block(
+			ts.Id("super").Call(ts.Id("body").Op(" || ").Id("String").Call(ts.Id("status"))).Op(";"),
+			ts.Id("this").Dot("status").E().Id("status").Op(";"),
+			ts.Id("this").Dot("body").E().Id("body").Op(";"),
+		),
+	)
+
+	srcFile.Line().NewLine().Let().Id("requestID").E().Lit(0).Op(";")
+
+	srcFile.Line().NewLine().Export().Function().Id("newRequest").Generic("T").Params(ts.Id("method").T().Id("string"), ts.Id("params").T().Id("T")).T().Id("JsonRPCRequest").Generic("T").Op(" ").Block(
+		ts.Id("requestID").Op("++;"),
+		ts.Return(ts.Values(ts.Dict{
+			ts.Id("id"):      ts.Id("String").Call(ts.Id("requestID")),
+			ts.Id("jsonrpc"): ts.Lit("2.0"),
+			ts.Id("method"):  ts.Id("method"),
+			ts.Id("params"):  ts.Id("params"),
+		})).Op(";"),
+	)
+
+	srcFile.Line().NewLine().Export().Class().Id("BaseClient").Op(" ").Block(
+		ts.Id("url").T().Id("string").Op(";"),
+		ts.Id("options").T().Id("Options").Op(";"),
+		ts.Line(),
+		ts.Id("constructor").Params(ts.Id("url").T().Id("string"), ts.Id("options").T().Id("Options").E().Values()).Op(" ").Block(
+			ts.Id("this").Dot("url").E().Id("url").Op(";"),
+			ts.Id("this").Dot("options").E().Id("options").Op(";"),
+		),
+		ts.Line(),
+		gen.clientFetchFunc(),
+		ts.Line(),
+		gen.clientBatchFunc(),
+		ts.Line(),
+		ts.Async().Id("call").Generic("T").Params(ts.Id("request").T().Id("JsonRPCRequest")).T().Id("Promise").Generic("T").Op(" ").Block(
+			ts.Const().Id("responses").E().Await().Id("this").Dot("batch").Call(ts.Id("request")).Op(";"),
+			ts.If(ts.Parents(ts.Id("responses").Index(ts.Lit(0)).Dot("error"))).Op(" ").Block(
+				ts.Throw().New(ts.Id("JsonRPCError").Call(ts.Id("responses").Index(ts.Lit(0)).Dot("error"))).Op(";"),
+			),
+			ts.Return(ts.Id("responses").Index(ts.Lit(0)).Dot("result").As().Id("T")).Op(";"),
+		),
+	)
+
+	gen.log.Info("write to ", path.Join(outDir, "client.ts"))
+	return srcFile.Save(path.Join(outDir, "client.ts"))
+}
+
+func (gen *typescript) clientFetchFunc() ts.Code {
+
+	return ts.Async().Id("fetch").Params(ts.Id("path").T().Id("string"), ts.Id("init").T().Id("RequestInit")).T().Id("Promise").Generic("Response").Op(" ").Block(
+		ts.Const().Id("headers").E().New(ts.Id("Headers").Call(ts.Id("this").Dot("options").Dot("headers"))).Op(";"),
+		ts.New(ts.Id("Headers").Call(ts.Id("init").Dot("headers"))).Dot("forEach").Call(
+			ts.Params(ts.Id("value"), ts.Id("key")).Op(" => ").Id("headers").Dot("set").Call(ts.Id("key"), ts.Id("value")),
+		).Op(";"),
+		ts.Const().Id("doFetch").E().Id("this").Dot("options").Dot("fetch").Op(" || ").Id("fetch").Op(";"),
+		ts.Return(ts.Id("doFetch").Call(
+			ts.Id("this").Dot("url").Op(" + ").Id("path"),
+			ts.Values(ts.Op("...").Id("init"), ts.Id("headers"), ts.Id("credentials").T().Id("this").Dot("options").Dot("credentials").Op(" || ").Id("init").Dot("credentials")),
+		)).Op(";"),
+	)
+}
+
+func (gen *typescript) clientBatchFunc() ts.Code {
+
+	notFound := ts.Values(ts.Dict{
+		ts.Id("id"):      ts.Id("request").Dot("id"),
+		ts.Id("jsonrpc"): ts.Lit("2.0"),
+		ts.Id("error"): ts.Values(ts.Dict{
+			ts.Id("code"):    ts.Op("-32603"),
+			ts.Id("message"): ts.Lit("response not found"),
+		}),
+	})
+
+	return ts.Async().Id("batch").Params(ts.Op("...").Id("requests").T().Id("Array").Generic("JsonRPCRequest")).T().Id("Promise").Generic("Array<JsonRPCResponse>").Op(" ").Block(
+		ts.Const().Id("response").E().Await().Id("this").Dot("fetch").Call(ts.Lit(""), ts.Values(ts.Dict{
+			ts.Id("method"):  ts.Lit("POST"),
+			ts.Id("headers"): ts.Values(ts.Dict{ts.Lit("Content-Type"): ts.Lit(contentJSON)}),
+			ts.Id("body"):    ts.Id("JSON").Dot("stringify").Call(ts.Id("requests")),
+		})).Op(";"),
+		ts.If(ts.Parents(ts.Op("!").Id("response").Dot("ok"))).Op(" ").Block(
+			ts.Throw().New(ts.Id("HTTPError").Call(ts.Id("response").Dot("status"), ts.Await().Id("response").Dot("text").Call())).Op(";"),
+		),
+		ts.Const().Id("responses").T().Id("Array").Generic("JsonRPCResponse").E().Await().Id("response").Dot("json").Call().Op(";"),
+		ts.Return(ts.Id("requests").Dot("map").Call(
+			ts.Params(ts.Id("request")).Op(" => ").Id("responses").Dot("find").Call(
+				ts.Params(ts.Id("item")).Op(" => ").Id("item").Dot("id").Op(" === ").Id("request").Dot("id"),
+			).Op(" || ").Add(notFound),
+		)).Op(";"),
+	)
+}
+
+func (gen *typescript) renderService(outDir string, svc *service) (err error) {
+
+	srcFile := ts.NewFile()
+	srcFile.PackageComment(doNotEdit)
+
+	gen.usedTypes = make(map[string]struct{})
+
+	clientImports := []string{"BaseClient"}
+
+	srcFile.Line().NewLine().Export().Class().Id("Client" + svc.Name).Op(" ").BlockFunc(func(bg *ts.Group) {
+
+		bg.Id("client").T().Id("BaseClient").Op(";")
+		bg.Line()
+		bg.Id("constructor").Params(ts.Id("client").T().Id("BaseClient")).Op(" ").Block(
+			ts.Id("this").Dot("client").E().Id("client").Op(";"),
+		)
+
+		var hasJsonRPC, hasHTTP bool
+
+		for _, method := range svc.methods {
+
+			if method.isJsonRPC() {
+				hasJsonRPC = true
+				bg.Line()
+				bg.Add(gen.jsonrpcRequestFunc(svc, method))
+				bg.Line()
+				bg.Add(gen.jsonrpcMethodFunc(svc, method))
+			} else if method.isHTTP() && !method.tags.Contains(tagHandler) {
+				hasHTTP = true
+				bg.Line()
+				bg.Add(gen.httpMethodFunc(svc, method))
+			}
+		}
+		if hasJsonRPC {
+			clientImports = append(clientImports, "JsonRPCRequest", "newRequest")
+		}
+		if hasHTTP {
+			clientImports = append(clientImports, "HTTPError")
+		}
+	})
+
+	srcFile.Import("./client", clientImports...)
+
+	var typeNames []string
+	for typeName := range gen.usedTypes {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+	if len(typeNames) != 0 {
+		srcFile.Import("./types", typeNames...)
+	}
+
+	gen.log.Info("write to ", path.Join(outDir, svc.lcName()+".ts"))
+	return srcFile.Save(path.Join(outDir, svc.lcName()+".ts"))
+}
+
+func (gen *typescript) jsonrpcRequestFunc(svc *service, method *method) ts.Code {
+
+	requestType := gen.useType(tsTypeName(method.requestStructName()))
+
+	return ts.Id("req" + method.Name).Add(gen.methodParams(svc, method)).T().Id("JsonRPCRequest").Op("<").Add(requestType).Op(">").Op(" ").Block(
+		ts.Return(ts.Id("newRequest").Call(
+			ts.Lit(svc.lcName()+"."+method.lcName()),
+			gen.fieldsObject(method.fieldsArgument()),
+		)).Op(";"),
+	)
+}
+
+func (gen *typescript) jsonrpcMethodFunc(svc *service, method *method) ts.Code {
+
+	responseType := gen.useType(tsTypeName(method.responseStructName()))
+
+	return ts.Async().Id(method.lccName()).Add(gen.methodParams(svc, method)).T().Id("Promise").Op("<").Add(responseType).Op(">").Op(" ").Block(
+		ts.Return(ts.Id("this").Dot("client").Dot("call").Op("<").Add(responseType).Op(">").Call(
+			ts.Id("this").Dot("req" + method.Name).CallFunc(func(cg *ts.Group) {
+				for _, arg := range method.argsWithoutContext() {
+					if _, isCookie := method.argCookieMap()[arg.Name]; isCookie {
+						continue
+					}
+					if types.IsEllipsis(arg.Type) {
+						cg.Op("...").Id(arg.Name)
+						continue
+					}
+					cg.Id(arg.Name)
+				}
+			}),
+		)).Op(";"),
+	)
+}
+
+func (gen *typescript) httpMethodFunc(svc *service, method *method) ts.Code {
+
+	var returnType ts.Code = gen.useType(tsTypeName(method.responseStructName()))
+
+	var downloadVar string
+	for _, retName := range sortedKeys(method.downloadVarsMap()) {
+		if ret := method.resultByName(retName); ret != nil && ret.Type.String() == "[]byte" {
+			downloadVar = retName
+			returnType = ts.Id("Blob")
+			break
+		}
+	}
+
+	return ts.Async().Id(method.lccName()).Add(gen.methodParams(svc, method)).T().Id("Promise").Op("<").Add(returnType).Op(">").Op(" ").BlockFunc(func(bg *ts.Group) {
+
+		bg.Const().Id("headers").T().Id("Record<string, string>").E().Values().Op(";")
+
+		uri := gen.httpURI(method)
+
+		if len(method.argParamMap()) != 0 {
+			bg.Const().Id("query").E().New(ts.Id("URLSearchParams").Call()).Op(";")
+			gen.argsToString(bg, method, method.argParamMap(), func(srcName string, value ts.Code) ts.Code {
+				return ts.Id("query").Dot("set").Call(ts.Lit(srcName), value).Op(";")
+			})
+			uri = ts.Add(uri).Op(" + ").Parents(ts.Id("query").Dot("toString").Call().Op(" ? ").Lit("?").Op(" + ").Id("query").Dot("toString").Call().Op(" : ").Lit(""))
+		}
+
+		gen.argsToString(bg, method, method.varHeaderMap(), func(srcName string, value ts.Code) ts.Code {
+			return ts.Id("headers").Index(ts.Lit(srcName)).E().Add(value).Op(";")
+		})
+
+		init := ts.Dict{
+			ts.Id("method"):  ts.Lit(method.httpMethod()),
+			ts.Id("headers"): ts.Id("headers"),
+		}
+		// browser forbids header 'Cookie', cookies of arguments are sent by browser, so they must be already set
+		if len(method.argCookieMap()) != 0 {
+			init[ts.Id("credentials")] = ts.Lit("include")
+		}
+
+		if len(method.uploadVarsMap()) != 0 {
+			bg.Const().Id("form").E().New(ts.Id("FormData").Call()).Op(";")
+			for _, uploadVar := range sortedKeys(method.uploadVarsMap()) {
+				if method.argByName(uploadVar) != nil {
+					uploadKey := method.uploadVarsMap()[uploadVar]
+					bg.Id("form").Dot("append").Call(ts.Lit(uploadKey), ts.Id(uploadVar), ts.Lit(uploadKey)).Op(";")
+				}
+			}
+			init[ts.Id("body")] = ts.Id("form")
+		} else if args := method.arguments(); len(args) != 0 {
+			bg.Id("headers").Index(ts.Lit("Content-Type")).E().Lit(contentJSON).Op(";")
+			init[ts.Id("body")] = ts.Id("JSON").Dot("stringify").Call(gen.fieldsObject(args))
+		}
+
+		bg.Const().Id("response").E().Await().Id("this").Dot("client").Dot("fetch").Call(uri, ts.Values(init)).Op(";")
+		bg.If(ts.Parents(ts.Id("response").Dot("status").Op(" !== ").Lit(method.tags.ValueInt(tagHttpSuccess, 200)))).Op(" ").Block(
+			ts.Throw().New(ts.Id("HTTPError").Call(ts.Id("response").Dot("status"), ts.Await().Id("response").Dot("text").Call())).Op(";"),
+		)
+		if downloadVar != "" {
+			bg.Return(ts.Id("response").Dot("blob").Call()).Op(";")
+			return
+		}
+		bg.Const().Id("text").E().Await().Id("response").Dot("text").Call().Op(";")
+		bg.Return(ts.Id("text").Op(" ? ").Id("JSON").Dot("parse").Call(ts.Id("text")).Op(" : ").Values()).Op(";")
+	})
+}
+
+func (gen *typescript) httpURI(method *method) ts.Code {
+
+	var parts []ts.Code
+	var static []string
+
+	for _, token := range strings.Split(method.httpPath(), "/") {
+
+		if strings.HasPrefix(token, "{") {
+
+			argName := strings.TrimSpace(strings.Replace(strings.TrimPrefix(token, "{"), "}", "", -1))
+
+			if method.argByName(strings.Split(argName, ".")[0]) != nil {
+				parts = append(parts, ts.Lit(strings.Join(append(static, ""), "/")))
+				parts = append(parts, ts.Id("encodeURIComponent").Call(ts.Id("String").Call(ts.Id(argName))))
+				static = []string{""}
+				continue
+			}
+		}
+		static = append(static, token)
+	}
+	if len(static) > 1 || len(parts) == 0 {
+		parts = append(parts, ts.Lit(strings.Join(static, "/")))
+	}
+	uri := &ts.Statement{}
+	for i, part := range parts {
+		if i > 0 {
+			uri.Op(" + ")
+		}
+		uri.Add(part)
+	}
+	return uri
+}
+
+func (gen *typescript) argsToString(bg *ts.Group, method *method, varMap map[string]string, setFn func(srcName string, value ts.Code) ts.Code) {
+
+	for _, argName := range sortedKeys(varMap) {
+
+		arg := method.argByName(strings.Split(argName, ".")[0])
+		if arg == nil {
+			continue
+		}
+		value := ts.Id("String").Call(ts.Id(argName))
+
+		if _, isPointer := arg.Type.(types.TPointer); isPointer {
+			bg.If(ts.Parents(ts.Id(argName).Op(" !== ").Id("undefined").Op(" && ").Id(argName).Op(" !== ").Id("null"))).Op(" ").Block(
+				setFn(varMap[argName], value),
+			)
+			continue
+		}
+		bg.Add(setFn(varMap[argName], value))
+	}
+}
+
+func (gen *typescript) methodParams(svc *service, method *method) ts.Code {
+
+	return ts.ParamsFunc(func(pg *ts.Group) {
+		for _, arg := range method.argsWithoutContext() {
+
+			if _, isCookie := method.argCookieMap()[arg.Name]; isCookie {
+				continue
+			}
+			param := ts.Id(arg.Name)
+			if types.IsEllipsis(arg.Type) {
+				param = ts.Op("...").Id(arg.Name)
+			}
+			pg.Add(param.T().Add(gen.argType(svc.pkgPath, method, arg)))
+		}
+	})
+}
+
+func (gen *typescript) fieldsObject(fields []types.StructField) ts.Code {
+
+	dict := ts.Dict{}
+	for _, field := range fields {
+
+		fieldName := field.Name
+		if jsonTags, _ := field.Tags["json"]; len(jsonTags) > 0 && jsonTags[0] != "" {
+			fieldName = jsonTags[0]
+		}
+		if fieldName == "-" {
+			continue
+		}
+		dict[ts.Id(fieldName)] = ts.Id(field.Variable.Name)
+	}
+	if len(dict) == 0 {
+		return ts.Values()
+	}
+	return ts.Values(dict)
+}
+
+func (gen *typescript) useType(typeName string) ts.Code {
+	gen.usedTypes[typeName] = struct{}{}
+	return ts.Id(typeName)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (typescript-client_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTypeScriptCookies checks, that cookies are sent by browser and not by forbidden header.
+func TestTypeScriptCookies(t *testing.T) {
+
+	tr, dir := testTransport(t, map[string]string{
+		"interfaces/interface.go": `package interfaces
+
+import "context"
+
+// @tg http-server
+type Files interface {
+
+	// @tg http-method=GET
+	// @tg http-path=/files/{id}
+	// @tg http-headers=token|X-Token
+	// @tg http-cookies=session|session
+	Get(ctx context.Context, id string, token string, session string) (name string, err error)
+
+	// @tg http-method=GET
+	// @tg http-path=/files
+	List(ctx context.Context) (names []string, err error)
+}
+`,
+	})
+	outDir := filepath.Join(dir, "web")
+	if err := tr.RenderTypeScript(outDir); err != nil {
+		t.Fatal(err)
+	}
+	generated, err := ioutil.ReadFile(filepath.Join(outDir, "files.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(generated)
+
+	if strings.Contains(src, "Cookie") {
+		t.Errorf("files.ts sets forbidden header 'Cookie':\n%s", src)
+	}
+	if strings.Count(src, `credentials: "include"`) != 1 {
+		t.Errorf("files.ts does not include credentials only to request with cookies:\n%s", src)
+	}
+	if !strings.Contains(src, "async get(id: string, token: string): Promise<") {
+		t.Errorf("files.ts has cookie arguments:\n%s", src)
+	}
+}
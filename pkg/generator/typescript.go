@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (typescript.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vetcher/go-astra/types"
+
+	"github.com/seniorGolang/tg/pkg/tags"
+	ts "github.com/seniorGolang/tg/pkg/typescript"
+	"github.com/seniorGolang/tg/pkg/utils"
+)
+
+type typescript struct {
+	*swagger
+
+	typeDefs  map[string]ts.Code
+	usedTypes map[string]struct{}
+}
+
+func newTypescript(tr *Transport) (gen *typescript) {
+
+	gen = &typescript{
+		swagger:  newSwagger(tr),
+		typeDefs: make(map[string]ts.Code),
+	}
+	return
+}
+
+func (gen *typescript) render(outDir string) (err error) {
+
+	if err = os.MkdirAll(outDir, 0777); err != nil {
+		return
+	}
+
+	for _, serviceName := range gen.serviceKeys() {
+
+		svc := gen.services[serviceName]
+
+		for _, method := range svc.methods {
+
+			if !method.isJsonRPC() && (!method.isHTTP() || method.tags.Contains(tagHandler)) {
+				continue
+			}
+
+			if method.isJsonRPC() {
+				gen.registerStruct(tsTypeName(method.requestStructName()), svc.pkgPath, method.tags, method.fieldsArgument())
+			} else if requestFields := method.arguments(); len(requestFields) != 0 {
+				gen.registerStruct(tsTypeName(method.requestStructName()), svc.pkgPath, method.tags, requestFields)
+			}
+			gen.registerStruct(tsTypeName(method.responseStructName()), svc.pkgPath, method.tags, method.results())
+		}
+		if err = gen.renderService(outDir, svc); err != nil {
+			return
+		}
+	}
+
+	if err = gen.renderClient(outDir); err != nil {
+		return
+	}
+	return gen.renderTypes(outDir)
+}
+
+func (gen *typescript) renderTypes(outDir string) (err error) {
+
+	srcFile := ts.NewFile()
+	srcFile.PackageComment(doNotEdit)
+
+	var typeNames []string
+	for typeName := range gen.typeDefs {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		srcFile.Line().NewLine().Export().Type().Id(typeName).E().Add(gen.typeDefs[typeName]).Op(";")
+	}
+
+	gen.log.Info("write to ", path.Join(outDir, "types.ts"))
+	return srcFile.Save(path.Join(outDir, "types.ts"))
+}
+
+func (gen *typescript) registerStruct(name, pkgPath string, mTags tags.DocTags, fields []types.StructField) {
+
+	gen.typeDefs[name] = ts.BlockFunc(func(bg *ts.Group) {
+		for _, field := range fields {
+			gen.structField(bg, pkgPath, field, mTags.Sub(field.Variable.Name))
+		}
+	})
+}
+
+func (gen *typescript) structField(bg *ts.Group, pkgPath string, field types.StructField, fieldTags tags.DocTags) {
+
+	fieldName := field.Name
+	if jsonTags, _ := field.Tags["json"]; len(jsonTags) > 0 && jsonTags[0] != "" {
+		fieldName = jsonTags[0]
+	}
+	if fieldName == "-" || fieldName == "" {
+		return
+	}
+
+	_, isPointer := field.Type.(types.TPointer)
+	_, omitEmpty := utils.SliceStringToMap(field.Tags["json"])["omitempty"]
+
+	stmt := bg.Id(fieldName)
+	if isPointer || omitEmpty {
+		stmt.Op("?")
+	}
+	stmt.T().Add(gen.walkType(pkgPath, field.Type, fieldTags)).Op(";")
+}
+
+func (gen *typescript) walkType(pkgPath string, varType types.Type, varTags tags.DocTags) ts.Code {
+
+	if newType := varTags.Value(tagType); newType != "" {
+		return ts.Id(tsBuiltin(newType))
+	}
+
+	switch varType.String() {
+	case "time.Time", "uuid.UUID", "[]byte", "json.RawMessage":
+		if varType.String() == "json.RawMessage" {
+			return ts.Id("any")
+		}
+		return ts.Id("string")
+	}
+
+	switch vType := varType.(type) {
+
+	case types.TName:
+
+		if types.IsBuiltin(varType) {
+			return ts.Id(tsBuiltin(vType.TypeName))
+		}
+		return gen.namedType(pkgPath, vType.TypeName)
+
+	case types.TImport:
+
+		return gen.namedType(vType.Import.Package, vType.Next.String())
+
+	case types.TMap:
+
+		return ts.Id("Record").Op("<").Add(gen.walkType(pkgPath, vType.Key, nil)).Op(", ").Add(gen.walkType(pkgPath, vType.Value, nil)).Op(">")
+
+	case types.TArray:
+
+		return ts.Id("Array").Op("<").Add(gen.walkType(pkgPath, vType.Next, nil)).Op(">")
+
+	case types.TEllipsis:
+
+		return ts.Id("Array").Op("<").Add(gen.walkType(pkgPath, vType.Next, nil)).Op(">")
+
+	case types.TPointer:
+
+		return gen.walkType(pkgPath, vType.Next, varTags)
+
+	case types.Struct:
+
+		return ts.BlockFunc(func(bg *ts.Group) {
+			for _, field := range vType.Fields {
+				if field.Name == "" || field.Name[:1] != strings.ToUpper(field.Name[:1]) {
+					continue
+				}
+				gen.structField(bg, pkgPath, field, tags.ParseTags(field.Docs))
+			}
+		})
+	}
+	return ts.Id("any")
+}
+
+func (gen *typescript) namedType(pkgPath, typeName string) ts.Code {
+
+	if _, found := gen.typeDefs[typeName]; !found {
+
+		gen.typeDefs[typeName] = ts.Id("any")
+
+		if nextType := gen.searchType(pkgPath, typeName); nextType != nil {
+			gen.typeDefs[typeName] = gen.walkType(pkgPath, nextType, nil)
+		}
+	}
+	if gen.usedTypes != nil {
+		gen.usedTypes[typeName] = struct{}{}
+	}
+	return ts.Id(typeName)
+}
+
+func (gen *typescript) argType(pkgPath string, method *method, arg types.Variable) ts.Code {
+
+	if method.isUploadVar(arg.Name) {
+		return ts.Id("Blob")
+	}
+	return gen.walkType(pkgPath, arg.Type, method.tags.Sub(arg.Name))
+}
+
+func tsBuiltin(typeName string) string {
+
+	switch typeName {
+	case "bool":
+		return "boolean"
+	case "string", "error":
+		return "string"
+	case "byte", "rune", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "number":
+		return "number"
+	}
+	return "any"
+}
+
+func tsTypeName(name string) string {
+	return utils.ToCamel(name)
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	. "github.com/dave/jennifer/jen"
@@ -40,6 +41,15 @@ func removeSkippedFields(fields []types.Variable, skipFields []string) []types.V
 	return result
 }
 
+func sortedKeys(m map[string]string) (keys []string) {
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
 func isContextFirst(fields []types.Variable) bool {
 	if len(fields) == 0 {
 		return false
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (watch.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Watch polls services package and packages of referenced types and calls render each time
+// when annotations, signatures or referenced types are changed. Blocks until ctx is done.
+func Watch(ctx context.Context, log logrus.FieldLogger, svcDir string, interval time.Duration, render func(tr Transport) error, options ...Option) (err error) {
+
+	var lastSum, lastState string
+
+	dirs := []string{svcDir}
+
+	for {
+		if state := dirsState(dirs); state != lastState {
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+
+			if dirsState(dirs) != state {
+				continue
+			}
+			lastState = state
+
+			var tr Transport
+			if tr, err = NewTransport(log, svcDir, options...); err != nil {
+				log.WithError(err).Warn("parse services")
+				continue
+			}
+
+			var sum string
+			sum, dirs = tr.fingerprint()
+			dirs = append([]string{svcDir}, dirs...)
+			lastState = dirsState(dirs)
+
+			if sum != lastSum {
+				lastSum = sum
+				log.Info("services changed, regenerate")
+				showError(log, render(tr), "render")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (tr Transport) fingerprint() (sum string, typeDirs []string) {
+
+	hash := sha256.New()
+	doc := newSwagger(&tr)
+
+	_, _ = fmt.Fprintln(hash, tr.tags)
+
+	for _, serviceName := range tr.serviceKeys() {
+
+		svc := tr.services[serviceName]
+		_, _ = fmt.Fprintln(hash, serviceName, svc.tags)
+
+		for _, method := range svc.methods {
+
+			_, _ = fmt.Fprintln(hash, method.Function.String(), method.tags)
+
+			doc.registerStruct(method.requestStructName(), svc.pkgPath, method.tags, method.argumentsWithUploads())
+			doc.registerStruct(method.responseStructName(), svc.pkgPath, method.tags, method.results())
+		}
+	}
+
+	schemas, _ := json.Marshal(doc.schemas)
+	_, _ = hash.Write(schemas)
+
+	for typeDir := range doc.typeDirs {
+		typeDirs = append(typeDirs, typeDir)
+	}
+	sort.Strings(typeDirs)
+	return hex.EncodeToString(hash.Sum(nil)), typeDirs
+}
+
+func dirsState(dirs []string) string {
+
+	var state strings.Builder
+
+	for _, dir := range dirs {
+		_ = filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+
+			if err != nil || info.IsDir() || !strings.HasSuffix(info.Name(), ".go") || strings.HasSuffix(info.Name(), "_test.go") {
+				return nil
+			}
+			_, _ = fmt.Fprintf(&state, "%s:%d:%d;", filePath, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+	}
+	return state.String()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Khramtsov Aleksei (contact@altsoftllc.com).
+// This file (watch_test.go at 14.05.2020, 2:13) is subject to the terms and
+// conditions defined in file 'LICENSE', which is part of this project source code.
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const watchBase = `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server log
+type Calc interface {
+	// Sum returns sum of arguments
+	// @tg summary=Sum
+	Sum(ctx context.Context, a, b int) (c int, err error)
+}
+`
+
+func TestFingerprint(t *testing.T) {
+
+	base := fingerprintOf(t, watchBase)
+
+	tests := []struct {
+		name    string
+		src     string
+		changed bool
+	}{
+		{name: "same source", src: watchBase},
+		{name: "comment without tags", changed: false, src: `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server log
+type Calc interface {
+	// Sum returns sum of both arguments, comment is edited
+	// @tg summary=Sum
+	Sum(ctx context.Context, a, b int) (c int, err error)
+}
+`},
+		{name: "method signature", changed: true, src: `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server log
+type Calc interface {
+	// Sum returns sum of arguments
+	// @tg summary=Sum
+	Sum(ctx context.Context, a, b int64) (c int64, err error)
+}
+`},
+		{name: "method tag", changed: true, src: `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server log
+type Calc interface {
+	// Sum returns sum of arguments
+	// @tg summary=Addition
+	Sum(ctx context.Context, a, b int) (c int, err error)
+}
+`},
+		{name: "interface tag", changed: true, src: `package interfaces
+
+import "context"
+
+// @tg jsonRPC-server http-server log
+type Calc interface {
+	// Sum returns sum of arguments
+	// @tg summary=Sum
+	Sum(ctx context.Context, a, b int) (c int, err error)
+}
+`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if sum := fingerprintOf(t, test.src); (sum != base) != test.changed {
+				t.Errorf("fingerprint changed = %v, want %v", sum != base, test.changed)
+			}
+		})
+	}
+}
+
+func fingerprintOf(t *testing.T, src string) (sum string) {
+
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module watchtest\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	svcDir := filepath.Join(dir, "interfaces")
+	if err := os.MkdirAll(svcDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(svcDir, "calc.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	tr, err := NewTransport(log, svcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, _ = tr.fingerprint()
+	return
+}
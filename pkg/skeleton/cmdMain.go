@@ -134,7 +134,7 @@ func renderMainFunc(meta metaInfo, basePkg string, services []types.Interface) C
 		for _, service := range services {
 			svcName := service.Name
 			g.Var().Id(svcName).Qual(pkgService, service.Name)
-			appArgs = append(appArgs, Qual(pkgTransport, "Service").Call(Qual(pkgTransport, "New"+svcName).Call(Id("log"), Id(svcName))))
+			appArgs = append(appArgs, Qual(pkgTransport, svcName).Call(Qual(pkgTransport, "New"+svcName).Call(Id("log"), Id(svcName))))
 		}
 
 		g.Line()
@@ -145,6 +145,17 @@ func renderMainFunc(meta metaInfo, basePkg string, services []types.Interface) C
 			g.Id("srv").Op(":=").Qual(pkgTransport, "New").Call(appArgs...)
 		}
 
+		if meta.withMongo {
+			g.Line().List(Id("mongoClient"), Err()).Op(":=").Qual(pkgMongo, "Connect").Call(Qual(pkgContext, "Background").Call(), Qual(pkgMongoOptions, "Client").Call().Dot("ApplyURI").Call(Qual(pkgConfig, "Mongo").Call().Dot("Address")))
+			g.Qual(pkgTransport, "ExitOnError").Call(Id("log"), Err(), Lit("connect to mongo"))
+			g.Defer().Func().Params().Block(
+				Id("_").Op("=").Id("mongoClient").Dot("Disconnect").Call(Qual(pkgContext, "Background").Call()),
+			).Call()
+			g.Id("srv").Dot("WithHealthCheck").Call(Lit("mongo"), Func().Params(Id("ctx").Qual(pkgContext, "Context")).Error().Block(
+				Return(Id("mongoClient").Dot("Ping").Call(Id("ctx"), Nil())),
+			))
+		}
+
 		g.Line()
 
 		serve := make(map[string]struct{})
@@ -165,18 +176,23 @@ func renderMainFunc(meta metaInfo, basePkg string, services []types.Interface) C
 		}
 
 		if _, found := serve["http-server"]; found {
-			g.Id("srv").Dot("ServeHTTP").Call(Qual(pkgConfig, "Service").Call().Dot("ServiceBind"))
+			g.Qual(pkgTransport, "ExitOnError").Call(Id("log"), Id("srv").Dot("ServeHTTP").Call(Qual(pkgConfig, "Service").Call().Dot("ServiceBind")), Lit("serve http"))
 		}
 
-		g.Line().Qual(pkgTransport, "ServeMetrics").Call(Id("log"), Qual(pkgConfig, "Service").Call().Dot("MetricsBind"))
+		g.Line().Qual(pkgTransport, "ExitOnError").Call(Id("log"), Id("srv").Dot("ServeHealth").Call(Qual(pkgConfig, "Service").Call().Dot("HealthBind")), Lit("serve health"))
+		g.Qual(pkgTransport, "ExitOnError").Call(Id("log"), Id("srv").Dot("ServeMetrics").Call(Qual(pkgConfig, "Service").Call().Dot("MetricsBind")), Lit("serve metrics"))
 		g.If(Qual(pkgConfig, "Service").Call().Dot("EnablePPROF")).Block(
-			Id("srv").Dot("ServePPROF").Call(Qual(pkgConfig, "Service").Call().Dot("PprofBind")),
+			Qual(pkgTransport, "ExitOnError").Call(Id("log"), Id("srv").Dot("ServePPROF").Call(Qual(pkgConfig, "Service").Call().Dot("PprofBind")), Lit("serve PPROF")),
 		)
 
 		g.Line().Op("<-").Id("shutdown")
 
 		g.Line().Id("log").Dot("Info").Call(Lit("shutdown application"))
-		g.Id("srv").Dot("Shutdown").Call()
+		g.List(Id("ctx"), Id("cancel")).Op(":=").Qual(pkgContext, "WithTimeout").Call(Qual(pkgContext, "Background").Call(), Qual(pkgConfig, "Service").Call().Dot("ShutdownTimeout"))
+		g.Defer().Id("cancel").Call()
+		g.If(Err().Op(":=").Id("srv").Dot("Shutdown").Call(Id("ctx")).Op(";").Err().Op("!=").Nil()).Block(
+			Id("log").Dot("WithError").Call(Err()).Dot("Error").Call(Lit("shutdown application")),
+		)
 	})
 }
 
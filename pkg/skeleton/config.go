@@ -44,6 +44,7 @@ func renderServiceConfig(configPath string) (err error) {
 		g.Id("HealthBind").String().Tag(map[string]string{"envconfig": "BIND_HEALTH", "default": ":9091"})
 		g.Id("MetricsBind").String().Tag(map[string]string{"envconfig": "BIND_METRICS", "default": ":9090"})
 		g.Id("EnablePPROF").Bool().Tag(map[string]string{"envconfig": "ENABLE_PPROF", "default": "false"})
+		g.Id("ShutdownTimeout").Qual(pkgTime, "Duration").Tag(map[string]string{"envconfig": "SHUTDOWN_TIMEOUT", "default": "30s"})
 	})
 
 	srcFile.Line().Var().Id("service").Op("*").Id("ServiceConfig")
@@ -4,10 +4,13 @@
 package skeleton
 
 const (
-	pkgOS      = "os"
-	pkgContext = "context"
-	pkgSignal  = "os/signal"
-	pkgSyscall = "syscall"
-	pkgLog     = "github.com/sirupsen/logrus"
-	pkgEnv     = "github.com/kelseyhightower/envconfig"
+	pkgOS           = "os"
+	pkgTime         = "time"
+	pkgContext      = "context"
+	pkgSignal       = "os/signal"
+	pkgSyscall      = "syscall"
+	pkgLog          = "github.com/sirupsen/logrus"
+	pkgEnv          = "github.com/kelseyhightower/envconfig"
+	pkgMongo        = "go.mongodb.org/mongo-driver/mongo"
+	pkgMongoOptions = "go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -51,7 +51,7 @@ func (d Dict) render(f *File, w io.Writer, s *Statement) error {
 		if err := k.render(f, w, nil); err != nil {
 			return err
 		}
-		if _, err := w.Write([]byte(":")); err != nil {
+		if _, err := w.Write([]byte(": ")); err != nil {
 			return err
 		}
 		if err := v.render(f, w, nil); err != nil {
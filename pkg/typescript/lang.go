@@ -46,7 +46,7 @@ func (s *Statement) List(items ...Code) *Statement {
 		multi:     false,
 		name:      "list",
 		open:      "",
-		separator: ",",
+		separator: ", ",
 	}
 	*s = append(*s, g)
 	return s
@@ -71,7 +71,7 @@ func (s *Statement) ListFunc(f func(*Group)) *Statement {
 		multi:     false,
 		name:      "list",
 		open:      "",
-		separator: ",",
+		separator: ", ",
 	}
 	f(g)
 	*s = append(*s, g)
@@ -98,7 +98,7 @@ func (s *Statement) Values(values ...Code) *Statement {
 		multi:     false,
 		name:      "values",
 		open:      "{",
-		separator: ",",
+		separator: ", ",
 	}
 	*s = append(*s, g)
 	return s
@@ -123,7 +123,7 @@ func (s *Statement) ValuesFunc(f func(*Group)) *Statement {
 		multi:     false,
 		name:      "values",
 		open:      "{",
-		separator: ",",
+		separator: ", ",
 	}
 	f(g)
 	*s = append(*s, g)
@@ -306,7 +306,7 @@ func (s *Statement) Call(params ...Code) *Statement {
 		multi:     false,
 		name:      "call",
 		open:      "(",
-		separator: ",",
+		separator: ", ",
 	}
 	*s = append(*s, g)
 	return s
@@ -331,7 +331,7 @@ func (s *Statement) CallFunc(f func(*Group)) *Statement {
 		multi:     false,
 		name:      "call",
 		open:      "(",
-		separator: ",",
+		separator: ", ",
 	}
 	f(g)
 	*s = append(*s, g)
@@ -358,7 +358,7 @@ func (s *Statement) Params(params ...Code) *Statement {
 		multi:     false,
 		name:      "params",
 		open:      "(",
-		separator: ",",
+		separator: ", ",
 	}
 	*s = append(*s, g)
 	return s
@@ -383,7 +383,7 @@ func (s *Statement) ParamsFunc(f func(*Group)) *Statement {
 		multi:     false,
 		name:      "params",
 		open:      "(",
-		separator: ",",
+		separator: ", ",
 	}
 	f(g)
 	*s = append(*s, g)
@@ -2109,3 +2109,69 @@ func (s *Statement) Catch() *Statement {
 	*s = append(*s, t)
 	return s
 }
+
+// Async renders the async keyword.
+func Async() *Statement {
+	return newStatement().Async()
+}
+
+// Async renders the async keyword.
+func (g *Group) Async() *Statement {
+	s := Async()
+	g.items = append(g.items, s)
+	return s
+}
+
+// Async renders the async keyword.
+func (s *Statement) Async() *Statement {
+	t := token{
+		content: "async",
+		typ:     keywordToken,
+	}
+	*s = append(*s, t)
+	return s
+}
+
+// Await renders the await keyword.
+func Await() *Statement {
+	return newStatement().Await()
+}
+
+// Await renders the await keyword.
+func (g *Group) Await() *Statement {
+	s := Await()
+	g.items = append(g.items, s)
+	return s
+}
+
+// Await renders the await keyword.
+func (s *Statement) Await() *Statement {
+	t := token{
+		content: "await",
+		typ:     keywordToken,
+	}
+	*s = append(*s, t)
+	return s
+}
+
+// Class renders the class keyword.
+func Class() *Statement {
+	return newStatement().Class()
+}
+
+// Class renders the class keyword.
+func (g *Group) Class() *Statement {
+	s := Class()
+	g.items = append(g.items, s)
+	return s
+}
+
+// Class renders the class keyword.
+func (s *Statement) Class() *Statement {
+	t := token{
+		content: "class",
+		typ:     keywordToken,
+	}
+	*s = append(*s, t)
+	return s
+}
@@ -40,12 +40,12 @@ func (t token) render(f *File, w io.Writer, s *Statement) error {
 		var out string
 		switch t.content.(type) {
 		case string:
-			out = fmt.Sprintf(`"%s" `, t.content)
+			out = strconv.Quote(t.content.(string))
 		case bool, int, complex128:
 			// default constant types can be left bare
-			out = fmt.Sprintf("%v ", t.content)
+			out = fmt.Sprintf("%v", t.content)
 		case float64:
-			out = fmt.Sprintf("%v ", t.content)
+			out = fmt.Sprintf("%v", t.content)
 			if !strings.Contains(out, ".") && !strings.Contains(out, "e") {
 				out += ".0"
 			}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
 )
 
@@ -60,17 +61,86 @@ func (f *File) Render(w io.Writer) error {
 	if _, err := source.Write(body.Bytes()); err != nil {
 		return err
 	}
-	if _, err := w.Write(source.Bytes()); err != nil {
+	if _, err := w.Write(indent(source.Bytes())); err != nil {
 		return err
 	}
 	return nil
 }
 
+// indent re-indents rendered source by brackets depth, because items of multiline groups are rendered without any padding.
+func indent(src []byte) []byte {
+
+	var quote byte
+	var levels []int
+	var blank bool
+
+	out := &bytes.Buffer{}
+	for _, line := range strings.Split(strings.TrimSpace(string(src)), "\n") {
+
+		if line = strings.TrimSpace(line); line == "" {
+			if !blank {
+				out.WriteString("\n")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+
+		var opened bool
+		padding := -1
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			if padding < 0 && (quote != 0 || strings.IndexByte("}])", c) < 0) {
+				padding = len(levels)
+			}
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'' || c == '`':
+				quote = c
+			case c == '/' && i+1 < len(line) && line[i+1] == '/':
+				i = len(line)
+			case strings.IndexByte("{[(", c) >= 0:
+				if !opened {
+					levels = append(levels, 0)
+					opened = true
+				}
+				levels[len(levels)-1]++
+			case strings.IndexByte("}])", c) >= 0 && len(levels) != 0:
+				if levels[len(levels)-1]--; levels[len(levels)-1] == 0 {
+					levels = levels[:len(levels)-1]
+					opened = false
+				}
+			}
+		}
+		if quote != '`' {
+			quote = 0
+		}
+		if padding < 0 {
+			padding = len(levels)
+		}
+		out.WriteString(strings.Repeat("    ", padding))
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	return out.Bytes()
+}
+
 func (f *File) renderImports(source io.Writer) (err error) {
 
+	var froms []string
+	for from := range f.imports {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
 	// import { JSONRPCRequest, IResponse } from '@mihanizm56/fetch-api';
-	for from, def := range f.imports {
-		if _, err := fmt.Fprintf(source, "import {%s} from '%s';\n", strings.Join(def.items, ","), from); err != nil {
+	for _, from := range froms {
+		if _, err := fmt.Fprintf(source, "import {%s} from '%s';\n", strings.Join(f.imports[from].items, ", "), from); err != nil {
 			return err
 		}
 	}